@@ -0,0 +1,127 @@
+package archive
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ZiaoLiu-1/pskill/internal/store"
+)
+
+// MaxFileSize caps a single extracted file so a hostile archive cannot fill
+// the disk.
+const MaxFileSize = 16 << 20
+
+// WriteTarGz writes the regular files under dir as a gzipped tarball with
+// paths relative to dir. VCS metadata and pskill's bookkeeping files are
+// skipped.
+func WriteTarGz(w io.Writer, dir string) error {
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if d.Name() == ".git" {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		rel, _ := filepath.Rel(dir, path)
+		if !d.Type().IsRegular() || store.IsBookkeeping(rel) {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		hdr := &tar.Header{
+			Name:    filepath.ToSlash(rel),
+			Mode:    int64(info.Mode().Perm()),
+			Size:    info.Size(),
+			ModTime: info.ModTime(),
+		}
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+		f, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		_, err = io.Copy(tw, f)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	return gz.Close()
+}
+
+// ExtractTarGz unpacks a gzipped tarball into dest. Entries that would land
+// outside dest, links and oversized files are rejected; bookkeeping files
+// are dropped so a payload cannot pose as pskill's own records.
+func ExtractTarGz(r io.Reader, dest string) error {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return fmt.Errorf("open archive: %w", err)
+	}
+	defer gz.Close()
+	if err := os.MkdirAll(dest, 0o755); err != nil {
+		return err
+	}
+	root, err := filepath.Abs(dest)
+	if err != nil {
+		return err
+	}
+	tr := tar.NewReader(gz)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("read archive: %w", err)
+		}
+		target := filepath.Join(root, filepath.FromSlash(hdr.Name))
+		if target != root && !strings.HasPrefix(target, root+string(filepath.Separator)) {
+			return fmt.Errorf("archive entry %q escapes destination", hdr.Name)
+		}
+		if store.IsBookkeeping(hdr.Name) {
+			continue
+		}
+		switch hdr.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(target, 0o755); err != nil {
+				return err
+			}
+		case tar.TypeReg:
+			if hdr.Size > MaxFileSize {
+				return fmt.Errorf("archive entry %q is too large", hdr.Name)
+			}
+			if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+				return err
+			}
+			f, err := os.OpenFile(target, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0o644)
+			if err != nil {
+				return err
+			}
+			_, err = io.Copy(f, io.LimitReader(tr, MaxFileSize))
+			f.Close()
+			if err != nil {
+				return err
+			}
+		default:
+			return fmt.Errorf("archive entry %q: unsupported type", hdr.Name)
+		}
+	}
+}
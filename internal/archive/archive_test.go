@@ -0,0 +1,73 @@
+package archive
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRoundTrip(t *testing.T) {
+	src := t.TempDir()
+	os.MkdirAll(filepath.Join(src, "scripts"), 0o755)
+	os.MkdirAll(filepath.Join(src, ".git"), 0o755)
+	os.WriteFile(filepath.Join(src, "SKILL.md"), []byte("# hi\n"), 0o644)
+	os.WriteFile(filepath.Join(src, "scripts", "run.sh"), []byte("echo\n"), 0o644)
+	os.WriteFile(filepath.Join(src, ".git", "HEAD"), []byte("ref\n"), 0o644)
+
+	var buf bytes.Buffer
+	if err := WriteTarGz(&buf, src); err != nil {
+		t.Fatal(err)
+	}
+	dest := t.TempDir()
+	if err := ExtractTarGz(&buf, dest); err != nil {
+		t.Fatal(err)
+	}
+	if raw, _ := os.ReadFile(filepath.Join(dest, "scripts", "run.sh")); string(raw) != "echo\n" {
+		t.Errorf("nested file not restored: %q", raw)
+	}
+	if _, err := os.Stat(filepath.Join(dest, ".git")); err == nil {
+		t.Error(".git should not be archived")
+	}
+}
+
+func TestExtract_DropsBookkeeping(t *testing.T) {
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	for _, name := range []string{"SKILL.md", ".pskill-meta.json", "./.pskill-scan.json", "scripts/.pskill-setup.sh"} {
+		tw.WriteHeader(&tar.Header{Name: name, Mode: 0o644, Size: 1, Typeflag: tar.TypeReg})
+		tw.Write([]byte("x"))
+	}
+	tw.Close()
+	gz.Close()
+
+	dest := t.TempDir()
+	if err := ExtractTarGz(&buf, dest); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{".pskill-meta.json", ".pskill-scan.json"} {
+		if _, err := os.Stat(filepath.Join(dest, name)); err == nil {
+			t.Errorf("%s should not be extracted from a payload", name)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(dest, "scripts", ".pskill-setup.sh")); err != nil {
+		t.Errorf("nested file dropped: %v", err)
+	}
+}
+
+func TestExtract_RejectsTraversal(t *testing.T) {
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	tw.WriteHeader(&tar.Header{Name: "../evil", Mode: 0o644, Size: 1, Typeflag: tar.TypeReg})
+	tw.Write([]byte("x"))
+	tw.Close()
+	gz.Close()
+
+	if err := ExtractTarGz(&buf, t.TempDir()); err == nil {
+		t.Fatal("expected traversal to be rejected")
+	}
+}
@@ -1,8 +1,12 @@
 package cli
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
+	"path"
 	"path/filepath"
 	"strings"
 
@@ -10,110 +14,382 @@ import (
 
 	"github.com/ZiaoLiu-1/pskill/internal/adapter"
 	"github.com/ZiaoLiu-1/pskill/internal/config"
+	"github.com/ZiaoLiu-1/pskill/internal/installer"
 	"github.com/ZiaoLiu-1/pskill/internal/monitor"
 	"github.com/ZiaoLiu-1/pskill/internal/project"
 	"github.com/ZiaoLiu-1/pskill/internal/registry"
 	"github.com/ZiaoLiu-1/pskill/internal/search"
+	"github.com/ZiaoLiu-1/pskill/internal/skill"
+	"github.com/ZiaoLiu-1/pskill/internal/source"
 	"github.com/ZiaoLiu-1/pskill/internal/store"
+	"github.com/ZiaoLiu-1/pskill/internal/tui"
 )
 
 func newAddCmd() *cobra.Command {
 	var cliTargets string
 	var projectScope bool
+	var listOnly bool
+	var pick string
+	var all bool
+	var ref string
+	var asJSON bool
 
 	cmd := &cobra.Command{
-		Use:   "add <skill-name>",
+		Use:   "add <skill-name|repo-spec|path>",
 		Short: "Install a skill to store and selected CLIs",
-		Args:  cobra.ExactArgs(1),
+		Long: "Install a skill by registry name, or install one or more skills from a git repository. Repositories are given as a URL or as " +
+			"github:user/repo[/path][@ref], git+https://host/repo.git[//subdir][@ref] or git+file:///path/repo.git[//subdir][@ref]. " +
+			"A registry name may be pinned to a published version as name@version. " +
+			"For repositories, --list shows every SKILL.md found and --pick or an interactive checklist selects which to install. " +
+			"A local directory (./path/to/skill) is copied into the store; use `pskill link` to keep it live instead.",
+		Args: cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			skillName := args[0]
 			cfg, err := config.LoadGlobal()
 			if err != nil {
 				return err
 			}
-
-			st := store.NewManager(cfg.StoreDir)
-			destPath, exists, err := st.EnsureSkillDir(skillName)
-			if err != nil {
-				return err
-			}
-
-			if !exists {
-				client := registry.NewClient(cfg.RegistryURL, cfg.CacheDir, cfg.RegistryAPIKey)
-				// Search for the skill to get its GitHub URL
-				results, _, _ := client.Search(skillName, 1, 1, "stars")
-				githubURL := ""
-				for _, r := range results {
-					if r.Name == skillName {
-						githubURL = r.GithubURL
-						break
-					}
-				}
-				if err := client.DownloadSkill(skillName, githubURL, destPath); err != nil {
-					return err
-				}
-			}
-
 			targets := cfg.TargetCLIs
 			if cliTargets != "" {
 				targets = strings.Split(cliTargets, ",")
 			}
-			adapters := adapter.All()
-			for _, t := range targets {
-				ad, ok := adapters[strings.TrimSpace(t)]
-				if !ok {
-					continue
+
+			if source.IsRepoURL(args[0]) {
+				spec, err := source.ParseSpec(args[0])
+				if err != nil {
+					return err
+				}
+				if ref != "" {
+					spec.Ref = ref
 				}
-				if err := st.LinkSkillToCLI(skillName, ad.SkillDir()); err != nil {
-					fmt.Fprintf(os.Stderr, "warn: unable to link %s to %s: %v\n", skillName, ad.Name(), err)
+				names, err := addFromRepo(cmd.Context(), cfg, spec, listOnly, asJSON, pick, all)
+				if err != nil || len(names) == 0 {
+					return err
 				}
+				return finishInstall(cfg, vetInstalled(cfg, names), targets, projectScope)
 			}
 
-			engine := search.NewEngine(cfg.IndexDir)
-			_ = engine.IndexSkillByPath(skillName, destPath)
-
-			scope := "global"
-			if projectScope {
-				wd, _ := os.Getwd()
-				manifest, err := project.Load(wd)
+			if isLocalPath(args[0]) {
+				name, err := addFromPath(cfg, args[0])
 				if err != nil {
-					name := filepath.Base(wd)
-					if name == "" || name == "." || name == "/" {
-						name = "project"
-					}
-					manifest = project.Manifest{Name: name, TargetCLIs: targets}
-				}
-				manifest.Installed = appendIfMissing(manifest.Installed, skillName)
-				manifest.TargetCLIs = targets
-				_ = project.Save(wd, manifest)
-				scope = "project"
-			}
-			// Record usage event
-			if tr, err := monitor.NewTracker(cfg.StatsDB); err == nil {
-				cliName := "global"
-				if len(targets) > 0 {
-					cliName = targets[0]
+					return err
 				}
-				wd, _ := os.Getwd()
-				_ = tr.Record(monitor.Event{
-					SkillName: skillName,
-					CLI:       cliName,
-					Project:   filepath.Base(wd),
-					EventType: "install",
-				})
-				tr.Close()
+				return finishInstall(cfg, vetInstalled(cfg, []string{name}), targets, projectScope)
 			}
 
-			fmt.Fprintf(os.Stdout, "Installed %s (%s)\n", skillName, scope)
-			return nil
+			names, err := addFromRegistry(cmd.Context(), cfg, args[0])
+			if err != nil {
+				return err
+			}
+			return finishInstall(cfg, names, targets, projectScope)
 		},
 	}
 
 	cmd.Flags().StringVar(&cliTargets, "cli", "", "comma-separated target CLIs")
 	cmd.Flags().BoolVar(&projectScope, "project", false, "mark skill for current project")
+	cmd.Flags().BoolVar(&listOnly, "list", false, "list skills in a repository without installing")
+	cmd.Flags().StringVar(&pick, "pick", "", "comma-separated skills to install from a repository")
+	cmd.Flags().BoolVar(&all, "all", false, "install every skill in a repository")
+	cmd.Flags().StringVar(&ref, "ref", "", "branch or tag to install from a repository")
+	cmd.Flags().BoolVar(&asJSON, "json", false, "output --list as JSON")
 	return cmd
 }
 
+// addFromRegistry puts a registry skill in the store and returns the names
+// safe to link. Scoped names (@acme/pdf) resolve from the owning registry
+// but are stored under the bare name. A version (pdf@1.2.0) pins the
+// download, and replaces a stored copy of another version after keeping it
+// in the history.
+func addFromRegistry(ctx context.Context, cfg config.Config, arg string) ([]string, error) {
+	spec, version := registry.SplitVersion(arg)
+	if version != "" && !skill.ValidVersion(version) {
+		return nil, fmt.Errorf("invalid version %q in %s", version, arg)
+	}
+	skillName := registry.BareName(spec)
+	st := store.NewManager(cfg.StoreDir)
+	if st.IsQuarantined(skillName) {
+		return nil, fmt.Errorf("%s is quarantined pending review; see `pskill info %s`, then `pskill approve %s`", skillName, skillName, skillName)
+	}
+	destPath, exists, err := st.EnsureSkillDir(skillName)
+	if err != nil {
+		return nil, err
+	}
+
+	// Offline, a skill already in the store installs as usual and
+	// anything else resolves only from cached registry metadata.
+	if !exists {
+		if err := downloadToStore(ctx, cfg, arg, "", destPath); err != nil {
+			_ = os.RemoveAll(destPath)
+			if errors.Is(err, registry.ErrOffline) {
+				return nil, fmt.Errorf("%s is not in the store and cannot be downloaded offline", skillName)
+			}
+			return nil, err
+		}
+		return vetInstalled(cfg, []string{skillName}), nil
+	}
+	// An entry left by an earlier add that died before its scan is vetted
+	// again rather than linked as is.
+	meta, _ := st.ReadMeta(skillName)
+	if version == "" || meta.Version == version {
+		return vetInstalled(cfg, []string{skillName}), nil
+	}
+	if meta.SourceType != store.SourceRegistry {
+		return nil, fmt.Errorf("%s is installed from %s, not a registry; remove it before pinning %s", skillName, meta.SourceURL, version)
+	}
+	fmt.Printf("Replacing %s %s with %s\n", skillName, meta.Version, version)
+	snapshot(st, skillName)
+	if err := downloadToStore(ctx, cfg, skillName+"@"+version, meta.Registry, destPath); err != nil {
+		return nil, fmt.Errorf("install %s %s: %w", skillName, version, err)
+	}
+	return vetInstalled(cfg, []string{skillName}), nil
+}
+
+// addFromRepo fetches a repository, selects skills from the spec's subtree
+// and copies them into the store. It returns the installed skill names.
+func addFromRepo(ctx context.Context, cfg config.Config, spec source.Spec, listOnly, asJSON bool, pick string, all bool) ([]string, error) {
+	git := source.NewGit(cfg.CacheDir).SetOffline(cfg.Offline)
+	dir, err := git.Fetch(ctx, spec.URL, spec.Ref)
+	if err != nil {
+		return nil, err
+	}
+	commit, err := git.Head(dir)
+	if err != nil {
+		return nil, err
+	}
+	repoURL := spec.String()
+	root := filepath.Join(dir, filepath.FromSlash(spec.Subdir))
+	if _, err := os.Stat(root); err != nil {
+		return nil, fmt.Errorf("%s not found in %s", spec.Subdir, spec.URL)
+	}
+	found, err := source.FindSkills(root)
+	if err != nil {
+		return nil, err
+	}
+	if len(found) == 0 {
+		return nil, fmt.Errorf("no SKILL.md found in %s", repoURL)
+	}
+	for i := range found {
+		found[i].Subdir = path.Join(spec.Subdir, found[i].Subdir)
+	}
+
+	if listOnly {
+		if asJSON {
+			out, _ := json.MarshalIndent(found, "", "  ")
+			fmt.Println(string(out))
+			return nil, nil
+		}
+		for _, f := range found {
+			fmt.Printf("%-32s %s\n", f.Name, f.Description)
+		}
+		return nil, nil
+	}
+
+	var selected []source.Found
+	switch {
+	case pick != "":
+		var missing []string
+		selected, missing = source.Pick(found, strings.Split(pick, ","))
+		if len(missing) > 0 {
+			return nil, fmt.Errorf("not found in %s: %s", repoURL, strings.Join(missing, ", "))
+		}
+	case all || len(found) == 1:
+		selected = found
+	case isTerminal():
+		items := make([]tui.ChecklistItem, 0, len(found))
+		for _, f := range found {
+			items = append(items, tui.ChecklistItem{Label: f.Name, Detail: f.Description})
+		}
+		idx, err := tui.RunChecklist(fmt.Sprintf("Skills in %s", repoURL), items)
+		if err != nil {
+			return nil, err
+		}
+		for _, i := range idx {
+			selected = append(selected, found[i])
+		}
+	default:
+		return nil, fmt.Errorf("%s has %d skills; use --list, --pick or --all", repoURL, len(found))
+	}
+
+	st := store.NewManager(cfg.StoreDir)
+	names := make([]string, 0, len(selected))
+	for _, f := range selected {
+		if _, err := st.ImportDir(f.Name, f.Dir); err != nil {
+			return names, fmt.Errorf("import %s: %w", f.Name, err)
+		}
+		meta := store.Meta{SourceType: store.SourceGit, SourceURL: spec.URL, Ref: spec.Ref, Subdir: f.Subdir, Commit: commit, InstalledBy: invokedAs}
+		if err := st.WriteMeta(f.Name, st.Stamp(f.Name, meta)); err != nil {
+			fmt.Fprintf(os.Stderr, "warn: unable to record source for %s: %v\n", f.Name, err)
+		}
+		names = append(names, f.Name)
+	}
+	fmt.Printf("Resolved %s to %s\n", repoURL, shortCommit(commit))
+	return names, nil
+}
+
+// addFromPath copies a skill directory on disk into the store and records
+// the directory as its source so `pskill update` can copy it again.
+func addFromPath(cfg config.Config, dir string) (string, error) {
+	abs, err := resolvePath(dir)
+	if err != nil {
+		return "", err
+	}
+	name := filepath.Base(abs)
+	st := store.NewManager(cfg.StoreDir)
+	if _, err := st.ImportDir(name, abs); err != nil {
+		return "", fmt.Errorf("import %s: %w", abs, err)
+	}
+	if err := st.WriteMeta(name, st.Stamp(name, store.Meta{SourceType: store.SourceLocal, SourceURL: abs, InstalledBy: invokedAs})); err != nil {
+		fmt.Fprintf(os.Stderr, "warn: unable to record source for %s: %v\n", name, err)
+	}
+	return name, nil
+}
+
+// resolvePath makes a user-supplied path absolute, expanding a leading "~/".
+func resolvePath(p string) (string, error) {
+	if strings.HasPrefix(p, "~/") {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", err
+		}
+		p = filepath.Join(home, p[2:])
+	}
+	return filepath.Abs(p)
+}
+
+// isLocalPath reports whether arg names a directory on disk rather than a
+// registry skill.
+func isLocalPath(arg string) bool {
+	if arg == "." || arg == ".." || strings.HasPrefix(arg, "./") || strings.HasPrefix(arg, "../") ||
+		strings.HasPrefix(arg, "/") || strings.HasPrefix(arg, "~/") || filepath.IsAbs(arg) {
+		return true
+	}
+	if strings.ContainsRune(arg, filepath.Separator) {
+		fi, err := os.Stat(arg)
+		return err == nil && fi.IsDir()
+	}
+	return false
+}
+
+// finishInstall links stored skills into the target CLIs, indexes them,
+// updates pskill.yaml when requested and records the install event.
+func finishInstall(cfg config.Config, names []string, targets []string, projectScope bool) error {
+	st := store.NewManager(cfg.StoreDir)
+	wd, _ := os.Getwd()
+	if err := checkBudget(cfg, wd, targets, names); err != nil {
+		return err
+	}
+
+	adapters := adapter.All()
+	for _, skillName := range names {
+		for _, t := range targets {
+			ad, ok := adapters[strings.TrimSpace(t)]
+			if !ok {
+				continue
+			}
+			if err := st.LinkSkillToCLI(skillName, ad.SkillDir()); err != nil {
+				fmt.Fprintf(os.Stderr, "warn: unable to link %s to %s: %v\n", skillName, ad.Name(), err)
+			}
+		}
+	}
+
+	scope := "global"
+	if projectScope {
+		manifest, err := project.Load(wd)
+		if err != nil {
+			name := filepath.Base(wd)
+			if name == "" || name == "." || name == "/" {
+				name = "project"
+			}
+			manifest = project.Manifest{Name: name, TargetCLIs: targets}
+		}
+		for _, skillName := range names {
+			manifest.Installed = appendIfMissing(manifest.Installed, skillName)
+			_ = st.SetProject(skillName, wd, true)
+			for _, t := range targets {
+				localDir := installer.ProjectCLISkillDir(wd, strings.TrimSpace(t))
+				if _, err := st.LinkSkillToProject(skillName, localDir, manifest.Vars, false); err != nil {
+					fmt.Fprintf(os.Stderr, "warn: unable to link %s to %s (project): %v\n", skillName, t, err)
+				}
+			}
+		}
+		manifest.TargetCLIs = targets
+		_ = project.Save(wd, manifest)
+		scope = "project"
+	}
+	// Index last so the search facets see the links and project.
+	dirs := map[string]string{}
+	for _, skillName := range names {
+		dirs[skillName] = filepath.Join(cfg.StoreDir, skillName)
+	}
+	_, _ = search.NewStoreEngine(cfg.IndexDir, cfg.StoreDir).IndexSkillsByPath(dirs)
+	// Record usage event
+	if tr, err := monitor.NewTracker(cfg.StatsDB); err == nil {
+		cliName := "global"
+		if len(targets) > 0 {
+			cliName = targets[0]
+		}
+		for _, skillName := range names {
+			_ = tr.Record(monitor.Event{
+				SkillName: skillName,
+				CLI:       cliName,
+				Project:   filepath.Base(wd),
+				EventType: "install",
+			})
+		}
+		tr.Close()
+	}
+
+	for _, skillName := range names {
+		fmt.Fprintf(os.Stdout, "Installed %s (%s)\n", skillName, scope)
+	}
+	return nil
+}
+
+// downloadToStore resolves a skill by exact name across the configured
+// registries, or in registryName only when set, and downloads it into
+// destPath. A name@version specifier downloads that version.
+func downloadToStore(ctx context.Context, cfg config.Config, skillName, registryName, destPath string) error {
+	skillName, version := registry.SplitVersion(skillName)
+	reg := registry.FromConfig(cfg)
+	var result registry.SkillResult
+	var err error
+	if registryName != "" {
+		result, err = reg.GetFrom(ctx, registryName, skillName)
+	} else {
+		result, err = reg.Get(ctx, skillName)
+	}
+	if err != nil {
+		return fmt.Errorf("resolve %s: %w", skillName, err)
+	}
+	if result, err = registry.AtVersion(result, version); err != nil {
+		return err
+	}
+	if err := reg.Fetch(ctx, result, destPath); err != nil {
+		return err
+	}
+	if result.Registry != "" {
+		fmt.Printf("Fetched %s from %s\n", result.Name, result.Registry)
+	}
+	st := store.NewManager(cfg.StoreDir)
+	meta := store.Meta{
+		SourceType:  store.SourceRegistry,
+		SourceURL:   result.GithubURL,
+		Registry:    result.Registry,
+		ID:          result.ID,
+		Version:     result.Version,
+		Author:      result.Author,
+		InstalledBy: invokedAs,
+	}
+	_ = st.WriteMeta(registry.BareName(skillName), st.Stamp(registry.BareName(skillName), meta))
+	return nil
+}
+
+func shortCommit(commit string) string {
+	if len(commit) > 12 {
+		return commit[:12]
+	}
+	return commit
+}
+
 func appendIfMissing(items []string, item string) []string {
 	for _, it := range items {
 		if it == item {
@@ -0,0 +1,154 @@
+package cli
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+
+	"github.com/ZiaoLiu-1/pskill/internal/config"
+	"github.com/ZiaoLiu-1/pskill/internal/installer"
+	"github.com/ZiaoLiu-1/pskill/internal/security"
+	"github.com/ZiaoLiu-1/pskill/internal/store"
+)
+
+func newApproveCmd() *cobra.Command {
+	var cliTargets string
+	var projectScope bool
+	var reject bool
+	var yes bool
+	cmd := &cobra.Command{
+		Use:   "approve [skill]",
+		Short: "Review a quarantined skill and link it",
+		Long: "Skills are scanned when they are installed or updated. One with high or medium findings (piping downloads to a " +
+			"shell, sending credentials over the network, hidden Unicode, prompt injection, executables or large binaries) is " +
+			"kept in quarantine and not linked. Without arguments, list quarantined skills; with one, show its findings and " +
+			"link it after confirmation, or delete it with --reject. Skills quarantined because the trust policy denies their " +
+			"signature cannot be approved; sign them or change the policy.",
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := config.LoadGlobal()
+			if err != nil {
+				return err
+			}
+			st := store.NewManager(cfg.StoreDir)
+			if len(args) == 0 {
+				names, err := st.ListQuarantined()
+				if err != nil {
+					return err
+				}
+				if len(names) == 0 {
+					fmt.Println("No skills in quarantine")
+					return nil
+				}
+				for _, name := range names {
+					summary := "no report"
+					if rep, err := installer.QuarantineReport(cfg, name); err == nil {
+						summary = rep.Summary()
+					}
+					fmt.Printf("%-32s %s\n", name, summary)
+				}
+				return nil
+			}
+
+			name := args[0]
+			if !st.IsQuarantined(name) {
+				return fmt.Errorf("%s is not in quarantine", name)
+			}
+			if reject {
+				if err := st.RemoveQuarantined(name); err != nil {
+					return err
+				}
+				fmt.Printf("Deleted quarantined %s\n", name)
+				return nil
+			}
+			rep, err := installer.QuarantineReport(cfg, name)
+			if err == nil {
+				printFindings(name, rep)
+			}
+			if !yes {
+				ok, err := confirm(fmt.Sprintf("Link %s despite these findings?", name))
+				if err != nil {
+					return err
+				}
+				if !ok {
+					return errors.New("not approved")
+				}
+			}
+			if err := installer.Approve(cfg, name); err != nil {
+				return err
+			}
+			targets := cfg.TargetCLIs
+			if cliTargets != "" {
+				targets = strings.Split(cliTargets, ",")
+			}
+			return finishInstall(cfg, []string{name}, targets, projectScope)
+		},
+	}
+	cmd.Flags().StringVar(&cliTargets, "cli", "", "comma-separated target CLIs")
+	cmd.Flags().BoolVar(&projectScope, "project", false, "mark skill for current project")
+	cmd.Flags().BoolVar(&reject, "reject", false, "delete the quarantined skill instead")
+	cmd.Flags().BoolVarP(&yes, "yes", "y", false, "approve without asking")
+	return cmd
+}
+
+// vetInstalled scans skills just written to the store and checks their
+// signatures, returning the ones safe to link. Risky ones are quarantined
+// with a note on how to review them; a skill that cannot be checked is not
+// linked either.
+func vetInstalled(cfg config.Config, names []string) []string {
+	safe := make([]string, 0, len(names))
+	for _, name := range names {
+		rep, quarantined, err := installer.Vet(cfg, name)
+		switch {
+		case err != nil:
+			fmt.Fprintf(os.Stderr, "warn: unable to scan %s, not linking it: %v\n", name, err)
+		case quarantined && deniedBySignature(rep):
+			printFindings(name, rep)
+			fmt.Printf("Quarantined %s: the trust policy denies it; sign it or change trust in config.yaml\n", name)
+		case quarantined:
+			printFindings(name, rep)
+			fmt.Printf("Quarantined %s; review with `pskill info %s`, then `pskill approve %s`\n", name, name, name)
+		default:
+			for _, f := range rep.Findings {
+				if f.Rule == security.RuleSignature {
+					fmt.Fprintf(os.Stderr, "warn: %s: %s\n", name, f.Detail)
+				}
+			}
+			safe = append(safe, name)
+		}
+	}
+	return safe
+}
+
+func deniedBySignature(rep security.Report) bool {
+	for _, f := range rep.Findings {
+		if f.Rule == security.RuleSignature && f.Severity == security.High {
+			return true
+		}
+	}
+	return false
+}
+
+func printFindings(name string, rep security.Report) {
+	fmt.Printf("Scan of %s: %s\n", name, rep.Summary())
+	for _, f := range rep.Findings {
+		fmt.Printf("  %s\n", f)
+	}
+}
+
+// confirm asks a yes/no question on the terminal. Without one it refuses,
+// so scripts have to pass --yes.
+func confirm(question string) (bool, error) {
+	if !term.IsTerminal(int(os.Stdin.Fd())) {
+		return false, errors.New("not a terminal; pass --yes to approve")
+	}
+	fmt.Fprintf(os.Stderr, "%s [y/N] ", question)
+	line, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+	answer := strings.ToLower(strings.TrimSpace(line))
+	return answer == "y" || answer == "yes", nil
+}
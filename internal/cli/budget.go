@@ -0,0 +1,44 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ZiaoLiu-1/pskill/internal/config"
+	"github.com/ZiaoLiu-1/pskill/internal/installer"
+	"github.com/ZiaoLiu-1/pskill/internal/project"
+	"github.com/ZiaoLiu-1/pskill/internal/tokens"
+)
+
+// checkBudget enforces the pskill.yaml budget in projectDir as if the given
+// store skills were linked into every target CLI. It warns on stderr, or
+// returns an error when the manifest sets onExceed: fail.
+func checkBudget(cfg config.Config, projectDir string, targets []string, adding []string) error {
+	manifest, err := project.Load(projectDir)
+	if err != nil || manifest.Budget.IsZero() {
+		return nil
+	}
+	usage := installer.TokenUsage(targets, projectDir)
+	for _, name := range adding {
+		c, err := tokens.ForDir(filepath.Join(cfg.StoreDir, name))
+		if err != nil {
+			continue
+		}
+		for i := range usage {
+			usage[i].Add(name, c)
+		}
+	}
+	violations := tokens.CheckBudget(manifest.Budget, usage)
+	if len(violations) == 0 {
+		return nil
+	}
+	if manifest.Budget.Enforced() {
+		return fmt.Errorf("token budget exceeded: %s", strings.Join(violations, "; "))
+	}
+	for _, v := range violations {
+		fmt.Fprintf(os.Stderr, "warn: token budget: %s\n", v)
+	}
+	return nil
+}
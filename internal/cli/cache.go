@@ -0,0 +1,186 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ZiaoLiu-1/pskill/internal/config"
+	"github.com/ZiaoLiu-1/pskill/internal/registry"
+)
+
+func newCacheCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "cache",
+		Short: "Inspect and trim the registry cache",
+		Long: "Registry responses and downloaded skill files are cached under cacheDir. The cache is kept under cacheMaxMb " +
+			"(config.yaml, 0 for no limit) by evicting the least recently used entries.",
+	}
+	cmd.AddCommand(newCacheLsCmd(), newCacheStatsCmd(), newCacheCleanCmd(), newCachePruneCmd())
+	return cmd
+}
+
+func openCache() (config.Config, *registry.Cache, error) {
+	cfg, err := config.LoadGlobal()
+	if err != nil {
+		return cfg, nil, err
+	}
+	c := registry.NewCache(cfg.CacheDir)
+	c.SetMaxSize(int64(cfg.CacheMaxMB) << 20)
+	return cfg, c, nil
+}
+
+func newCacheLsCmd() *cobra.Command {
+	var asJSON bool
+	cmd := &cobra.Command{
+		Use:   "ls",
+		Short: "List cache entries, most recently used first",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			_, c, err := openCache()
+			if err != nil {
+				return err
+			}
+			items, err := c.List()
+			if err != nil {
+				return err
+			}
+			if asJSON {
+				out, _ := json.MarshalIndent(items, "", "  ")
+				fmt.Println(string(out))
+				return nil
+			}
+			for _, it := range items {
+				stored := "corrupt"
+				if !it.Corrupt {
+					stored = it.StoredAt.Local().Format("2006-01-02 15:04")
+				}
+				fmt.Printf("%-56s %9s  %s\n", it.Key, formatSize(it.Size), stored)
+			}
+			return nil
+		},
+	}
+	cmd.Flags().BoolVar(&asJSON, "json", false, "output as JSON")
+	return cmd
+}
+
+func newCacheStatsCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "stats",
+		Short: "Show cache size and entry counts",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, c, err := openCache()
+			if err != nil {
+				return err
+			}
+			items, err := c.List()
+			if err != nil {
+				return err
+			}
+			var total int64
+			corrupt := 0
+			var oldest time.Time
+			for _, it := range items {
+				total += it.Size
+				if it.Corrupt {
+					corrupt++
+				} else if oldest.IsZero() || it.StoredAt.Before(oldest) {
+					oldest = it.StoredAt
+				}
+			}
+			limit := "unbounded"
+			if cfg.CacheMaxMB > 0 {
+				limit = formatSize(int64(cfg.CacheMaxMB) << 20)
+			}
+			fmt.Printf("Directory:  %s\n", cfg.CacheDir)
+			fmt.Printf("Entries:    %d (%d corrupt)\n", len(items), corrupt)
+			fmt.Printf("Size:       %s of %s\n", formatSize(total), limit)
+			if !oldest.IsZero() {
+				fmt.Printf("Oldest:     %s\n", oldest.Local().Format("2006-01-02 15:04"))
+			}
+			fmt.Printf("Git clones: %s\n", formatSize(dirSize(filepath.Join(cfg.CacheDir, "git"))))
+			return nil
+		},
+	}
+}
+
+func newCacheCleanCmd() *cobra.Command {
+	var git bool
+	cmd := &cobra.Command{
+		Use:   "clean",
+		Short: "Remove every cache entry",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, c, err := openCache()
+			if err != nil {
+				return err
+			}
+			n, err := c.Clear()
+			if err != nil {
+				return err
+			}
+			fmt.Printf("Removed %d cache entries\n", n)
+			if git {
+				dir := filepath.Join(cfg.CacheDir, "git")
+				size := dirSize(dir)
+				if err := os.RemoveAll(dir); err != nil {
+					return err
+				}
+				fmt.Printf("Removed git clones (%s)\n", formatSize(size))
+			}
+			return nil
+		},
+	}
+	cmd.Flags().BoolVar(&git, "git", false, "also remove cached git clones")
+	return cmd
+}
+
+func newCachePruneCmd() *cobra.Command {
+	var olderThan time.Duration
+	cmd := &cobra.Command{
+		Use:   "prune",
+		Short: "Drop corrupt and old entries and enforce the size limit",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			_, c, err := openCache()
+			if err != nil {
+				return err
+			}
+			n, freed, err := c.Prune(olderThan)
+			if err != nil {
+				return err
+			}
+			fmt.Printf("Pruned %d cache entries, freed %s\n", n, formatSize(freed))
+			return nil
+		},
+	}
+	cmd.Flags().DurationVar(&olderThan, "older-than", 30*24*time.Hour, "remove entries stored longer ago than this (0 keeps all)")
+	return cmd
+}
+
+func formatSize(n int64) string {
+	switch {
+	case n >= 1<<20:
+		return fmt.Sprintf("%.1f MB", float64(n)/(1<<20))
+	case n >= 1<<10:
+		return fmt.Sprintf("%.1f KB", float64(n)/(1<<10))
+	default:
+		return fmt.Sprintf("%d B", n)
+	}
+}
+
+func dirSize(dir string) int64 {
+	var total int64
+	_ = filepath.WalkDir(dir, func(_ string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if info, err := d.Info(); err == nil && d.Type().IsRegular() {
+			total += info.Size()
+		}
+		return nil
+	})
+	return total
+}
@@ -0,0 +1,140 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ZiaoLiu-1/pskill/internal/config"
+	"github.com/ZiaoLiu-1/pskill/internal/diff"
+	"github.com/ZiaoLiu-1/pskill/internal/installer"
+	"github.com/ZiaoLiu-1/pskill/internal/project"
+	"github.com/ZiaoLiu-1/pskill/internal/store"
+)
+
+func newDiffCmd() *cobra.Command {
+	var projectCopy bool
+	var cliName string
+	var from, to string
+	var stat bool
+	var listVersions bool
+	var asJSON bool
+
+	cmd := &cobra.Command{
+		Use:   "diff <skill>",
+		Short: "Show changes between a stored skill and its upstream, project copy or earlier versions",
+		Long: "Compare the store copy of a skill against its upstream source (default), against the copied or rendered instance in the current project (--project), " +
+			"or between stored versions (--from, --to). Versions are snapshotted by `pskill update`; use --versions to list them.",
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := config.LoadGlobal()
+			if err != nil {
+				return err
+			}
+			name := args[0]
+			st := store.NewManager(cfg.StoreDir)
+			if _, err := os.Stat(st.SkillDir(name)); err != nil {
+				return fmt.Errorf("%s is not in the store", name)
+			}
+
+			if listVersions {
+				ids, err := st.ListVersions(name)
+				if err != nil {
+					return err
+				}
+				if len(ids) == 0 {
+					fmt.Println("No stored versions.")
+				}
+				for _, id := range ids {
+					fmt.Println(id)
+				}
+				return nil
+			}
+
+			var a, b, labelA, labelB string
+			switch {
+			case from != "" || to != "":
+				if from == "" {
+					ids, _ := st.ListVersions(name)
+					if len(ids) == 0 {
+						return fmt.Errorf("%s has no stored versions", name)
+					}
+					from = ids[len(ids)-1]
+				}
+				if to == "" {
+					to = "current"
+				}
+				if a, err = st.VersionDir(name, from); err != nil {
+					return err
+				}
+				if b, err = st.VersionDir(name, to); err != nil {
+					return err
+				}
+				labelA, labelB = from, to
+			case projectCopy:
+				wd, _ := os.Getwd()
+				targets := cfg.TargetCLIs
+				manifest, merr := project.Load(wd)
+				if merr == nil && len(manifest.TargetCLIs) > 0 {
+					targets = manifest.TargetCLIs
+				}
+				if cliName != "" {
+					targets = strings.Split(cliName, ",")
+				}
+				dir, cli := installer.ProjectCopy(wd, targets, name)
+				if dir == "" {
+					return fmt.Errorf("%s is not installed in this project", name)
+				}
+				expected, cleanup, err := installer.ExpectedProjectCopy(cfg, name, manifest.Vars)
+				if err != nil {
+					return err
+				}
+				defer cleanup()
+				a, b = expected, dir
+				labelA, labelB = "store", cli
+			default:
+				up, cleanup, err := installer.FetchUpstream(cmd.Context(), cfg, name)
+				if err != nil {
+					return err
+				}
+				defer cleanup()
+				a, b = st.SkillDir(name), up
+				labelA, labelB = "store", "upstream"
+			}
+
+			files, err := diff.Dirs(a, b, labelA, labelB)
+			if err != nil {
+				return err
+			}
+			if asJSON {
+				out, _ := json.MarshalIndent(files, "", "  ")
+				fmt.Println(string(out))
+				return nil
+			}
+			if len(files) == 0 {
+				fmt.Printf("No differences between %s and %s.\n", labelA, labelB)
+				return nil
+			}
+			if stat {
+				fmt.Print(diff.Stat(files))
+				return nil
+			}
+			for _, f := range files {
+				fmt.Print(f.Unified)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&projectCopy, "project", false, "compare against the project-local copy in the current directory")
+	cmd.Flags().StringVar(&cliName, "cli", "", "comma-separated CLIs to look in with --project")
+	cmd.Flags().StringVar(&from, "from", "", "stored version to diff from (default: latest snapshot)")
+	cmd.Flags().StringVar(&to, "to", "", "stored version to diff to (default: current)")
+	cmd.Flags().BoolVar(&stat, "stat", false, "show a per-file summary instead of the full diff")
+	cmd.Flags().BoolVar(&listVersions, "versions", false, "list stored versions")
+	cmd.Flags().BoolVar(&asJSON, "json", false, "output as JSON")
+	return cmd
+}
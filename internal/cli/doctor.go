@@ -0,0 +1,160 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ZiaoLiu-1/pskill/internal/config"
+	"github.com/ZiaoLiu-1/pskill/internal/registry"
+	"github.com/ZiaoLiu-1/pskill/internal/search"
+	"github.com/ZiaoLiu-1/pskill/internal/store"
+)
+
+type finding struct {
+	Skill   string `json:"skill,omitempty"`
+	Level   string `json:"level"` // "error" or "warn"
+	Message string `json:"message"`
+}
+
+func newDoctorCmd() *cobra.Command {
+	var asJSON bool
+	cmd := &cobra.Command{
+		Use:   "doctor",
+		Short: "Check the store for broken entries and missing or stale provenance",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := config.LoadGlobal()
+			if err != nil {
+				return err
+			}
+			findings, err := checkStore(cfg)
+			if err != nil {
+				return err
+			}
+			if asJSON {
+				out, _ := json.MarshalIndent(findings, "", "  ")
+				fmt.Println(string(out))
+				return nil
+			}
+			errs := 0
+			for _, f := range findings {
+				label := f.Level
+				if f.Skill != "" {
+					label += " " + f.Skill
+				}
+				fmt.Printf("%s: %s\n", label, f.Message)
+				if f.Level == "error" {
+					errs++
+				}
+			}
+			if len(findings) == 0 {
+				fmt.Println("No problems found.")
+				return nil
+			}
+			if errs > 0 {
+				return fmt.Errorf("%d problems found", errs)
+			}
+			return nil
+		},
+	}
+	cmd.Flags().BoolVar(&asJSON, "json", false, "output as JSON")
+	return cmd
+}
+
+func checkStore(cfg config.Config) ([]finding, error) {
+	st := store.NewManager(cfg.StoreDir)
+	var out []finding
+	add := func(skill, level, format string, args ...interface{}) {
+		out = append(out, finding{Skill: skill, Level: level, Message: fmt.Sprintf(format, args...)})
+	}
+
+	if _, err := registry.LoadCredentials(registry.CredentialsPath(cfg)); err != nil {
+		add("", "error", "credentials: %v", err)
+	}
+
+	// Entries ListSkills hides: dangling dev links and interrupted imports.
+	entries, err := os.ReadDir(cfg.StoreDir)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	for _, e := range entries {
+		path := filepath.Join(cfg.StoreDir, e.Name())
+		switch {
+		case strings.HasSuffix(e.Name(), ".pskill-tmp"):
+			add("", "warn", "leftover temporary copy %s; safe to delete", path)
+		case e.Type()&os.ModeSymlink != 0:
+			if _, err := os.Stat(path); err != nil {
+				target, _ := os.Readlink(path)
+				add(e.Name(), "error", "linked to %s, which no longer exists; run pskill remove --prune %s", target, e.Name())
+			}
+		}
+	}
+
+	quarantined, err := st.ListQuarantined()
+	if err != nil {
+		return nil, err
+	}
+	for _, name := range quarantined {
+		add(name, "warn", "quarantined after a risky scan; review with pskill info %s, then pskill approve %s", name, name)
+	}
+
+	names, err := st.ListSkills()
+	if err != nil {
+		return nil, err
+	}
+	for _, name := range names {
+		if _, err := os.Stat(filepath.Join(st.SkillDir(name), "SKILL.md")); err != nil {
+			add(name, "error", "no SKILL.md in %s", st.SkillDir(name))
+			continue
+		}
+		meta, err := st.ReadMeta(name)
+		if err != nil {
+			if os.IsNotExist(err) {
+				add(name, "warn", "no provenance record; reinstall it so update and outdated can track its source")
+			} else {
+				add(name, "error", "unreadable provenance record: %v", err)
+			}
+			continue
+		}
+		_, linked := st.LinkTarget(name)
+		if modified, err := st.Modified(name); err == nil && modified && !linked {
+			add(name, "warn", "edited in the store since it was installed; pskill update will overwrite the changes")
+		}
+		switch meta.SourceType {
+		case store.SourceLocal, store.SourceImport:
+			if _, err := os.Stat(meta.SourceURL); err != nil {
+				add(name, "warn", "source directory %s no longer exists", meta.SourceURL)
+			}
+		case store.SourceRegistry, store.SourceGit, store.SourceLink:
+		default:
+			add(name, "warn", "unknown source type %q", meta.SourceType)
+		}
+	}
+
+	indexed, err := search.NewStoreEngine(cfg.IndexDir, cfg.StoreDir).Names()
+	if err != nil {
+		add("", "error", "search index: %v; run pskill reindex", err)
+		return out, nil
+	}
+	inIndex := map[string]bool{}
+	for _, name := range indexed {
+		inIndex[name] = true
+	}
+	inStore := map[string]bool{}
+	for _, name := range names {
+		inStore[name] = true
+		if !inIndex[name] {
+			add(name, "warn", "missing from the search index; run pskill reindex")
+		}
+	}
+	for _, name := range indexed {
+		if !inStore[name] {
+			add(name, "warn", "in the search index but not the store; run pskill reindex")
+		}
+	}
+	return out, nil
+}
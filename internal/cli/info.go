@@ -0,0 +1,156 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ZiaoLiu-1/pskill/internal/config"
+	"github.com/ZiaoLiu-1/pskill/internal/security"
+	"github.com/ZiaoLiu-1/pskill/internal/signing"
+	"github.com/ZiaoLiu-1/pskill/internal/skill"
+	"github.com/ZiaoLiu-1/pskill/internal/store"
+)
+
+type skillInfo struct {
+	Name        string            `json:"name"`
+	Description string            `json:"description"`
+	Version     string            `json:"version,omitempty"` // from SKILL.md frontmatter
+	Path        string            `json:"path"`
+	Linked      string            `json:"linked,omitempty"`
+	Modified    bool              `json:"modified"`
+	Meta        *store.Meta       `json:"meta,omitempty"`
+	Snapshots   []string          `json:"snapshots,omitempty"`
+	Quarantined bool              `json:"quarantined"`
+	Scan        *security.Report  `json:"scan,omitempty"`
+	Signature   *signing.Decision `json:"signature,omitempty"`
+}
+
+func newInfoCmd() *cobra.Command {
+	var asJSON bool
+	cmd := &cobra.Command{
+		Use:   "info <skill>",
+		Short: "Show a stored skill and where it came from",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := config.LoadGlobal()
+			if err != nil {
+				return err
+			}
+			name := args[0]
+			st := store.NewManager(cfg.StoreDir)
+			dir := st.SkillDir(name)
+			quarantined := false
+			if _, err := os.Stat(dir); err != nil {
+				if !st.IsQuarantined(name) {
+					return fmt.Errorf("%s is not in the store", name)
+				}
+				dir, quarantined = st.QuarantineDir(name), true
+			}
+			info := skillInfo{Name: name, Path: dir, Quarantined: quarantined}
+			if sk, err := skill.ParseFile(filepath.Join(dir, "SKILL.md"), ""); err == nil {
+				info.Description, info.Version = sk.Description, sk.Version
+			}
+			info.Linked, _ = st.LinkTarget(name)
+			if meta, err := st.ReadMeta(name); err == nil {
+				info.Meta = &meta
+				info.Modified, _ = st.Modified(name)
+			}
+			info.Snapshots, _ = st.ListVersions(name)
+			if rep, err := security.ReadReport(dir); err == nil {
+				info.Scan = &rep
+			}
+			registryName := ""
+			if info.Meta != nil {
+				registryName = info.Meta.Registry
+			}
+			if d, err := signing.Check(cfg, dir, registryName, name); err == nil {
+				info.Signature = &d
+			}
+
+			if asJSON {
+				out, _ := json.MarshalIndent(info, "", "  ")
+				fmt.Println(string(out))
+				return nil
+			}
+			printInfo(info)
+			return nil
+		},
+	}
+	cmd.Flags().BoolVar(&asJSON, "json", false, "output as JSON")
+	return cmd
+}
+
+func printInfo(info skillInfo) {
+	row := func(label, value string) {
+		if value != "" {
+			fmt.Printf("%-13s %s\n", label+":", value)
+		}
+	}
+	row("Name", info.Name)
+	row("Description", info.Description)
+	row("Version", info.Version)
+	row("Path", info.Path)
+	row("Linked", info.Linked)
+	if info.Meta == nil {
+		fmt.Println("Source:       unknown (no provenance record; reinstall to create one)")
+		printScan(info)
+		return
+	}
+	m := info.Meta
+	row("Source", strings.TrimSpace(m.SourceType+" "+m.Registry))
+	row("URL", m.SourceURL)
+	row("From CLI", m.SourceCLI)
+	row("Registry ID", m.ID)
+	if m.Version != "" && m.Version != info.Version {
+		row("Reg. version", m.Version)
+	}
+	row("Ref", m.Ref)
+	row("Subdir", m.Subdir)
+	row("Commit", m.Commit)
+	if m.Hash != "" {
+		hash := m.Hash
+		if info.Modified && info.Linked == "" {
+			hash += " (modified since install)"
+		}
+		row("Hash", hash)
+	}
+	installed := m.InstalledAt.Local().Format("2006-01-02 15:04")
+	if m.InstalledBy != "" {
+		installed += " by " + m.InstalledBy
+	}
+	row("Installed", installed)
+	updated := m.UpdatedAt.Local().Format("2006-01-02 15:04")
+	if m.Installer != "" {
+		updated += " (pskill " + m.Installer + ")"
+	}
+	row("Updated", updated)
+	if len(info.Snapshots) > 0 {
+		row("Snapshots", fmt.Sprintf("%d (pskill diff %s --versions)", len(info.Snapshots), info.Name))
+	}
+	printScan(info)
+}
+
+func printScan(info skillInfo) {
+	if d := info.Signature; d != nil {
+		fmt.Printf("%-13s %s (policy: %s)\n", "Signature:", d.Verification, d.Action)
+	}
+	if info.Quarantined {
+		fmt.Printf("Quarantined:  yes (pskill approve %s to link it, --reject to delete it)\n", info.Name)
+	}
+	if info.Scan == nil {
+		return
+	}
+	scan := info.Scan.Summary() + ", " + info.Scan.ScannedAt.Local().Format("2006-01-02 15:04")
+	if info.Meta != nil && info.Meta.Approved != "" && info.Scan.Risky() && !info.Quarantined {
+		scan += " (approved)"
+	}
+	fmt.Printf("%-13s %s\n", "Scan:", scan)
+	for _, f := range info.Scan.Findings {
+		fmt.Printf("  %s\n", f)
+	}
+}
@@ -13,6 +13,7 @@ import (
 	"github.com/ZiaoLiu-1/pskill/internal/config"
 	"github.com/ZiaoLiu-1/pskill/internal/detector"
 	"github.com/ZiaoLiu-1/pskill/internal/scanner"
+	"github.com/ZiaoLiu-1/pskill/internal/search"
 	"github.com/ZiaoLiu-1/pskill/internal/store"
 	"github.com/ZiaoLiu-1/pskill/internal/tui"
 )
@@ -72,16 +73,26 @@ func initNonInteractive(cfg config.Config, importExisting bool) error {
 			return err
 		}
 		st := store.NewManager(cfg.StoreDir)
+		engine := search.NewStoreEngine(cfg.IndexDir, cfg.StoreDir)
 		adapters := adapter.All()
+		imported := map[string]string{}
 		for _, sk := range inv.Skills {
-			if err := st.ImportSkill(sk); err != nil {
+			if err := st.ImportSkill(sk, "pskill init"); err != nil {
 				fmt.Fprintf(os.Stderr, "warn: unable to import %s: %v\n", sk.Name, err)
 				continue
 			}
+			imported[sk.Name] = st.SkillDir(sk.Name)
 			if ad, ok := adapters[sk.SourceCLI]; ok && ad.SupportsSkills() {
 				_ = st.LinkSkillToCLI(sk.Name, ad.SkillDir())
 			}
 		}
+		skipped, err := engine.IndexSkillsByPath(imported)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "warn: unable to index imported skills: %v\n", err)
+		}
+		for name, err := range skipped {
+			fmt.Fprintf(os.Stderr, "warn: unable to index %s: %v\n", name, err)
+		}
 	}
 
 	fmt.Fprintf(os.Stdout, "Initialized pskill with targets: %s\n", strings.Join(cfg.TargetCLIs, ", "))
@@ -0,0 +1,82 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ZiaoLiu-1/pskill/internal/config"
+	"github.com/ZiaoLiu-1/pskill/internal/search"
+	"github.com/ZiaoLiu-1/pskill/internal/store"
+)
+
+func newLinkCmd() *cobra.Command {
+	var cliTargets string
+	var projectScope bool
+	var name string
+
+	cmd := &cobra.Command{
+		Use:   "link <path>",
+		Short: "Link a skill under development into the store so edits are live",
+		Long:  "Make the store entry a symlink to a skill directory in a working checkout, then link it into the target CLIs. Edits show up immediately in every CLI. Use `pskill unlink` to freeze it as a copy.",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := config.LoadGlobal()
+			if err != nil {
+				return err
+			}
+			targets := cfg.TargetCLIs
+			if cliTargets != "" {
+				targets = strings.Split(cliTargets, ",")
+			}
+			dir, err := resolvePath(args[0])
+			if err != nil {
+				return err
+			}
+			if name == "" {
+				name = filepath.Base(dir)
+			}
+			st := store.NewManager(cfg.StoreDir)
+			if err := st.LinkDir(name, dir); err != nil {
+				return err
+			}
+			if err := st.WriteMeta(name, st.Stamp(name, store.Meta{SourceType: store.SourceLink, SourceURL: dir, InstalledBy: invokedAs})); err != nil {
+				fmt.Fprintf(os.Stderr, "warn: unable to record source for %s: %v\n", name, err)
+			}
+			fmt.Printf("Linked %s → %s\n", name, dir)
+			return finishInstall(cfg, []string{name}, targets, projectScope)
+		},
+	}
+	cmd.Flags().StringVar(&cliTargets, "cli", "", "comma-separated target CLIs")
+	cmd.Flags().BoolVar(&projectScope, "project", false, "mark skill for current project")
+	cmd.Flags().StringVar(&name, "name", "", "store name (default: directory name)")
+	return cmd
+}
+
+func newUnlinkCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "unlink <skill>",
+		Short: "Replace a linked dev skill with a snapshot copy",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := config.LoadGlobal()
+			if err != nil {
+				return err
+			}
+			name := args[0]
+			st := store.NewManager(cfg.StoreDir)
+			target, err := st.UnlinkDir(name)
+			if err != nil {
+				return err
+			}
+			if err := search.NewStoreEngine(cfg.IndexDir, cfg.StoreDir).IndexSkillByPath(name, st.SkillDir(name)); err != nil {
+				fmt.Fprintf(os.Stderr, "warn: unable to index %s: %v\n", name, err)
+			}
+			fmt.Printf("Unlinked %s (copied from %s)\n", name, target)
+			return nil
+		},
+	}
+}
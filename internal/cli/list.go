@@ -11,12 +11,15 @@ import (
 
 	"github.com/ZiaoLiu-1/pskill/internal/adapter"
 	"github.com/ZiaoLiu-1/pskill/internal/config"
+	"github.com/ZiaoLiu-1/pskill/internal/installer"
 	"github.com/ZiaoLiu-1/pskill/internal/store"
+	"github.com/ZiaoLiu-1/pskill/internal/tokens"
 )
 
 func newListCmd() *cobra.Command {
 	var asJSON bool
 	var cliName string
+	var showTokens bool
 	cmd := &cobra.Command{
 		Use:     "ls",
 		Aliases: []string{"list"},
@@ -50,12 +53,24 @@ func newListCmd() *cobra.Command {
 				skills = filtered
 			}
 
+			if showTokens {
+				targets := cfg.TargetCLIs
+				if cliName != "" {
+					targets = []string{strings.ToLower(strings.TrimSpace(cliName))}
+				}
+				return printTokens(cfg.StoreDir, skills, targets, asJSON)
+			}
+
 			if asJSON {
 				out, _ := json.MarshalIndent(skills, "", "  ")
 				fmt.Println(string(out))
 				return nil
 			}
 			for _, s := range skills {
+				if target, ok := st.LinkTarget(s); ok {
+					fmt.Printf("%s  (dev → %s)\n", s, target)
+					continue
+				}
 				fmt.Println(s)
 			}
 			return nil
@@ -63,5 +78,40 @@ func newListCmd() *cobra.Command {
 	}
 	cmd.Flags().BoolVar(&asJSON, "json", false, "output as JSON")
 	cmd.Flags().StringVar(&cliName, "cli", "", "filter by cli name")
+	cmd.Flags().BoolVar(&showTokens, "tokens", false, "show estimated context tokens per skill and per CLI")
 	return cmd
 }
+
+type skillTokens struct {
+	Name string `json:"name"`
+	tokens.Count
+}
+
+func printTokens(storeDir string, skills []string, targets []string, asJSON bool) error {
+	rows := make([]skillTokens, 0, len(skills))
+	for _, s := range skills {
+		c, err := tokens.ForDir(filepath.Join(storeDir, s))
+		if err != nil {
+			continue
+		}
+		rows = append(rows, skillTokens{Name: s, Count: c})
+	}
+	wd, _ := os.Getwd()
+	usage := installer.TokenUsage(targets, wd)
+
+	if asJSON {
+		out, _ := json.MarshalIndent(map[string]interface{}{"skills": rows, "clis": usage}, "", "  ")
+		fmt.Println(string(out))
+		return nil
+	}
+	fmt.Printf("%-32s %8s %8s\n", "SKILL", "ALWAYS", "ON-USE")
+	for _, r := range rows {
+		fmt.Printf("%-32s %8d %8d\n", r.Name, r.Description, r.Body)
+	}
+	fmt.Println()
+	fmt.Printf("%-32s %8s %8s %8s\n", "CLI", "ALWAYS", "ON-USE", "SKILLS")
+	for _, u := range usage {
+		fmt.Printf("%-32s %8d %8d %8d\n", u.CLI, u.Description, u.Body, len(u.Skills))
+	}
+	return nil
+}
@@ -0,0 +1,171 @@
+package cli
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+
+	"github.com/ZiaoLiu-1/pskill/internal/config"
+	"github.com/ZiaoLiu-1/pskill/internal/registry"
+)
+
+func newLoginCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "login [registry]",
+		Short: "Save an API token for a registry",
+		Long: "Prompt for a registry's API token, or read it from stdin when piped, and save it to ~/.pskill/credentials " +
+			"(mode 0600). A registry's `credentials` reference (env:VAR, file:path or helper:command) in config.yaml takes " +
+			"precedence; a saved token takes precedence over a plaintext apiKey.",
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := config.LoadGlobal()
+			if err != nil {
+				return err
+			}
+			name := ""
+			if len(args) == 1 {
+				name = args[0]
+			}
+			rc, err := loginTarget(cfg, name)
+			if err != nil {
+				return err
+			}
+			token, err := readToken(rc.Name)
+			if err != nil {
+				return err
+			}
+			path := registry.CredentialsPath(cfg)
+			if _, err := registry.SaveCredential(path, rc.Name, token); err != nil {
+				return err
+			}
+			fmt.Printf("Saved token for %s in %s\n", rc.Name, path)
+			if rc.Credentials != "" {
+				fmt.Fprintf(os.Stderr, "warn: %s has credentials: %s in config.yaml, which takes precedence\n", rc.Name, rc.Credentials)
+			}
+			if rc.APIKey != "" {
+				fmt.Fprintf(os.Stderr, "warn: config.yaml still holds a plaintext apiKey for %s; remove it\n", rc.Name)
+			}
+			return nil
+		},
+	}
+}
+
+func newLogoutCmd() *cobra.Command {
+	var all bool
+	cmd := &cobra.Command{
+		Use:   "logout [registry]",
+		Short: "Remove a registry's saved API token",
+		Args:  cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := config.LoadGlobal()
+			if err != nil {
+				return err
+			}
+			path := registry.CredentialsPath(cfg)
+			var names []string
+			switch {
+			case all:
+				tokens, err := registry.LoadCredentials(path)
+				if err != nil {
+					return err
+				}
+				for name := range tokens {
+					names = append(names, name)
+				}
+				sort.Strings(names)
+			case len(args) == 1:
+				// Not checked against config.yaml, so tokens for removed
+				// registries can still be deleted.
+				names = []string{args[0]}
+			default:
+				rc, err := loginTarget(cfg, "")
+				if err != nil {
+					return err
+				}
+				names = []string{rc.Name}
+			}
+			for _, name := range names {
+				changed, err := registry.SaveCredential(path, name, "")
+				if err != nil {
+					return err
+				}
+				if changed {
+					fmt.Printf("Removed token for %s\n", name)
+				} else {
+					fmt.Printf("No saved token for %s\n", name)
+				}
+			}
+			return nil
+		},
+	}
+	cmd.Flags().BoolVar(&all, "all", false, "remove every saved token")
+	return cmd
+}
+
+// loginTarget finds the token-authenticated registry called name, or the
+// only one when name is empty. Without configured registries that is the
+// default skillsmp registry.
+func loginTarget(cfg config.Config, name string) (config.RegistryConfig, error) {
+	candidates := []config.RegistryConfig{{Name: registry.TypeSkillsMP, Type: registry.TypeSkillsMP, APIKey: cfg.RegistryAPIKey}}
+	if len(cfg.Registries) > 0 {
+		candidates = nil
+		for _, rc := range cfg.Registries {
+			if rc.Name == "" {
+				rc.Name = rc.Type
+			}
+			switch rc.Type {
+			case registry.TypeSkillsMP, "", registry.TypeIndex:
+				candidates = append(candidates, rc)
+			}
+		}
+	}
+	var names []string
+	for _, rc := range candidates {
+		if rc.Name == name {
+			return rc, nil
+		}
+		names = append(names, rc.Name)
+	}
+	switch {
+	case name != "":
+		return config.RegistryConfig{}, fmt.Errorf("no registry %q that takes a token; configured: %s", name, strings.Join(names, ", "))
+	case len(candidates) == 1:
+		return candidates[0], nil
+	case len(candidates) == 0:
+		return config.RegistryConfig{}, errors.New("no configured registry takes a token")
+	default:
+		return config.RegistryConfig{}, fmt.Errorf("several registries take a token; choose one of %s", strings.Join(names, ", "))
+	}
+}
+
+// readToken prompts for a token without echoing it, or reads the first
+// line of stdin when it is not a terminal.
+func readToken(name string) (string, error) {
+	var token string
+	if term.IsTerminal(int(os.Stdin.Fd())) {
+		fmt.Fprintf(os.Stderr, "Token for %s: ", name)
+		raw, err := term.ReadPassword(int(os.Stdin.Fd()))
+		fmt.Fprintln(os.Stderr)
+		if err != nil {
+			return "", err
+		}
+		token = string(raw)
+	} else {
+		line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+		if err != nil && line == "" {
+			return "", fmt.Errorf("read token: %w", err)
+		}
+		token = line
+	}
+	token = strings.TrimSpace(token)
+	if token == "" {
+		return "", errors.New("empty token")
+	}
+	return token, nil
+}
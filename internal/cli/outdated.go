@@ -0,0 +1,69 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ZiaoLiu-1/pskill/internal/config"
+	"github.com/ZiaoLiu-1/pskill/internal/installer"
+	"github.com/ZiaoLiu-1/pskill/internal/store"
+)
+
+func newOutdatedCmd() *cobra.Command {
+	var asJSON bool
+	var all bool
+	cmd := &cobra.Command{
+		Use:   "outdated [skill...]",
+		Short: "List stored skills whose source has changed since install",
+		Long: "Compare each stored skill with the source recorded at install time: the commit for git repositories, the version " +
+			"(or last update) for registries and the directory contents for local copies. Run `pskill update` to refresh them.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := config.LoadGlobal()
+			if err != nil {
+				return err
+			}
+			names := args
+			if len(names) == 0 {
+				if names, err = store.NewManager(cfg.StoreDir).ListSkills(); err != nil {
+					return err
+				}
+			}
+			statuses := installer.CheckOutdated(cmd.Context(), cfg, names)
+			if !all {
+				kept := statuses[:0]
+				for _, s := range statuses {
+					if s.Outdated || s.Err != "" {
+						kept = append(kept, s)
+					}
+				}
+				statuses = kept
+			}
+
+			if asJSON {
+				out, _ := json.MarshalIndent(statuses, "", "  ")
+				fmt.Println(string(out))
+				return nil
+			}
+			if len(statuses) == 0 {
+				fmt.Println("All skills are up to date.")
+				return nil
+			}
+			fmt.Printf("%-28s %-9s %-14s %-14s\n", "SKILL", "SOURCE", "CURRENT", "LATEST")
+			for _, s := range statuses {
+				latest := s.Latest
+				if s.Err != "" {
+					latest = "error: " + s.Err
+				} else if !s.Outdated {
+					latest += " (up to date)"
+				}
+				fmt.Printf("%-28s %-9s %-14s %s\n", s.Name, s.Source, s.Current, latest)
+			}
+			return nil
+		},
+	}
+	cmd.Flags().BoolVar(&asJSON, "json", false, "output as JSON")
+	cmd.Flags().BoolVar(&all, "all", false, "include skills that are up to date")
+	return cmd
+}
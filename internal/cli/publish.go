@@ -0,0 +1,113 @@
+package cli
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ZiaoLiu-1/pskill/internal/archive"
+	"github.com/ZiaoLiu-1/pskill/internal/config"
+	"github.com/ZiaoLiu-1/pskill/internal/registry"
+	"github.com/ZiaoLiu-1/pskill/internal/skill"
+	"github.com/ZiaoLiu-1/pskill/internal/store"
+)
+
+func newPublishCmd() *cobra.Command {
+	var registryName string
+	var version string
+	var dryRun bool
+
+	cmd := &cobra.Command{
+		Use:   "publish <skill|path>",
+		Short: "Lint, package and upload a skill to a registry",
+		Long: "Publish a skill from the store or a directory on disk. The skill is linted first and any error aborts the upload. " +
+			"The version comes from --version or the SKILL.md frontmatter and must be semver; registries refuse to overwrite a published version. " +
+			"Uploads go to a skillsmp-type registry, such as `pskill registry serve --publish`, via POST /api/v1/skills/{name}/versions.",
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := config.LoadGlobal()
+			if err != nil {
+				return err
+			}
+			dir, err := publishDir(cfg, args[0])
+			if err != nil {
+				return err
+			}
+
+			issues := skill.Lint(dir)
+			for _, is := range issues {
+				fmt.Fprintln(os.Stderr, is)
+			}
+			if skill.HasErrors(issues) {
+				return fmt.Errorf("lint failed for %s", dir)
+			}
+			sk, err := skill.ParseFile(filepath.Join(dir, "SKILL.md"), "")
+			if err != nil {
+				return err
+			}
+			if version == "" {
+				version = sk.Version
+			}
+			if version == "" {
+				return fmt.Errorf("%s has no version; add version: to its frontmatter or pass --version", sk.Name)
+			}
+			if !skill.ValidVersion(version) {
+				return fmt.Errorf("version %q is not semver (e.g. 1.2.0)", version)
+			}
+
+			var buf bytes.Buffer
+			if err := archive.WriteTarGz(&buf, dir); err != nil {
+				return fmt.Errorf("package %s: %w", dir, err)
+			}
+			meta := registry.PublishMeta{Name: sk.Name, Version: version, Description: sk.Description, Tags: sk.Tags}
+			if dryRun {
+				fmt.Printf("Would publish %s %s (%s)\n", meta.Name, meta.Version, formatSize(int64(buf.Len())))
+				return nil
+			}
+
+			multi := registry.FromConfig(cfg)
+			pub, regName, err := multi.Publisher(registryName)
+			if err != nil {
+				return err
+			}
+			res, err := pub.Publish(cmd.Context(), meta, buf.Bytes())
+			if err != nil {
+				if errors.Is(err, registry.ErrVersionExists) {
+					return fmt.Errorf("%s %s is already published to %s; bump the version", meta.Name, meta.Version, regName)
+				}
+				return fmt.Errorf("publish to %s: %w", regName, err)
+			}
+			if res.Version != "" {
+				version = res.Version
+			}
+			fmt.Printf("Published %s %s to %s\n", meta.Name, version, regName)
+			fmt.Printf("Install with: pskill add %s\n", multi.InstallSpec(regName, meta.Name, version))
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&registryName, "registry", "", "registry to publish to (default: the only one that accepts uploads)")
+	cmd.Flags().StringVar(&version, "version", "", "version to publish (default: frontmatter version)")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "lint and package without uploading")
+	return cmd
+}
+
+// publishDir resolves a path or a stored skill name to the directory to
+// package. A linked dev skill publishes from its working directory.
+func publishDir(cfg config.Config, arg string) (string, error) {
+	if isLocalPath(arg) {
+		return resolvePath(arg)
+	}
+	st := store.NewManager(cfg.StoreDir)
+	if target, ok := st.LinkTarget(arg); ok {
+		return target, nil
+	}
+	dir := st.SkillDir(arg)
+	if _, err := os.Stat(dir); err != nil {
+		return "", fmt.Errorf("%s is not in the store", arg)
+	}
+	return dir, nil
+}
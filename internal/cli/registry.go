@@ -0,0 +1,83 @@
+package cli
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ZiaoLiu-1/pskill/internal/config"
+	"github.com/ZiaoLiu-1/pskill/internal/registry"
+)
+
+func newRegistryCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "registry",
+		Short: "Work with skill registries",
+	}
+	cmd.AddCommand(newRegistryServeCmd())
+	return cmd
+}
+
+func newRegistryServeCmd() *cobra.Command {
+	var dir string
+	var addr string
+	var indexDir string
+	var baseURL string
+	var publish bool
+	var token string
+
+	cmd := &cobra.Command{
+		Use:   "serve",
+		Short: "Serve a directory of skills as a registry",
+		Long: "Serve every SKILL.md under --dir over the same search, ai-search and download API that skillsmp.com provides. " +
+			"Point registryUrl (or a registries entry of type skillsmp) at the printed address. Restart to pick up new skills. " +
+			"With --publish, `pskill publish` uploads are accepted and stored under <dir>/.pskill-published.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := config.LoadGlobal()
+			if err != nil {
+				return err
+			}
+			root, err := resolvePath(dir)
+			if err != nil {
+				return err
+			}
+			if indexDir == "" {
+				indexDir = filepath.Join(cfg.CacheDir, "serve-index")
+			}
+			ln, err := net.Listen("tcp", addr)
+			if err != nil {
+				return err
+			}
+			if baseURL == "" {
+				baseURL = "http://" + ln.Addr().String()
+			}
+			srv, err := registry.NewServer(root, indexDir, baseURL)
+			if err != nil {
+				ln.Close()
+				return err
+			}
+			if publish {
+				secret := ""
+				if token != "" {
+					if secret, err = registry.ResolveCredentials(token); err != nil {
+						ln.Close()
+						return err
+					}
+				}
+				srv.EnablePublish(secret)
+			}
+			fmt.Printf("Serving %d skills from %s at %s\n", srv.Len(), root, baseURL)
+			return http.Serve(ln, srv)
+		},
+	}
+	cmd.Flags().StringVar(&dir, "dir", ".", "directory to scan for skills")
+	cmd.Flags().StringVar(&addr, "addr", "127.0.0.1:8765", "listen address")
+	cmd.Flags().StringVar(&indexDir, "index", "", "search index directory (default: <cacheDir>/serve-index)")
+	cmd.Flags().StringVar(&baseURL, "base-url", "", "public URL used in download links (default: http://<addr>)")
+	cmd.Flags().BoolVar(&publish, "publish", false, "accept uploads from pskill publish")
+	cmd.Flags().StringVar(&token, "token", "", "require this bearer token for uploads (env:VAR or file:path)")
+	return cmd
+}
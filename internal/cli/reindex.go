@@ -0,0 +1,36 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ZiaoLiu-1/pskill/internal/config"
+	"github.com/ZiaoLiu-1/pskill/internal/search"
+)
+
+func newReindexCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "reindex",
+		Short: "Rebuild the local search index from the store",
+		Long: "Drop the local search index and rebuild it from every skill in the store. Search already rebuilds an index " +
+			"that is corrupt or was written by a different pskill version; use this after editing the store by hand.",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := config.LoadGlobal()
+			if err != nil {
+				return err
+			}
+			indexed, skipped, err := search.NewStoreEngine(cfg.IndexDir, cfg.StoreDir).Reindex()
+			for _, e := range skipped {
+				fmt.Fprintf(os.Stderr, "warn: skipped %v\n", e)
+			}
+			if err != nil {
+				return err
+			}
+			fmt.Printf("Indexed %d skills\n", indexed)
+			return nil
+		},
+	}
+}
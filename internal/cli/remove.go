@@ -9,6 +9,7 @@ import (
 
 	"github.com/ZiaoLiu-1/pskill/internal/config"
 	"github.com/ZiaoLiu-1/pskill/internal/monitor"
+	"github.com/ZiaoLiu-1/pskill/internal/search"
 	"github.com/ZiaoLiu-1/pskill/internal/store"
 )
 
@@ -27,10 +28,17 @@ func newRemoveCmd() *cobra.Command {
 			if err := st.UnlinkSkillEverywhere(args[0]); err != nil {
 				return err
 			}
+			engine := search.NewStoreEngine(cfg.IndexDir, cfg.StoreDir)
 			if prune {
 				if err := st.RemoveSkill(args[0]); err != nil {
 					return err
 				}
+				if err := engine.DeleteSkill(args[0]); err != nil {
+					fmt.Fprintf(os.Stderr, "warn: unable to remove %s from the search index: %v\n", args[0], err)
+				}
+			} else {
+				// Still searchable, but no longer linked anywhere.
+				_ = engine.IndexSkillByPath(args[0], st.SkillDir(args[0]))
 			}
 			// Record usage event
 			if tr, err := monitor.NewTracker(cfg.StatsDB); err == nil {
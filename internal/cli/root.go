@@ -15,6 +15,8 @@ import (
 
 var (
 	debug      bool
+	offline    bool
+	invokedAs  string // command path recorded as InstalledBy in store meta
 	appVersion = "dev"
 	appCommit  = "none"
 	appDate    = "unknown"
@@ -47,15 +49,38 @@ func newRootCmd() *cobra.Command {
 	}
 
 	cmd.PersistentFlags().BoolVar(&debug, "debug", false, "enable debug logging")
+	cmd.PersistentFlags().BoolVar(&offline, "offline", false, "use cached registry data and the local store only")
+	cmd.PersistentPreRun = func(cmd *cobra.Command, args []string) {
+		config.SetOffline(offline)
+		invokedAs = cmd.CommandPath()
+	}
 	cmd.AddCommand(
 		newInitCmd(),
 		newAddCmd(),
 		newRemoveCmd(),
+		newUpdateCmd(),
+		newDiffCmd(),
+		newLinkCmd(),
+		newUnlinkCmd(),
+		newSyncCmd(),
 		newListCmd(),
+		newInfoCmd(),
+		newOutdatedCmd(),
+		newDoctorCmd(),
 		newDetectCmd(),
 		newScanCmd(),
 		newSearchCmd(),
+		newReindexCmd(),
 		newTrendingCmd(),
+		newTagsCmd(),
+		newRegistryCmd(),
+		newCacheCmd(),
+		newPublishCmd(),
+		newLoginCmd(),
+		newLogoutCmd(),
+		newApproveCmd(),
+		newSignCmd(),
+		newVerifyCmd(),
 		newMonitorCmd(),
 		newVersionCmd(),
 	)
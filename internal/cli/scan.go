@@ -9,6 +9,7 @@ import (
 	"github.com/ZiaoLiu-1/pskill/internal/adapter"
 	"github.com/ZiaoLiu-1/pskill/internal/config"
 	"github.com/ZiaoLiu-1/pskill/internal/scanner"
+	"github.com/ZiaoLiu-1/pskill/internal/search"
 	"github.com/ZiaoLiu-1/pskill/internal/store"
 )
 
@@ -32,13 +33,19 @@ func newScanCmd() *cobra.Command {
 				cfg, err := config.LoadGlobal()
 				if err == nil {
 					st := store.NewManager(cfg.StoreDir)
+					engine := search.NewStoreEngine(cfg.IndexDir, cfg.StoreDir)
 					adapters := adapter.All()
+					imported := map[string]string{}
 					for _, sk := range inv.Skills {
-						_ = st.ImportSkill(sk)
+						if err := st.ImportSkill(sk, "pskill scan"); err != nil {
+							continue
+						}
+						imported[sk.Name] = st.SkillDir(sk.Name)
 						if ad, ok := adapters[sk.SourceCLI]; ok {
 							_ = st.LinkSkillToCLI(sk.Name, ad.SkillDir())
 						}
 					}
+					_, _ = engine.IndexSkillsByPath(imported)
 				}
 			}
 			fmt.Printf("Detected %d skills\n", len(inv.Skills))
@@ -1,7 +1,9 @@
 package cli
 
 import (
+	"encoding/json"
 	"fmt"
+	"os"
 	"strings"
 
 	"github.com/spf13/cobra"
@@ -11,33 +13,87 @@ import (
 	"github.com/ZiaoLiu-1/pskill/internal/search"
 )
 
+// searchOutput is the --json form of pskill search.
+type searchOutput struct {
+	Query  string                 `json:"query"`
+	Local  search.Page            `json:"local"`
+	Remote []registry.SkillResult `json:"remote,omitempty"`
+}
+
 func newSearchCmd() *cobra.Command {
-	var online bool
+	var online, asJSON bool
+	var limit int
 	cmd := &cobra.Command{
 		Use:   "search <query>",
 		Short: "Search skills by meaning",
-		Args:  cobra.MinimumNArgs(1),
+		Long: "Search the local index by keyword (BM25) and by meaning together. Meaning comes from TF-IDF vectors of each " +
+			"skill's words plus a small built-in vocabulary of related terms, so \"write tests for my api\" finds a skill " +
+			"that \"generates unit test suites for REST endpoints\". Each local hit shows the words that matched. No network " +
+			"is needed; --online also asks the configured registries.\n\n" +
+			"Narrow local results with tag:, cli:, installed: (global, project or none), source: (registry, git, import, " +
+			"link, a registry name or a host such as github) and author: filters, e.g.\n\n" +
+			"  pskill search tag:testing cli:claude installed:project source:github \"react hooks\"\n\n" +
+			"Repeat a field to accept any of its values and prefix a filter with - to exclude it. Counts per value are " +
+			"listed below the results. Filters describe installed skills, so --online is skipped when any are given.",
+		Args: cobra.MinimumNArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			query := strings.Join(args, " ")
 			cfg, err := config.LoadGlobal()
 			if err != nil {
 				return err
 			}
-			engine := search.NewEngine(cfg.IndexDir)
-			local, _ := engine.Search(query, 10)
-			for i, item := range local {
-				fmt.Printf("L%02d %-28s %.2f\n", i+1, item.Name, item.Score)
+			engine := search.NewStoreEngine(cfg.IndexDir, cfg.StoreDir)
+			page, err := engine.Query(query, limit, 0)
+			if err != nil {
+				return err
+			}
+			out := searchOutput{Query: query, Local: page}
+			parsed := search.ParseQuery(query)
+			if online && parsed.Filtered() {
+				fmt.Fprintln(os.Stderr, "skip registries: filters apply to installed skills only")
+			} else if online {
+				out.Remote, _ = registry.FromConfig(cfg).AISearch(cmd.Context(), parsed.Text)
+				if note := registry.StaleNote(out.Remote); note != "" {
+					fmt.Fprintf(os.Stderr, "warn: offline, results %s\n", note)
+				}
+			}
+			if asJSON {
+				raw, _ := json.MarshalIndent(out, "", "  ")
+				fmt.Println(string(raw))
+				return nil
+			}
+
+			for i, item := range page.Results {
+				fmt.Println(strings.TrimRight(fmt.Sprintf("L%02d %-28s %.2f  %s", i+1, item.Name, item.Score, item.Why), " "))
+			}
+			if page.Total > len(page.Results) {
+				fmt.Printf("... %d more local results (--limit)\n", page.Total-len(page.Results))
+			}
+			for i, item := range out.Remote {
+				fmt.Printf("R%02d %-28s %.2f  by %s [%s]\n", i+1, item.Name, item.Score, item.Author, item.Registry)
 			}
-			if online {
-				client := registry.NewClient(cfg.RegistryURL, cfg.CacheDir, cfg.RegistryAPIKey)
-				remote, _ := client.AISearch(query)
-				for i, item := range remote {
-					fmt.Printf("R%02d %-28s %.2f  by %s\n", i+1, item.Name, item.Score, item.Author)
+			if len(page.Facets) > 0 {
+				fmt.Println()
+				for _, f := range page.Facets {
+					terms := make([]string, 0, len(f.Terms))
+					for i, term := range f.Terms {
+						if i == maxFacetTerms {
+							terms = append(terms, "…")
+							break
+						}
+						terms = append(terms, fmt.Sprintf("%s (%d)", term.Value, term.Count))
+					}
+					fmt.Printf("%-10s %s\n", f.Field+":", strings.Join(terms, "  "))
 				}
 			}
 			return nil
 		},
 	}
-	cmd.Flags().BoolVar(&online, "online", false, "also search skillsmp.com")
+	cmd.Flags().BoolVar(&online, "online", false, "also search configured registries")
+	cmd.Flags().BoolVar(&asJSON, "json", false, "output results and facet counts as JSON")
+	cmd.Flags().IntVar(&limit, "limit", 10, "maximum number of local results")
 	return cmd
 }
+
+// maxFacetTerms caps how many values of a facet are listed.
+const maxFacetTerms = 8
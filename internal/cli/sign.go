@@ -0,0 +1,147 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ZiaoLiu-1/pskill/internal/config"
+	"github.com/ZiaoLiu-1/pskill/internal/signing"
+	"github.com/ZiaoLiu-1/pskill/internal/skill"
+	"github.com/ZiaoLiu-1/pskill/internal/store"
+)
+
+func newSignCmd() *cobra.Command {
+	var keyPath string
+	var keygen bool
+	cmd := &cobra.Command{
+		Use:   "sign <skill|path>",
+		Short: "Sign a skill's content with an ed25519 key",
+		Long: "Write SKILL.sig next to a skill's SKILL.md, signing the hash of its files so installers that trust the key can " +
+			"check where it came from. Sign after the last edit and before publishing; any later change invalidates it. " +
+			"`pskill sign --keygen [name]` creates a key pair (~/.pskill/signing.key, unencrypted, mode 0600) and prints the " +
+			"public key to add under trust.keys in config.yaml.",
+		Args: cobra.RangeArgs(0, 1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := config.LoadGlobal()
+			if err != nil {
+				return err
+			}
+			if keyPath == "" {
+				keyPath = filepath.Join(cfg.HomeDir, "signing.key")
+			}
+			if keygen {
+				name := "my-key"
+				if len(args) == 1 {
+					name = args[0]
+				}
+				pub, sec, err := signing.GenerateKey()
+				if err != nil {
+					return err
+				}
+				if err := signing.WriteKeyPair(keyPath, pub, sec); err != nil {
+					return err
+				}
+				fmt.Printf("Wrote secret key %s and public key %s.pub (key %s)\n", keyPath, keyPath, signing.KeyID(pub.ID))
+				fmt.Printf("To trust it, add to config.yaml:\n\ntrust:\n  keys:\n    - name: %s\n      publicKey: %s\n", name, pub)
+				return nil
+			}
+			if len(args) == 0 {
+				return fmt.Errorf("name a skill or directory to sign, or pass --keygen")
+			}
+
+			dir, err := publishDir(cfg, args[0])
+			if err != nil {
+				return err
+			}
+			sec, err := signing.ReadSecretKey(keyPath)
+			if err != nil {
+				return err
+			}
+			name := filepath.Base(dir)
+			if sk, err := skill.ParseFile(filepath.Join(dir, "SKILL.md"), ""); err == nil && sk.Name != "" {
+				name = sk.Name
+			}
+			hash, err := signing.Sign(dir, name, sec, time.Now())
+			if err != nil {
+				return err
+			}
+			fmt.Printf("Signed %s (%s) with key %s\n", name, hash, signing.KeyID(sec.ID))
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&keyPath, "key", "", "secret key file (default ~/.pskill/signing.key)")
+	cmd.Flags().BoolVar(&keygen, "keygen", false, "create a new key pair instead of signing")
+	return cmd
+}
+
+type verifyResult struct {
+	Name string `json:"name"`
+	Path string `json:"path"`
+	signing.Decision
+}
+
+func newVerifyCmd() *cobra.Command {
+	var asJSON bool
+	cmd := &cobra.Command{
+		Use:   "verify [skill|path...]",
+		Short: "Check skill signatures against the trust policy",
+		Long: "Verify SKILL.sig for the named skills or directories, or every stored skill, against the keys in trust.keys, and " +
+			"show what the trust policy does with each: allow, warn or deny. Fails when a signature is bad or the policy denies a skill.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := config.LoadGlobal()
+			if err != nil {
+				return err
+			}
+			st := store.NewManager(cfg.StoreDir)
+			targets := args
+			if len(targets) == 0 {
+				if targets, err = st.ListSkills(); err != nil {
+					return err
+				}
+			}
+
+			results := make([]verifyResult, 0, len(targets))
+			failed := 0
+			for _, arg := range targets {
+				dir, err := publishDir(cfg, arg)
+				if err != nil {
+					return err
+				}
+				name, registryName := filepath.Base(dir), ""
+				if !isLocalPath(arg) {
+					name = arg
+					if meta, err := st.ReadMeta(arg); err == nil {
+						registryName = meta.Registry
+					}
+				}
+				d, err := signing.Check(cfg, dir, registryName, name)
+				if err != nil {
+					return err
+				}
+				if d.Status == signing.Invalid || d.Action == signing.Deny {
+					failed++
+				}
+				results = append(results, verifyResult{Name: name, Path: dir, Decision: d})
+			}
+
+			if asJSON {
+				out, _ := json.MarshalIndent(results, "", "  ")
+				fmt.Println(string(out))
+			} else {
+				for _, r := range results {
+					fmt.Printf("%-32s %-5s  %s\n", r.Name, r.Action, r.Verification)
+				}
+			}
+			if failed > 0 {
+				return fmt.Errorf("%d of %d skills failed verification", failed, len(results))
+			}
+			return nil
+		},
+	}
+	cmd.Flags().BoolVar(&asJSON, "json", false, "output as JSON")
+	return cmd
+}
@@ -0,0 +1,109 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ZiaoLiu-1/pskill/internal/config"
+	"github.com/ZiaoLiu-1/pskill/internal/installer"
+	"github.com/ZiaoLiu-1/pskill/internal/project"
+	"github.com/ZiaoLiu-1/pskill/internal/search"
+	"github.com/ZiaoLiu-1/pskill/internal/store"
+)
+
+func newSyncCmd() *cobra.Command {
+	var force bool
+	cmd := &cobra.Command{
+		Use:   "sync",
+		Short: "Install and link every skill in pskill.yaml into project-local CLI dirs",
+		Long:  "Install and link every skill in pskill.yaml into project-local CLI dirs. Templated skills are re-rendered with the manifest's vars; rendered copies edited by hand are skipped unless --force is set.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := config.LoadGlobal()
+			if err != nil {
+				return err
+			}
+			wd, err := os.Getwd()
+			if err != nil {
+				return err
+			}
+			manifest, err := project.Load(wd)
+			if err != nil {
+				return fmt.Errorf("no pskill.yaml in %s: %w", wd, err)
+			}
+			targets := manifest.TargetCLIs
+			if len(targets) == 0 {
+				targets = cfg.TargetCLIs
+			}
+
+			st := store.NewManager(cfg.StoreDir)
+			// Quarantined skills, including ones downloaded now, stay
+			// unlinked until approved. Entries already in the store are
+			// vetted too, in case an earlier download never was.
+			var installed []string
+			for _, name := range manifest.Installed {
+				if st.IsQuarantined(name) {
+					fmt.Fprintf(os.Stderr, "skip %s: quarantined; review with pskill approve %s\n", name, name)
+					continue
+				}
+				destPath, exists, err := st.EnsureSkillDir(name)
+				if err != nil {
+					return err
+				}
+				if !exists {
+					if err := downloadToStore(cmd.Context(), cfg, name, "", destPath); err != nil {
+						return fmt.Errorf("download %s: %w", name, err)
+					}
+				}
+				if len(vetInstalled(cfg, []string{name})) == 0 {
+					continue
+				}
+				installed = append(installed, name)
+			}
+
+			if err := checkBudget(cfg, wd, targets, installed); err != nil {
+				return err
+			}
+
+			rendered := 0
+			for _, name := range installed {
+				for _, t := range targets {
+					localDir := installer.ProjectCLISkillDir(wd, strings.TrimSpace(t))
+					if localDir == "" {
+						continue
+					}
+					ok, err := st.LinkSkillToProject(name, localDir, manifest.Vars, force)
+					if errors.Is(err, store.ErrHandEdited) {
+						fmt.Fprintf(os.Stderr, "warn: %s for %s was edited by hand; skipping (use --force to overwrite)\n", name, t)
+						continue
+					}
+					if err != nil {
+						fmt.Fprintf(os.Stderr, "warn: unable to link %s to %s: %v\n", name, t, err)
+						continue
+					}
+					if ok {
+						rendered++
+					}
+				}
+			}
+			dirs := map[string]string{}
+			for _, name := range installed {
+				_ = st.SetProject(name, wd, true)
+				dirs[name] = st.SkillDir(name)
+			}
+			_, _ = search.NewStoreEngine(cfg.IndexDir, cfg.StoreDir).IndexSkillsByPath(dirs)
+
+			fmt.Printf("Synced %d skills into %s", len(installed), strings.Join(targets, ", "))
+			if rendered > 0 {
+				fmt.Printf(" (%d rendered copies)", rendered)
+			}
+			fmt.Println()
+			return nil
+		},
+	}
+	cmd.Flags().BoolVar(&force, "force", false, "overwrite rendered copies that were edited by hand")
+	return cmd
+}
@@ -0,0 +1,83 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"sort"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ZiaoLiu-1/pskill/internal/config"
+	"github.com/ZiaoLiu-1/pskill/internal/skill"
+	"github.com/ZiaoLiu-1/pskill/internal/store"
+)
+
+type tagCount struct {
+	Tag      string `json:"tag"`
+	Count    int    `json:"count"`
+	Category bool   `json:"category"`
+}
+
+func newTagsCmd() *cobra.Command {
+	var asJSON bool
+	var categoriesOnly bool
+	cmd := &cobra.Command{
+		Use:   "tags",
+		Short: "List tags across stored skills with counts",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := config.LoadGlobal()
+			if err != nil {
+				return err
+			}
+			st := store.NewManager(cfg.StoreDir)
+			names, err := st.ListSkills()
+			if err != nil {
+				return err
+			}
+
+			counts := map[string]int{}
+			for _, name := range names {
+				sk, err := skill.ParseFile(filepath.Join(cfg.StoreDir, name, "SKILL.md"), "")
+				if err != nil {
+					continue
+				}
+				for _, tag := range sk.Tags {
+					counts[tag]++
+				}
+			}
+
+			out := make([]tagCount, 0, len(counts))
+			for tag, n := range counts {
+				_, isCat := skill.Taxonomy[tag]
+				if categoriesOnly && !isCat {
+					continue
+				}
+				out = append(out, tagCount{Tag: tag, Count: n, Category: isCat})
+			}
+			sort.Slice(out, func(i, j int) bool {
+				if out[i].Count != out[j].Count {
+					return out[i].Count > out[j].Count
+				}
+				return out[i].Tag < out[j].Tag
+			})
+
+			if asJSON {
+				raw, _ := json.MarshalIndent(out, "", "  ")
+				fmt.Println(string(raw))
+				return nil
+			}
+			for _, tc := range out {
+				marker := " "
+				if tc.Category {
+					marker = "*"
+				}
+				fmt.Printf("%s %-24s %d\n", marker, tc.Tag, tc.Count)
+			}
+			return nil
+		},
+	}
+	cmd.Flags().BoolVar(&asJSON, "json", false, "output as JSON")
+	cmd.Flags().BoolVar(&categoriesOnly, "categories", false, "only show taxonomy categories")
+	return cmd
+}
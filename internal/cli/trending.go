@@ -1,35 +1,75 @@
 package cli
 
 import (
+	"encoding/json"
 	"fmt"
+	"os"
 
 	"github.com/spf13/cobra"
 
 	"github.com/ZiaoLiu-1/pskill/internal/config"
+	"github.com/ZiaoLiu-1/pskill/internal/monitor"
 	"github.com/ZiaoLiu-1/pskill/internal/registry"
 )
 
 func newTrendingCmd() *cobra.Command {
 	var limit int
+	var all bool
+	var asJSON bool
+	var rising bool
 	cmd := &cobra.Command{
 		Use:   "trending",
-		Short: "Show trending skills from skillsmp.com",
+		Short: "Show trending skills from configured registries",
+		Long: "Show skills by stars. Results are fetched page by page, so --limit may exceed a registry's page size; --all reads every page. " +
+			"Each run records the star counts in statsDb; --rising orders by stars gained over the last week of those snapshots.",
 		RunE: func(cmd *cobra.Command, args []string) error {
 			cfg, err := config.LoadGlobal()
 			if err != nil {
 				return err
 			}
-			client := registry.NewClient(cfg.RegistryURL, cfg.CacheDir, cfg.RegistryAPIKey)
-			items, _, err := client.Trending(limit, 1)
-			if err != nil {
+			max := limit
+			if all {
+				max = 0
+			}
+			it := registry.TrendingPages(registry.FromConfig(cfg), max)
+			items, err := it.Collect(cmd.Context(), max)
+			if err != nil && len(items) == 0 {
 				return err
 			}
-			for i, it := range items {
-				fmt.Printf("%2d. %-30s ★%d  %s\n", i+1, it.Name, it.Stars, it.Author)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "warn: stopped after %d results: %v\n", len(items), err)
+			}
+			trends, err := monitor.SnapshotTrending(cfg.StatsDB, items)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "warn: star history: %v\n", err)
+			}
+			if rising {
+				monitor.SortRising(items, trends)
+			}
+			if asJSON {
+				if items == nil {
+					items = []registry.SkillResult{}
+				}
+				out, _ := json.MarshalIndent(items, "", "  ")
+				fmt.Println(string(out))
+			} else {
+				for i, r := range items {
+					delta := ""
+					if tr, ok := trends[monitor.SnapshotKey(r)]; ok && tr.Weekly != 0 {
+						delta = fmt.Sprintf(" (%+d/wk)", tr.Weekly)
+					}
+					fmt.Printf("%2d. %-30s ★%d%s  %s [%s]\n", i+1, r.Name, r.Stars, delta, r.Author, r.Registry)
+				}
+			}
+			if note := registry.StaleNote(items); note != "" {
+				fmt.Fprintf(os.Stderr, "warn: offline, results %s\n", note)
 			}
 			return nil
 		},
 	}
 	cmd.Flags().IntVar(&limit, "limit", 10, "number of results")
+	cmd.Flags().BoolVar(&all, "all", false, "fetch every page")
+	cmd.Flags().BoolVar(&asJSON, "json", false, "output as JSON")
+	cmd.Flags().BoolVar(&rising, "rising", false, "order by stars gained this week")
 	return cmd
 }
@@ -0,0 +1,181 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ZiaoLiu-1/pskill/internal/adapter"
+	"github.com/ZiaoLiu-1/pskill/internal/config"
+	"github.com/ZiaoLiu-1/pskill/internal/search"
+	"github.com/ZiaoLiu-1/pskill/internal/source"
+	"github.com/ZiaoLiu-1/pskill/internal/store"
+)
+
+func newUpdateCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "update [skill...]",
+		Short: "Refresh installed skills from their recorded source",
+		Long:  "Refresh installed skills from the source recorded at install time. Skills installed from the same repository and ref are fetched together with a single clone.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := config.LoadGlobal()
+			if err != nil {
+				return err
+			}
+			st := store.NewManager(cfg.StoreDir)
+			names := args
+			if len(names) == 0 {
+				if names, err = st.ListSkills(); err != nil {
+					return err
+				}
+			}
+
+			type repoKey struct{ url, ref string }
+			groups := map[repoKey][]string{}
+			var registryNames, localNames []string
+			for _, name := range names {
+				if target, ok := st.LinkTarget(name); ok {
+					if len(args) > 0 {
+						fmt.Fprintf(os.Stderr, "skip %s: linked to %s\n", name, target)
+					}
+					continue
+				}
+				if st.IsQuarantined(name) {
+					fmt.Fprintf(os.Stderr, "skip %s: quarantined; run pskill approve %s or --reject it\n", name, name)
+					continue
+				}
+				meta, err := st.ReadMeta(name)
+				if err != nil {
+					if len(args) > 0 {
+						fmt.Fprintf(os.Stderr, "skip %s: no recorded source\n", name)
+					}
+					continue
+				}
+				switch meta.SourceType {
+				case store.SourceGit:
+					k := repoKey{meta.SourceURL, meta.Ref}
+					groups[k] = append(groups[k], name)
+				case store.SourceRegistry:
+					registryNames = append(registryNames, name)
+				case store.SourceLocal:
+					localNames = append(localNames, name)
+				}
+			}
+
+			indexed := map[string]string{}
+			git := source.NewGit(cfg.CacheDir).SetOffline(cfg.Offline)
+			keys := make([]repoKey, 0, len(groups))
+			for k := range groups {
+				keys = append(keys, k)
+			}
+			sort.Slice(keys, func(i, j int) bool { return keys[i].url+keys[i].ref < keys[j].url+keys[j].ref })
+
+			updated := 0
+			for _, k := range keys {
+				dir, err := git.Fetch(cmd.Context(), k.url, k.ref)
+				if err != nil {
+					fmt.Fprintf(os.Stderr, "warn: %s: %v\n", k.url, err)
+					continue
+				}
+				commit, _ := git.Head(dir)
+				for _, name := range groups[k] {
+					meta, _ := st.ReadMeta(name)
+					if meta.Commit != "" && meta.Commit == commit {
+						fmt.Printf("%s is up to date at %s\n", name, shortCommit(commit))
+						continue
+					}
+					snapshot(st, name)
+					dest, err := st.ImportDir(name, filepath.Join(dir, filepath.FromSlash(meta.Subdir)))
+					if err != nil {
+						fmt.Fprintf(os.Stderr, "warn: %s: %v\n", name, err)
+						continue
+					}
+					meta.Commit = commit
+					_ = st.WriteMeta(name, st.Stamp(name, meta))
+					if len(vetInstalled(cfg, []string{name})) == 0 {
+						continue
+					}
+					indexed[name] = dest
+					fmt.Printf("Updated %s from %s (%s)\n", name, describeRef(k.url, k.ref), shortCommit(commit))
+					updated++
+				}
+			}
+			for _, name := range localNames {
+				meta, _ := st.ReadMeta(name)
+				snapshot(st, name)
+				dest, err := st.ImportDir(name, meta.SourceURL)
+				if err != nil {
+					fmt.Fprintf(os.Stderr, "warn: %s: %v\n", name, err)
+					continue
+				}
+				_ = st.WriteMeta(name, st.Stamp(name, meta))
+				if len(vetInstalled(cfg, []string{name})) == 0 {
+					continue
+				}
+				indexed[name] = dest
+				fmt.Printf("Updated %s from %s\n", name, meta.SourceURL)
+				updated++
+			}
+			for _, name := range registryNames {
+				if cfg.Offline {
+					fmt.Fprintf(os.Stderr, "skip %s: offline\n", name)
+					continue
+				}
+				dest := filepath.Join(cfg.StoreDir, name)
+				meta, _ := st.ReadMeta(name)
+				snapshot(st, name)
+				if err := downloadToStore(cmd.Context(), cfg, name, meta.Registry, dest); err != nil {
+					fmt.Fprintf(os.Stderr, "warn: %s: %v\n", name, err)
+					continue
+				}
+				if len(vetInstalled(cfg, []string{name})) == 0 {
+					continue
+				}
+				indexed[name] = dest
+				fmt.Printf("Updated %s from registry\n", name)
+				updated++
+			}
+			_, _ = search.NewStoreEngine(cfg.IndexDir, cfg.StoreDir).IndexSkillsByPath(indexed)
+			rerenderGlobal(st, indexed)
+
+			fmt.Printf("%d skills updated\n", updated)
+			return nil
+		},
+	}
+}
+
+// rerenderGlobal refreshes the rendered copies of updated templated skills
+// in the global CLI dirs, which unlike symlinks do not follow the store.
+func rerenderGlobal(st *store.Manager, names map[string]string) {
+	for name := range names {
+		if !st.IsTemplated(name) {
+			continue
+		}
+		for _, ad := range adapter.All() {
+			if !ad.SupportsSkills() || !store.IsRendered(filepath.Join(ad.SkillDir(), name)) {
+				continue
+			}
+			if err := st.LinkSkillToCLI(name, ad.SkillDir()); err != nil {
+				fmt.Fprintf(os.Stderr, "warn: unable to render %s for %s: %v\n", name, ad.Name(), err)
+			}
+		}
+	}
+}
+
+// snapshot keeps the current store entry in the version history so
+// `pskill diff --from` can compare against it after the refresh.
+func snapshot(st *store.Manager, name string) {
+	if _, err := st.SnapshotVersion(name); err != nil {
+		fmt.Fprintf(os.Stderr, "warn: unable to snapshot %s: %v\n", name, err)
+	}
+}
+
+func describeRef(url, ref string) string {
+	if ref == "" {
+		return url
+	}
+	return url + "@" + ref
+}
@@ -18,6 +18,13 @@ func SetVersion(v string) { appVersion = v }
 // GetVersion returns the app version.
 func GetVersion() string { return appVersion }
 
+// offline is set by the global --offline flag and copied into every
+// loaded Config.
+var offline bool
+
+// SetOffline forces Config.Offline for the rest of the process.
+func SetOffline(v bool) { offline = v }
+
 // IsFirstRun returns true if no config.yaml exists yet (never initialized).
 func IsFirstRun() bool {
 	_, err := os.Stat(configPath())
@@ -25,16 +32,77 @@ func IsFirstRun() bool {
 }
 
 type Config struct {
-	HomeDir            string   `mapstructure:"homeDir" yaml:"homeDir"`
-	StoreDir           string   `mapstructure:"storeDir" yaml:"storeDir"`
-	CacheDir           string   `mapstructure:"cacheDir" yaml:"cacheDir"`
-	IndexDir           string   `mapstructure:"indexDir" yaml:"indexDir"`
-	StatsDB            string   `mapstructure:"statsDb" yaml:"statsDb"`
-	RegistryURL        string   `mapstructure:"registryUrl" yaml:"registryUrl"`
-	RegistryAPIKey     string   `mapstructure:"registryApiKey" yaml:"registryApiKey"`
-	TargetCLIs         []string `mapstructure:"targetClis" yaml:"targetClis"`
-	DefaultSkills      []string `mapstructure:"defaultSkills" yaml:"defaultSkills"`
-	AutoUpdateTrending bool     `mapstructure:"autoUpdateTrending" yaml:"autoUpdateTrending"`
+	HomeDir            string           `mapstructure:"homeDir" yaml:"homeDir"`
+	StoreDir           string           `mapstructure:"storeDir" yaml:"storeDir"`
+	CacheDir           string           `mapstructure:"cacheDir" yaml:"cacheDir"`
+	IndexDir           string           `mapstructure:"indexDir" yaml:"indexDir"`
+	StatsDB            string           `mapstructure:"statsDb" yaml:"statsDb"`
+	RegistryURL        string           `mapstructure:"registryUrl" yaml:"registryUrl"`
+	RegistryAPIKey     string           `mapstructure:"registryApiKey" yaml:"registryApiKey"`
+	TargetCLIs         []string         `mapstructure:"targetClis" yaml:"targetClis"`
+	DefaultSkills      []string         `mapstructure:"defaultSkills" yaml:"defaultSkills"`
+	AutoUpdateTrending bool             `mapstructure:"autoUpdateTrending" yaml:"autoUpdateTrending"`
+	CacheMaxMB         int              `mapstructure:"cacheMaxMb" yaml:"cacheMaxMb"` // registry cache size limit; 0 = unbounded
+	Registries         []RegistryConfig `mapstructure:"registries" yaml:"registries,omitempty"`
+	Trust              TrustConfig      `mapstructure:"trust" yaml:"trust,omitempty"`
+
+	// Offline serves registry data from the cache only. It is not saved.
+	Offline bool `mapstructure:"-" yaml:"-"`
+}
+
+// RegistryConfig describes one skill source. Type is skillsmp, git, dir or
+// index; URL, Path and Ref apply depending on the type. Lower Priority
+// values are consulted first. Scope lists name patterns such as "@acme/*"
+// that resolve only from this registry. Credentials references a secret as
+// "env:VAR", "file:path" or "helper:command" so tokens stay out of
+// config.yaml.
+//
+// Proxy overrides the HTTPS_PROXY environment ("direct" disables proxying),
+// CACert names a PEM bundle trusted in addition to the system roots, and
+// ClientCert/ClientKey a certificate presented to registries that ask for one.
+type RegistryConfig struct {
+	Name        string   `mapstructure:"name" yaml:"name"`
+	Type        string   `mapstructure:"type" yaml:"type"`
+	URL         string   `mapstructure:"url" yaml:"url,omitempty"`
+	Path        string   `mapstructure:"path" yaml:"path,omitempty"`
+	Ref         string   `mapstructure:"ref" yaml:"ref,omitempty"`
+	APIKey      string   `mapstructure:"apiKey" yaml:"apiKey,omitempty"`
+	Credentials string   `mapstructure:"credentials" yaml:"credentials,omitempty"`
+	Priority    int      `mapstructure:"priority" yaml:"priority,omitempty"`
+	Scope       []string `mapstructure:"scope" yaml:"scope,omitempty"`
+	Proxy       string   `mapstructure:"proxy" yaml:"proxy,omitempty"`
+	CACert      string   `mapstructure:"caCert" yaml:"caCert,omitempty"`
+	ClientCert  string   `mapstructure:"clientCert" yaml:"clientCert,omitempty"`
+	ClientKey   string   `mapstructure:"clientKey" yaml:"clientKey,omitempty"`
+}
+
+// TrustConfig is the signature policy. Keys lists the public keys whose
+// signatures are trusted. Unsigned and Invalid are the actions (allow, warn
+// or deny) for skills that are unsigned or signed by an unknown key, and
+// for signatures that do not match; they default to allow and warn. The
+// first rule matching a skill's registry or scope overrides them.
+type TrustConfig struct {
+	Keys     []TrustedKey `mapstructure:"keys" yaml:"keys,omitempty"`
+	Unsigned string       `mapstructure:"unsigned" yaml:"unsigned,omitempty"`
+	Invalid  string       `mapstructure:"invalid" yaml:"invalid,omitempty"`
+	Rules    []TrustRule  `mapstructure:"rules" yaml:"rules,omitempty"`
+}
+
+// TrustedKey names a public key as printed by `pskill sign --keygen`.
+type TrustedKey struct {
+	Name      string `mapstructure:"name" yaml:"name"`
+	PublicKey string `mapstructure:"publicKey" yaml:"publicKey"`
+}
+
+// TrustRule applies to skills from Registry, or whose name or registry
+// scope matches Scope (such as "@acme/*"); a rule may set both. Keys limits
+// the trusted keys to those names. Empty actions fall back to TrustConfig.
+type TrustRule struct {
+	Registry string   `mapstructure:"registry" yaml:"registry,omitempty"`
+	Scope    string   `mapstructure:"scope" yaml:"scope,omitempty"`
+	Keys     []string `mapstructure:"keys" yaml:"keys,omitempty"`
+	Unsigned string   `mapstructure:"unsigned" yaml:"unsigned,omitempty"`
+	Invalid  string   `mapstructure:"invalid" yaml:"invalid,omitempty"`
 }
 
 func defaultHome() string {
@@ -57,6 +125,7 @@ func defaults() Config {
 		TargetCLIs:         []string{"cursor", "claude", "codex"},
 		DefaultSkills:      []string{},
 		AutoUpdateTrending: true,
+		CacheMaxMB:         200,
 	}
 }
 
@@ -83,6 +152,7 @@ func LoadGlobal() (Config, error) {
 	if err := v.Unmarshal(&cfg); err != nil {
 		return Config{}, err
 	}
+	cfg.Offline = offline
 	// Ensure config dirs exist (use loaded config, not defaults)
 	_ = os.MkdirAll(cfg.StoreDir, 0o755)
 	_ = os.MkdirAll(cfg.CacheDir, 0o755)
@@ -112,4 +182,5 @@ func setDefaults(v *viper.Viper, cfg Config) {
 	v.SetDefault("targetClis", cfg.TargetCLIs)
 	v.SetDefault("defaultSkills", cfg.DefaultSkills)
 	v.SetDefault("autoUpdateTrending", cfg.AutoUpdateTrending)
+	v.SetDefault("cacheMaxMb", cfg.CacheMaxMB)
 }
@@ -0,0 +1,292 @@
+package diff
+
+import (
+	"bytes"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/ZiaoLiu-1/pskill/internal/store"
+)
+
+// File is the difference for one path inside two skill directories.
+type File struct {
+	Path    string `json:"path"`
+	Status  string `json:"status"` // "modified", "added", "removed"
+	Added   int    `json:"added"`
+	Removed int    `json:"removed"`
+	Binary  bool   `json:"binary"`
+	Unified string `json:"unified,omitempty"`
+}
+
+type op struct {
+	kind byte // ' ', '-', '+'
+	line string
+}
+
+// Dirs compares every file under a and b. Either directory may be missing,
+// in which case all files on the other side are reported added or removed.
+func Dirs(a, b, labelA, labelB string) ([]File, error) {
+	filesA, err := listFiles(a)
+	if err != nil {
+		return nil, err
+	}
+	filesB, err := listFiles(b)
+	if err != nil {
+		return nil, err
+	}
+	paths := map[string]bool{}
+	for p := range filesA {
+		paths[p] = true
+	}
+	for p := range filesB {
+		paths[p] = true
+	}
+	sorted := make([]string, 0, len(paths))
+	for p := range paths {
+		sorted = append(sorted, p)
+	}
+	sort.Strings(sorted)
+
+	var out []File
+	for _, p := range sorted {
+		var rawA, rawB []byte
+		if filesA[p] {
+			if rawA, err = os.ReadFile(filepath.Join(a, p)); err != nil {
+				return nil, err
+			}
+		}
+		if filesB[p] {
+			if rawB, err = os.ReadFile(filepath.Join(b, p)); err != nil {
+				return nil, err
+			}
+		}
+		if filesA[p] && filesB[p] && bytes.Equal(rawA, rawB) {
+			continue
+		}
+		f := File{Path: p, Status: "modified"}
+		nameA, nameB := labelA+"/"+p, labelB+"/"+p
+		switch {
+		case !filesA[p]:
+			f.Status = "added"
+			nameA = "/dev/null"
+		case !filesB[p]:
+			f.Status = "removed"
+			nameB = "/dev/null"
+		}
+		if isBinary(rawA) || isBinary(rawB) {
+			f.Binary = true
+			f.Unified = fmt.Sprintf("Binary files %s and %s differ\n", nameA, nameB)
+			out = append(out, f)
+			continue
+		}
+		f.Unified, f.Added, f.Removed = Unified(nameA, nameB, string(rawA), string(rawB), 3)
+		out = append(out, f)
+	}
+	return out, nil
+}
+
+// Unified renders a unified diff of two texts with n lines of context and
+// returns it along with the added and removed line counts.
+func Unified(nameA, nameB, a, b string, n int) (string, int, int) {
+	ops := lineOps(splitLines(a), splitLines(b))
+	added, removed := 0, 0
+	for _, o := range ops {
+		switch o.kind {
+		case '+':
+			added++
+		case '-':
+			removed++
+		}
+	}
+	if added == 0 && removed == 0 {
+		return "", 0, 0
+	}
+
+	var buf strings.Builder
+	fmt.Fprintf(&buf, "--- %s\n+++ %s\n", nameA, nameB)
+	for start := 0; start < len(ops); {
+		// find next change
+		for start < len(ops) && ops[start].kind == ' ' {
+			start++
+		}
+		if start >= len(ops) {
+			break
+		}
+		lo := start - n
+		if lo < 0 {
+			lo = 0
+		}
+		hi := start
+		for hi < len(ops) {
+			if ops[hi].kind != ' ' {
+				hi++
+				continue
+			}
+			// extend while the gap to the next change is within 2n
+			gap := hi
+			for gap < len(ops) && ops[gap].kind == ' ' {
+				gap++
+			}
+			if gap < len(ops) && gap-hi <= 2*n {
+				hi = gap
+				continue
+			}
+			hi += n
+			if hi > len(ops) {
+				hi = len(ops)
+			}
+			break
+		}
+		aStart, bStart := 1, 1
+		for _, o := range ops[:lo] {
+			if o.kind != '+' {
+				aStart++
+			}
+			if o.kind != '-' {
+				bStart++
+			}
+		}
+		aLen, bLen := 0, 0
+		for _, o := range ops[lo:hi] {
+			if o.kind != '+' {
+				aLen++
+			}
+			if o.kind != '-' {
+				bLen++
+			}
+		}
+		fmt.Fprintf(&buf, "@@ -%s +%s @@\n", hunkRange(aStart, aLen), hunkRange(bStart, bLen))
+		for _, o := range ops[lo:hi] {
+			buf.WriteByte(o.kind)
+			buf.WriteString(o.line)
+			buf.WriteByte('\n')
+		}
+		start = hi
+	}
+	return buf.String(), added, removed
+}
+
+// Stat renders a git-style --stat summary.
+func Stat(files []File) string {
+	var b strings.Builder
+	width := 0
+	for _, f := range files {
+		if len(f.Path) > width {
+			width = len(f.Path)
+		}
+	}
+	totalAdd, totalDel := 0, 0
+	for _, f := range files {
+		if f.Binary {
+			fmt.Fprintf(&b, " %-*s | Bin\n", width, f.Path)
+			continue
+		}
+		bar := strings.Repeat("+", min(f.Added, 40)) + strings.Repeat("-", min(f.Removed, 40))
+		fmt.Fprintf(&b, " %-*s | %4d %s\n", width, f.Path, f.Added+f.Removed, bar)
+		totalAdd += f.Added
+		totalDel += f.Removed
+	}
+	fmt.Fprintf(&b, " %d files changed, %d insertions(+), %d deletions(-)\n", len(files), totalAdd, totalDel)
+	return b.String()
+}
+
+func hunkRange(start, length int) string {
+	if length == 0 {
+		return fmt.Sprintf("%d,0", start-1)
+	}
+	if length == 1 {
+		return fmt.Sprintf("%d", start)
+	}
+	return fmt.Sprintf("%d,%d", start, length)
+}
+
+// lineOps computes an edit script via longest common subsequence. Skill
+// files are small, so the quadratic table is fine.
+func lineOps(a, b []string) []op {
+	n, m := len(a), len(b)
+	lcs := make([][]int, n+1)
+	for i := range lcs {
+		lcs[i] = make([]int, m+1)
+	}
+	for i := n - 1; i >= 0; i-- {
+		for j := m - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else if lcs[i+1][j] >= lcs[i][j+1] {
+				lcs[i][j] = lcs[i+1][j]
+			} else {
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+	ops := make([]op, 0, n+m)
+	i, j := 0, 0
+	for i < n && j < m {
+		switch {
+		case a[i] == b[j]:
+			ops = append(ops, op{' ', a[i]})
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			ops = append(ops, op{'-', a[i]})
+			i++
+		default:
+			ops = append(ops, op{'+', b[j]})
+			j++
+		}
+	}
+	for ; i < n; i++ {
+		ops = append(ops, op{'-', a[i]})
+	}
+	for ; j < m; j++ {
+		ops = append(ops, op{'+', b[j]})
+	}
+	return ops
+}
+
+func splitLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(s, "\n"), "\n")
+}
+
+func isBinary(b []byte) bool {
+	n := len(b)
+	if n > 8000 {
+		n = 8000
+	}
+	return bytes.IndexByte(b[:n], 0) >= 0
+}
+
+func listFiles(root string) (map[string]bool, error) {
+	out := map[string]bool{}
+	if root == "" {
+		return out, nil
+	}
+	if _, err := os.Stat(root); os.IsNotExist(err) {
+		return out, nil
+	}
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if d.Name() == ".git" {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		rel, _ := filepath.Rel(root, path)
+		if store.IsBookkeeping(rel) {
+			return nil
+		}
+		out[filepath.ToSlash(rel)] = true
+		return nil
+	})
+	return out, err
+}
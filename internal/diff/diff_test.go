@@ -0,0 +1,75 @@
+package diff
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestUnified_Hunk(t *testing.T) {
+	a := "one\ntwo\nthree\nfour\n"
+	b := "one\n2\nthree\nfour\nfive\n"
+	out, added, removed := Unified("a/x", "b/x", a, b, 1)
+	if added != 2 || removed != 1 {
+		t.Fatalf("added=%d removed=%d, want 2/1", added, removed)
+	}
+	want := "--- a/x\n+++ b/x\n@@ -1,4 +1,5 @@\n one\n-two\n+2\n three\n four\n+five\n"
+	if out != want {
+		t.Errorf("unexpected diff:\n%s\nwant:\n%s", out, want)
+	}
+}
+
+func TestUnified_Equal(t *testing.T) {
+	if out, _, _ := Unified("a", "b", "same\n", "same\n", 3); out != "" {
+		t.Errorf("expected empty diff, got %q", out)
+	}
+}
+
+func TestUnified_SeparateHunks(t *testing.T) {
+	var a, b []string
+	for i := 0; i < 20; i++ {
+		a = append(a, "line")
+		b = append(b, "line")
+	}
+	a[2], b[2] = "old", "new"
+	a[17], b[17] = "old", "new"
+	out, _, _ := Unified("a", "b", strings.Join(a, "\n"), strings.Join(b, "\n"), 3)
+	if n := strings.Count(out, "@@ -"); n != 2 {
+		t.Errorf("expected 2 hunks, got %d:\n%s", n, out)
+	}
+}
+
+func TestDirs(t *testing.T) {
+	a, b := t.TempDir(), t.TempDir()
+	os.WriteFile(filepath.Join(a, "SKILL.md"), []byte("hello\n"), 0o644)
+	os.WriteFile(filepath.Join(b, "SKILL.md"), []byte("hello world\n"), 0o644)
+	os.WriteFile(filepath.Join(a, "old.txt"), []byte("x\n"), 0o644)
+	os.MkdirAll(filepath.Join(b, "scripts"), 0o755)
+	os.WriteFile(filepath.Join(b, "scripts", "run.sh"), []byte("echo\n"), 0o644)
+	os.WriteFile(filepath.Join(b, ".pskill-meta.json"), []byte("{}"), 0o644)
+	os.WriteFile(filepath.Join(a, ".pskill-scan.json"), []byte("{}"), 0o644)
+	// Only the top-level bookkeeping names are pskill's own.
+	os.WriteFile(filepath.Join(b, "scripts", ".pskill-meta.json"), []byte("{}\n"), 0o644)
+
+	files, err := Dirs(a, b, "store", "upstream")
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := map[string]string{}
+	for _, f := range files {
+		got[f.Path] = f.Status
+	}
+	want := map[string]string{"SKILL.md": "modified", "old.txt": "removed", "scripts/run.sh": "added", "scripts/.pskill-meta.json": "added"}
+	if len(got) != len(want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	for p, s := range want {
+		if got[p] != s {
+			t.Errorf("%s: status %q, want %q", p, got[p], s)
+		}
+	}
+	if stat := Stat(files); !strings.Contains(stat, "4 files changed, 3 insertions(+), 2 deletions(-)") {
+		t.Errorf("unexpected stat:\n%s", stat)
+	}
+}
@@ -0,0 +1,108 @@
+package installer
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/ZiaoLiu-1/pskill/internal/config"
+	"github.com/ZiaoLiu-1/pskill/internal/registry"
+	"github.com/ZiaoLiu-1/pskill/internal/source"
+	"github.com/ZiaoLiu-1/pskill/internal/store"
+)
+
+// FetchUpstream retrieves a fresh copy of a stored skill from the source
+// recorded in its meta. The caller must invoke cleanup when done.
+func FetchUpstream(ctx context.Context, cfg config.Config, name string) (string, func(), error) {
+	noop := func() {}
+	st := store.NewManager(cfg.StoreDir)
+	if target, ok := st.LinkTarget(name); ok {
+		return target, noop, nil
+	}
+	meta, err := st.ReadMeta(name)
+	if err != nil {
+		return "", noop, fmt.Errorf("%s has no recorded source", name)
+	}
+	switch meta.SourceType {
+	case store.SourceLocal, store.SourceImport:
+		return meta.SourceURL, noop, nil
+	case store.SourceGit:
+		dir, err := source.NewGit(cfg.CacheDir).SetOffline(cfg.Offline).Fetch(ctx, meta.SourceURL, meta.Ref)
+		if err != nil {
+			return "", noop, err
+		}
+		return filepath.Join(dir, filepath.FromSlash(meta.Subdir)), noop, nil
+	case store.SourceRegistry:
+		tmp, err := os.MkdirTemp("", "pskill-upstream-")
+		if err != nil {
+			return "", noop, err
+		}
+		cleanup := func() { _ = os.RemoveAll(tmp) }
+		reg := registry.FromConfig(cfg)
+		var result registry.SkillResult
+		if meta.Registry != "" {
+			result, err = reg.GetFrom(ctx, meta.Registry, name)
+		} else {
+			result, err = reg.Get(ctx, name)
+		}
+		if err != nil {
+			cleanup()
+			return "", noop, fmt.Errorf("resolve %s: %w", name, err)
+		}
+		if err := reg.Fetch(ctx, result, tmp); err != nil {
+			cleanup()
+			return "", noop, err
+		}
+		return tmp, cleanup, nil
+	default:
+		return "", noop, fmt.Errorf("%s: unknown source type %q", name, meta.SourceType)
+	}
+}
+
+// ProjectCopy finds the project-local instance of a skill for the first
+// target CLI that has one. Copies and rendered dirs are preferred over
+// symlinks, which always match the store.
+func ProjectCopy(projectDir string, targets []string, name string) (string, string) {
+	dir, cli := "", ""
+	for _, t := range targets {
+		base := ProjectCLISkillDir(projectDir, t)
+		if base == "" {
+			continue
+		}
+		p := filepath.Join(base, name)
+		fi, err := os.Lstat(p)
+		if err != nil {
+			continue
+		}
+		if fi.Mode()&os.ModeSymlink == 0 {
+			return p, t
+		}
+		if dir == "" {
+			dir, cli = p, t
+		}
+	}
+	return dir, cli
+}
+
+// ExpectedProjectCopy renders what pskill would place in a project for a
+// skill, so comparing it with ProjectCopy shows hand edits and stale
+// renders rather than template placeholders. Plain skills return the
+// store dir unchanged.
+func ExpectedProjectCopy(cfg config.Config, name string, vars map[string]string) (string, func(), error) {
+	noop := func() {}
+	st := store.NewManager(cfg.StoreDir)
+	if !st.IsTemplated(name) {
+		return st.SkillDir(name), noop, nil
+	}
+	tmp, err := os.MkdirTemp("", "pskill-render-")
+	if err != nil {
+		return "", noop, err
+	}
+	cleanup := func() { _ = os.RemoveAll(tmp) }
+	if err := st.RenderSkill(name, filepath.Join(tmp, name), vars, true); err != nil {
+		cleanup()
+		return "", noop, err
+	}
+	return filepath.Join(tmp, name), cleanup, nil
+}
@@ -1,6 +1,7 @@
 package installer
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -12,21 +13,25 @@ import (
 	"github.com/ZiaoLiu-1/pskill/internal/project"
 	"github.com/ZiaoLiu-1/pskill/internal/registry"
 	"github.com/ZiaoLiu-1/pskill/internal/search"
+	"github.com/ZiaoLiu-1/pskill/internal/security"
 	"github.com/ZiaoLiu-1/pskill/internal/store"
+	"github.com/ZiaoLiu-1/pskill/internal/tokens"
 )
 
 // Result reports what happened during an install.
 type Result struct {
 	SkillName   string
 	StorePath   string
-	LinkedCLIs  []string // e.g. "cursor (project)", "claude (global)"
-	ProjectPath string   // cwd if project manifest was updated
+	LinkedCLIs  []string         // e.g. "cursor (project)", "claude (global)"
+	ProjectPath string           // cwd if project manifest was updated
+	Quarantined *security.Report // set when the skill was held for review instead of linked
+	Warnings    []string         // links that could not be made; the install still succeeded
 }
 
 // InstallFromRegistryResult downloads a skill into the central store,
 // symlinks it into project-local AND global CLI skill directories,
 // indexes for local search, records to monitor, and updates pskill.yaml.
-func InstallFromRegistryResult(cfg config.Config, result registry.SkillResult, markProject bool) (*Result, error) {
+func InstallFromRegistryResult(ctx context.Context, cfg config.Config, result registry.SkillResult, markProject bool) (*Result, error) {
 	skillName := strings.TrimSpace(result.Name)
 	if skillName == "" {
 		return nil, fmt.Errorf("skill name is empty")
@@ -36,6 +41,11 @@ func InstallFromRegistryResult(cfg config.Config, result registry.SkillResult, m
 
 	// 1. Download into central store
 	st := store.NewManager(cfg.StoreDir)
+	if st.IsQuarantined(skillName) {
+		rep, _ := QuarantineReport(cfg, skillName)
+		res.StorePath, res.Quarantined = st.QuarantineDir(skillName), &rep
+		return res, nil
+	}
 	destPath, exists, err := st.EnsureSkillDir(skillName)
 	if err != nil {
 		return nil, fmt.Errorf("create store dir: %w", err)
@@ -43,10 +53,28 @@ func InstallFromRegistryResult(cfg config.Config, result registry.SkillResult, m
 	res.StorePath = destPath
 
 	if !exists {
-		client := registry.NewClient(cfg.RegistryURL, cfg.CacheDir, cfg.RegistryAPIKey)
-		if err := client.DownloadSkill(skillName, result.GithubURL, destPath); err != nil {
+		if err := registry.FromConfig(cfg).Fetch(ctx, result, destPath); err != nil {
 			return nil, fmt.Errorf("download: %w", err)
 		}
+		_ = st.WriteMeta(skillName, st.Stamp(skillName, store.Meta{
+			SourceType:  store.SourceRegistry,
+			SourceURL:   result.GithubURL,
+			Registry:    result.Registry,
+			ID:          result.ID,
+			Version:     result.Version,
+			Author:      result.Author,
+			InstalledBy: "pskill (tui)",
+		}))
+	}
+	// Vet existing entries too: an earlier install may have died before
+	// its scan.
+	rep, quarantined, err := Vet(cfg, skillName)
+	if err != nil {
+		return nil, fmt.Errorf("scan: %w", err)
+	}
+	if quarantined {
+		res.StorePath, res.Quarantined = st.QuarantineDir(skillName), &rep
+		return res, nil
 	}
 
 	// 2. Symlink into global CLI skill directories (~/.cursor/skills/, etc.)
@@ -57,30 +85,40 @@ func InstallFromRegistryResult(cfg config.Config, result registry.SkillResult, m
 			continue
 		}
 		globalDir := ad.SkillDir()
-		if err := st.LinkSkillToCLI(skillName, globalDir); err == nil {
-			res.LinkedCLIs = append(res.LinkedCLIs, target+" (global)")
+		if err := st.LinkSkillToCLI(skillName, globalDir); err != nil {
+			res.Warnings = append(res.Warnings, fmt.Sprintf("unable to link %s to %s: %v", skillName, target, err))
+			continue
 		}
+		res.LinkedCLIs = append(res.LinkedCLIs, target+" (global)")
 	}
 
-	// 3. Symlink into project-local CLI skill directories (<cwd>/.cursor/skills/, etc.)
+	// 3. Symlink (or render templated skills) into project-local CLI skill
+	// directories (<cwd>/.cursor/skills/, etc.)
 	wd, _ := os.Getwd()
 	if wd != "" {
+		var vars map[string]string
+		if m, err := project.Load(wd); err == nil {
+			vars = m.Vars
+		}
 		for _, target := range cfg.TargetCLIs {
-			localDir := projectCLISkillDir(wd, target)
+			localDir := ProjectCLISkillDir(wd, target)
 			if localDir == "" {
 				continue
 			}
-			if err := st.LinkSkillToCLI(skillName, localDir); err == nil {
+			rendered, err := st.LinkSkillToProject(skillName, localDir, vars, false)
+			if err != nil {
+				res.Warnings = append(res.Warnings, fmt.Sprintf("unable to link %s to %s (project): %v", skillName, target, err))
+				continue
+			}
+			if rendered {
+				res.LinkedCLIs = append(res.LinkedCLIs, target+" (project, rendered)")
+			} else {
 				res.LinkedCLIs = append(res.LinkedCLIs, target+" (project)")
 			}
 		}
 	}
 
-	// 4. Index for local search
-	engine := search.NewEngine(cfg.IndexDir)
-	_ = engine.IndexSkillByPath(skillName, destPath)
-
-	// 5. Update project manifest (pskill.yaml)
+	// 4. Update project manifest (pskill.yaml)
 	if markProject && wd != "" {
 		manifest, err := project.Load(wd)
 		if err != nil {
@@ -93,9 +131,14 @@ func InstallFromRegistryResult(cfg config.Config, result registry.SkillResult, m
 		manifest.Installed = appendIfMissing(manifest.Installed, skillName)
 		manifest.TargetCLIs = cfg.TargetCLIs
 		_ = project.Save(wd, manifest)
+		_ = st.SetProject(skillName, wd, true)
 		res.ProjectPath = wd
 	}
 
+	// 5. Index for local search, after the links and manifest it reports
+	engine := search.NewStoreEngine(cfg.IndexDir, cfg.StoreDir)
+	_ = engine.IndexSkillByPath(skillName, destPath)
+
 	// 6. Record usage event
 	if tr, err := monitor.NewTracker(cfg.StatsDB); err == nil {
 		cliName := "global"
@@ -114,9 +157,9 @@ func InstallFromRegistryResult(cfg config.Config, result registry.SkillResult, m
 	return res, nil
 }
 
-// projectCLISkillDir returns the project-local skills directory for a CLI.
+// ProjectCLISkillDir returns the project-local skills directory for a CLI.
 // e.g. for "cursor" in /Users/me/myproject → /Users/me/myproject/.cursor/skills
-func projectCLISkillDir(projectDir, cliName string) string {
+func ProjectCLISkillDir(projectDir, cliName string) string {
 	switch cliName {
 	case "cursor":
 		return filepath.Join(projectDir, ".cursor", "skills")
@@ -129,6 +172,26 @@ func projectCLISkillDir(projectDir, cliName string) string {
 	}
 }
 
+// TokenUsage estimates the skill tokens each target CLI sees from projectDir:
+// its global skill dir plus the project-local one. projectDir may be empty.
+func TokenUsage(targets []string, projectDir string) []tokens.Usage {
+	adapters := adapter.All()
+	out := make([]tokens.Usage, 0, len(targets))
+	for _, t := range targets {
+		t = strings.TrimSpace(t)
+		ad, ok := adapters[t]
+		if !ok || !ad.SupportsSkills() {
+			continue
+		}
+		dirs := []string{ad.SkillDir()}
+		if projectDir != "" {
+			dirs = append(dirs, ProjectCLISkillDir(projectDir, t))
+		}
+		out = append(out, tokens.ForCLI(t, dirs...))
+	}
+	return out
+}
+
 // UninstallFromProject removes symlinks from project-local CLI dirs
 // and removes the skill from pskill.yaml. Does NOT remove from central store
 // or global CLI dirs (other projects may still use it).
@@ -138,14 +201,17 @@ func UninstallFromProject(cfg config.Config, skillName string) error {
 		return fmt.Errorf("cannot determine working directory")
 	}
 
-	// Remove project-local symlinks
+	// Remove project-local symlinks and rendered copies
+	var firstErr error
 	for _, target := range cfg.TargetCLIs {
-		localDir := projectCLISkillDir(wd, target)
+		localDir := ProjectCLISkillDir(wd, target)
 		if localDir == "" {
 			continue
 		}
 		linkPath := filepath.Join(localDir, skillName)
-		_ = os.Remove(linkPath)
+		if err := store.RemoveProjectCopy(linkPath, false); err != nil && firstErr == nil {
+			firstErr = err
+		}
 	}
 
 	// Update project manifest
@@ -154,6 +220,9 @@ func UninstallFromProject(cfg config.Config, skillName string) error {
 		manifest.Installed = removeItem(manifest.Installed, skillName)
 		_ = project.Save(wd, manifest)
 	}
+	st := store.NewManager(cfg.StoreDir)
+	_ = st.SetProject(skillName, wd, false)
+	_ = search.NewStoreEngine(cfg.IndexDir, cfg.StoreDir).IndexSkillByPath(skillName, st.SkillDir(skillName))
 
 	// Record event
 	if tr, err := monitor.NewTracker(cfg.StatsDB); err == nil {
@@ -166,7 +235,7 @@ func UninstallFromProject(cfg config.Config, skillName string) error {
 		_ = tr.Close()
 	}
 
-	return nil
+	return firstErr
 }
 
 func appendIfMissing(items []string, item string) []string {
@@ -0,0 +1,118 @@
+package installer
+
+import (
+	"context"
+	"path/filepath"
+	"time"
+
+	"github.com/ZiaoLiu-1/pskill/internal/config"
+	"github.com/ZiaoLiu-1/pskill/internal/registry"
+	"github.com/ZiaoLiu-1/pskill/internal/source"
+	"github.com/ZiaoLiu-1/pskill/internal/store"
+)
+
+// Status compares a stored skill with the source in its meta.
+type Status struct {
+	Name     string `json:"name"`
+	Source   string `json:"source"`
+	Current  string `json:"current"`
+	Latest   string `json:"latest"`
+	Outdated bool   `json:"outdated"`
+	Err      string `json:"error,omitempty"`
+}
+
+// CheckOutdated reports, for each named skill with a recorded source, the
+// installed and upstream revision: a commit for git, a version or update
+// time for registries and a content hash for local directories. Linked dev
+// skills and skills without meta are left out. Repositories are fetched
+// once per URL and ref.
+func CheckOutdated(ctx context.Context, cfg config.Config, names []string) []Status {
+	st := store.NewManager(cfg.StoreDir)
+	git := source.NewGit(cfg.CacheDir).SetOffline(cfg.Offline)
+	reg := registry.FromConfig(cfg)
+	heads := map[string]string{}
+	errs := map[string]error{}
+
+	var out []Status
+	for _, name := range names {
+		if ctx.Err() != nil {
+			break
+		}
+		if _, ok := st.LinkTarget(name); ok {
+			continue
+		}
+		meta, err := st.ReadMeta(name)
+		if err != nil {
+			continue
+		}
+		s := Status{Name: name, Source: meta.SourceType}
+		switch meta.SourceType {
+		case store.SourceGit:
+			key := meta.SourceURL + "@" + meta.Ref
+			if _, seen := heads[key]; !seen && errs[key] == nil {
+				dir, err := git.Fetch(ctx, meta.SourceURL, meta.Ref)
+				if err == nil {
+					heads[key], err = git.Head(dir)
+				}
+				errs[key] = err
+			}
+			s.Current, s.Latest = ShortHash(meta.Commit), ShortHash(heads[key])
+			if err := errs[key]; err != nil {
+				s.Err = err.Error()
+			} else {
+				s.Outdated = meta.Commit != heads[key]
+			}
+		case store.SourceRegistry:
+			var r registry.SkillResult
+			if meta.Registry != "" {
+				r, err = reg.GetFrom(ctx, meta.Registry, name)
+			} else {
+				r, err = reg.Get(ctx, name)
+			}
+			switch {
+			case err != nil:
+				s.Err = err.Error()
+			case r.Version != "" && meta.Version != "":
+				s.Current, s.Latest = meta.Version, r.Version
+				s.Outdated = r.Version != meta.Version
+			default:
+				// Without versions, a registry update after ours means new content.
+				s.Current = meta.UpdatedAt.Local().Format("2006-01-02")
+				s.Latest = s.Current
+				if r.UpdatedAt > 0 {
+					s.Latest = time.Unix(r.UpdatedAt, 0).Local().Format("2006-01-02")
+					s.Outdated = r.UpdatedAt > meta.UpdatedAt.Unix()
+				}
+			}
+		case store.SourceLocal, store.SourceImport:
+			hashSource := store.ContentHash
+			if meta.SourceType == store.SourceImport {
+				// Imports copy only SKILL.md; other files in the CLI dir are not ours.
+				hashSource = store.SkillFileHash
+			}
+			h, err := hashSource(filepath.Clean(meta.SourceURL))
+			s.Current = ShortHash(meta.Hash)
+			if err != nil {
+				s.Err = err.Error()
+				break
+			}
+			s.Latest = ShortHash(h)
+			s.Outdated = meta.Hash != "" && h != meta.Hash
+		default:
+			continue
+		}
+		out = append(out, s)
+	}
+	return out
+}
+
+// ShortHash trims a commit or "sha256:" content hash for display.
+func ShortHash(h string) string {
+	if len(h) > 7 && h[:7] == "sha256:" {
+		h = h[7:]
+	}
+	if len(h) > 12 {
+		return h[:12]
+	}
+	return h
+}
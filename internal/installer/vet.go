@@ -0,0 +1,110 @@
+package installer
+
+import (
+	"fmt"
+
+	"github.com/ZiaoLiu-1/pskill/internal/config"
+	"github.com/ZiaoLiu-1/pskill/internal/search"
+	"github.com/ZiaoLiu-1/pskill/internal/security"
+	"github.com/ZiaoLiu-1/pskill/internal/signing"
+	"github.com/ZiaoLiu-1/pskill/internal/store"
+)
+
+// Vet scans a store entry that was just installed or updated, checks its
+// signature against the trust policy and saves the report next to it. A
+// risky entry is moved into quarantine unless the user already approved
+// exactly this content; one the policy denies is quarantined regardless.
+// It reports whether the entry was quarantined; linked working directories
+// are not checked.
+func Vet(cfg config.Config, name string) (security.Report, bool, error) {
+	st := store.NewManager(cfg.StoreDir)
+	if _, ok := st.LinkTarget(name); ok {
+		return security.Report{}, false, nil
+	}
+	dir := st.SkillDir(name)
+	rep, err := security.Scan(dir)
+	if err != nil {
+		return rep, false, err
+	}
+	meta, _ := st.ReadMeta(name)
+	decision, err := signing.Check(cfg, dir, meta.Registry, name)
+	if err != nil {
+		return rep, false, err
+	}
+	if f, ok := signatureFinding(decision); ok {
+		rep.Findings = append(rep.Findings, f)
+	}
+	if err := security.WriteReport(dir, rep); err != nil {
+		return rep, false, err
+	}
+	if decision.Action == signing.Deny {
+		return rep, true, quarantine(cfg, st, name)
+	}
+	if !rep.Risky() {
+		return rep, false, nil
+	}
+	if meta.Approved != "" {
+		if h, err := store.ContentHash(dir); err == nil && h == meta.Approved {
+			return rep, false, nil
+		}
+	}
+	return rep, true, quarantine(cfg, st, name)
+}
+
+// quarantine moves name into quarantine and out of the search index, so an
+// update that turns risky stops showing up as installed.
+func quarantine(cfg config.Config, st *store.Manager, name string) error {
+	if err := st.Quarantine(name); err != nil {
+		return err
+	}
+	return search.NewStoreEngine(cfg.IndexDir, cfg.StoreDir).DeleteSkill(name)
+}
+
+// signatureFinding reports a signature the policy warns about or denies.
+func signatureFinding(d signing.Decision) (security.Finding, bool) {
+	f := security.Finding{Rule: security.RuleSignature, File: store.SignatureFile,
+		Detail: fmt.Sprintf("%s (trust policy: %s)", d.Verification, d.Action)}
+	switch d.Action {
+	case signing.Deny:
+		f.Severity = security.High
+	case signing.Warn:
+		f.Severity = security.Low
+	default:
+		return f, false
+	}
+	return f, true
+}
+
+// Approve moves a quarantined skill back into the store and records its
+// content as approved, so reinstalling the same content does not
+// quarantine it again. A skill the trust policy denies cannot be approved.
+// The caller links it.
+func Approve(cfg config.Config, name string) error {
+	st := store.NewManager(cfg.StoreDir)
+	if st.IsQuarantined(name) {
+		meta, _ := st.ReadMeta(name)
+		d, err := signing.Check(cfg, st.QuarantineDir(name), meta.Registry, name)
+		if err != nil {
+			return err
+		}
+		if d.Action == signing.Deny {
+			return fmt.Errorf("trust policy denies %s: %s", name, d.Verification)
+		}
+	}
+	if err := st.Release(name); err != nil {
+		return err
+	}
+	meta, err := st.ReadMeta(name)
+	if err != nil {
+		meta = store.Meta{SourceType: store.SourceLocal}
+	}
+	if meta.Approved, err = store.ContentHash(st.SkillDir(name)); err != nil {
+		return err
+	}
+	return st.WriteMeta(name, meta)
+}
+
+// QuarantineReport returns the scan report of a quarantined skill.
+func QuarantineReport(cfg config.Config, name string) (security.Report, error) {
+	return security.ReadReport(store.NewManager(cfg.StoreDir).QuarantineDir(name))
+}
@@ -0,0 +1,179 @@
+package monitor
+
+import (
+	"sort"
+	"time"
+
+	"github.com/ZiaoLiu-1/pskill/internal/registry"
+)
+
+// HistoryDays is how much star history Trends reads and sparklines show.
+// Snapshots older than historyKeep days are deleted.
+const (
+	HistoryDays = 30
+	historyKeep = 90
+)
+
+const dayLayout = "2006-01-02"
+
+// Trend is a skill's star history built from trending snapshots.
+type Trend struct {
+	Key      string
+	Name     string
+	Registry string
+	Stars    int64
+	Daily    int64   // stars gained since the day before the latest snapshot
+	Weekly   int64   // stars gained over the seven days before it
+	Series   []int64 // stars per day, oldest first, ending at the latest snapshot
+}
+
+// SnapshotKey identifies a registry result across snapshots.
+func SnapshotKey(r registry.SkillResult) string {
+	id := r.ID
+	if id == "" {
+		id = r.Name
+	}
+	return r.Registry + "/" + id
+}
+
+// RecordSnapshot stores the stars of each live item as its count for the
+// day of at; a later snapshot on the same day replaces an earlier one.
+// Items served from a stale cache are skipped, since their counts are not
+// from today.
+func (t *Tracker) RecordSnapshot(items []registry.SkillResult, at time.Time) error {
+	tx, err := t.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	day := at.UTC().Format(dayLayout)
+	stmt, err := tx.Prepare(`
+INSERT INTO star_history(skill_key, name, registry, day, stars, updated_at, taken_at)
+VALUES (?, ?, ?, ?, ?, ?, ?)
+ON CONFLICT(skill_key, day) DO UPDATE SET
+  name = excluded.name, stars = excluded.stars, updated_at = excluded.updated_at, taken_at = excluded.taken_at`)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+	for _, r := range items {
+		if !r.CachedAt.IsZero() {
+			continue
+		}
+		if _, err := stmt.Exec(SnapshotKey(r), r.Name, r.Registry, day, r.Stars, r.UpdatedAt, at.UTC()); err != nil {
+			return err
+		}
+	}
+	cutoff := at.UTC().AddDate(0, 0, -historyKeep).Format(dayLayout)
+	if _, err := tx.Exec(`DELETE FROM star_history WHERE day < ?`, cutoff); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// Trends returns the star history of every skill snapshotted in the last
+// HistoryDays days before now, by SnapshotKey.
+func (t *Tracker) Trends(now time.Time) (map[string]Trend, error) {
+	since := now.UTC().AddDate(0, 0, -HistoryDays).Format(dayLayout)
+	rows, err := t.db.Query(`
+SELECT skill_key, name, registry, day, stars FROM star_history
+WHERE day >= ? ORDER BY skill_key, day`, since)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	type point struct {
+		day   time.Time
+		stars int64
+	}
+	history := map[string][]point{}
+	out := map[string]Trend{}
+	for rows.Next() {
+		var key, name, reg, day string
+		var stars int64
+		if err := rows.Scan(&key, &name, &reg, &day, &stars); err != nil {
+			return nil, err
+		}
+		d, err := time.Parse(dayLayout, day)
+		if err != nil {
+			continue
+		}
+		history[key] = append(history[key], point{d, stars})
+		out[key] = Trend{Key: key, Name: name, Registry: reg, Stars: stars}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	for key, pts := range history {
+		// at is the stars on day d: the newest snapshot on or before it,
+		// or the oldest one when history does not reach back that far.
+		at := func(d time.Time) int64 {
+			v := pts[0].stars
+			for _, p := range pts {
+				if p.day.After(d) {
+					break
+				}
+				v = p.stars
+			}
+			return v
+		}
+		tr := out[key]
+		first, last := pts[0].day, pts[len(pts)-1].day
+		for d := first; !d.After(last); d = d.AddDate(0, 0, 1) {
+			tr.Series = append(tr.Series, at(d))
+		}
+		tr.Daily = tr.Stars - at(last.AddDate(0, 0, -1))
+		tr.Weekly = tr.Stars - at(last.AddDate(0, 0, -7))
+		out[key] = tr
+	}
+	return out, nil
+}
+
+// SnapshotTrending records today's stars for items in the stats database
+// at dbPath and returns the star history it now holds, by SnapshotKey. If
+// only the snapshot fails, the history is returned along with the error.
+func SnapshotTrending(dbPath string, items []registry.SkillResult) (map[string]Trend, error) {
+	tr, err := NewTracker(dbPath)
+	if err != nil {
+		return nil, err
+	}
+	defer tr.Close()
+	now := time.Now()
+	recErr := tr.RecordSnapshot(items, now)
+	trends, err := tr.Trends(now)
+	if err != nil {
+		return nil, err
+	}
+	return trends, recErr
+}
+
+// TrendsByName is Trends keyed by skill name, for callers that only know
+// installed names. When several registries list a name, the one with the
+// most stars wins.
+func (t *Tracker) TrendsByName(now time.Time) (map[string]Trend, error) {
+	all, err := t.Trends(now)
+	if err != nil {
+		return nil, err
+	}
+	out := map[string]Trend{}
+	for _, tr := range all {
+		if cur, ok := out[tr.Name]; !ok || tr.Stars > cur.Stars {
+			out[tr.Name] = tr
+		}
+	}
+	return out, nil
+}
+
+// SortRising orders items by stars gained this week, then today, keeping
+// the registry's order among ties.
+func SortRising(items []registry.SkillResult, trends map[string]Trend) {
+	sort.SliceStable(items, func(i, j int) bool {
+		a, b := trends[SnapshotKey(items[i])], trends[SnapshotKey(items[j])]
+		if a.Weekly != b.Weekly {
+			return a.Weekly > b.Weekly
+		}
+		return a.Daily > b.Daily
+	})
+}
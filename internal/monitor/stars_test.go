@@ -0,0 +1,95 @@
+package monitor
+
+import (
+	"fmt"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ZiaoLiu-1/pskill/internal/registry"
+)
+
+func TestRecordSnapshot_Trends(t *testing.T) {
+	tr := newTestTracker(t)
+	day0 := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	snap := func(day int, stars map[string]int64) {
+		t.Helper()
+		var items []registry.SkillResult
+		for name, n := range stars {
+			items = append(items, registry.SkillResult{ID: "id-" + name, Name: name, Registry: "skillsmp", Stars: n})
+		}
+		if err := tr.RecordSnapshot(items, day0.AddDate(0, 0, day)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	snap(0, map[string]int64{"steady": 100, "rocket": 10})
+	snap(3, map[string]int64{"steady": 101, "rocket": 40})
+	snap(8, map[string]int64{"steady": 102, "rocket": 90})
+	snap(9, map[string]int64{"steady": 103, "rocket": 95})
+	// A second snapshot on the same day replaces the first.
+	snap(9, map[string]int64{"steady": 103, "rocket": 100})
+
+	trends, err := tr.Trends(day0.AddDate(0, 0, 9))
+	if err != nil {
+		t.Fatal(err)
+	}
+	rocket := trends["skillsmp/id-rocket"]
+	if rocket.Stars != 100 || rocket.Daily != 10 || rocket.Weekly != 90 {
+		t.Errorf("rocket = %+v, want stars 100, daily +10, weekly +90", rocket)
+	}
+	if got := fmt.Sprint(rocket.Series); got != "[10 10 10 40 40 40 40 40 90 100]" {
+		t.Errorf("series = %s", got)
+	}
+
+	items := []registry.SkillResult{
+		{ID: "id-steady", Name: "steady", Registry: "skillsmp"},
+		{ID: "id-new", Name: "new", Registry: "skillsmp"},
+		{ID: "id-rocket", Name: "rocket", Registry: "skillsmp"},
+	}
+	SortRising(items, trends)
+	if got := items[0].Name + "," + items[1].Name + "," + items[2].Name; got != "rocket,steady,new" {
+		t.Errorf("rising order = %s", got)
+	}
+	byName, err := tr.TrendsByName(day0.AddDate(0, 0, 9))
+	if err != nil || byName["steady"].Weekly != 3 {
+		t.Errorf("TrendsByName steady = %+v, %v", byName["steady"], err)
+	}
+}
+
+func TestRecordSnapshot_SkipsStaleAndPrunes(t *testing.T) {
+	tr := newTestTracker(t)
+	now := time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)
+	old := []registry.SkillResult{{Name: "old", Stars: 1}}
+	if err := tr.RecordSnapshot(old, now.AddDate(0, 0, -historyKeep-1)); err != nil {
+		t.Fatal(err)
+	}
+	items := []registry.SkillResult{
+		{Name: "live", Stars: 5},
+		{Name: "cached", Stars: 7, CachedAt: now.Add(-48 * time.Hour)},
+	}
+	if err := tr.RecordSnapshot(items, now); err != nil {
+		t.Fatal(err)
+	}
+	var n int
+	if err := tr.db.QueryRow(`SELECT COUNT(*) FROM star_history`).Scan(&n); err != nil {
+		t.Fatal(err)
+	}
+	if n != 1 {
+		t.Errorf("rows = %d, want only the live snapshot", n)
+	}
+}
+
+func TestSnapshotTrending(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "stats.db")
+	items := []registry.SkillResult{{ID: "id-a", Name: "a", Registry: "skillsmp", Stars: 7}}
+	trends, err := SnapshotTrending(dbPath, items)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := trends[SnapshotKey(items[0])]; got.Stars != 7 {
+		t.Errorf("trend = %+v, want 7 stars", got)
+	}
+	if _, err := SnapshotTrending(filepath.Join(dbPath, "missing", "stats.db"), items); err == nil {
+		t.Error("expected an error for an unusable database path")
+	}
+}
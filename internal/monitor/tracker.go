@@ -44,6 +44,16 @@ CREATE TABLE IF NOT EXISTS usage (
   project TEXT NOT NULL,
   event_type TEXT NOT NULL,
   timestamp DATETIME NOT NULL
+);
+CREATE TABLE IF NOT EXISTS star_history (
+  skill_key TEXT NOT NULL,
+  name TEXT NOT NULL,
+  registry TEXT NOT NULL,
+  day TEXT NOT NULL,
+  stars INTEGER NOT NULL,
+  updated_at INTEGER NOT NULL,
+  taken_at DATETIME NOT NULL,
+  PRIMARY KEY (skill_key, day)
 );`)
 	return err
 }
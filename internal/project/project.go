@@ -13,6 +13,28 @@ type Manifest struct {
 	TargetCLIs    []string `yaml:"targetClis"`
 	DefaultSkills []string `yaml:"defaultSkills"`
 	Installed     []string `yaml:"installed"`
+	Budget        Budget   `yaml:"budget,omitempty"`
+	// Vars override template variable defaults of templated skills.
+	Vars map[string]string `yaml:"vars,omitempty"`
+}
+
+// Budget caps the estimated context tokens each CLI spends on skills.
+// AlwaysLoaded limits the descriptions loaded into every session, Total
+// limits descriptions plus bodies. Zero means unlimited.
+type Budget struct {
+	AlwaysLoaded int    `yaml:"alwaysLoaded,omitempty"`
+	Total        int    `yaml:"total,omitempty"`
+	OnExceed     string `yaml:"onExceed,omitempty"` // "warn" (default) or "fail"
+}
+
+// IsZero reports whether no limit is configured.
+func (b Budget) IsZero() bool {
+	return b.AlwaysLoaded == 0 && b.Total == 0
+}
+
+// Enforced reports whether exceeding the budget should fail the command.
+func (b Budget) Enforced() bool {
+	return b.OnExceed == "fail"
 }
 
 // Info represents a discovered project on disk.
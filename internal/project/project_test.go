@@ -3,6 +3,7 @@ package project
 import (
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 )
 
@@ -121,3 +122,26 @@ func TestExpandHome(t *testing.T) {
 		t.Errorf("expandHome should not change absolute paths, got %q", got2)
 	}
 }
+
+func TestBudgetRoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	m := Manifest{Name: "b", Budget: Budget{AlwaysLoaded: 2000, OnExceed: "fail"}}
+	if err := Save(dir, m); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := Load(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if loaded.Budget.AlwaysLoaded != 2000 || !loaded.Budget.Enforced() {
+		t.Errorf("unexpected budget: %+v", loaded.Budget)
+	}
+
+	if err := Save(dir, Manifest{Name: "nb"}); err != nil {
+		t.Fatal(err)
+	}
+	raw, _ := os.ReadFile(filepath.Join(dir, "pskill.yaml"))
+	if strings.Contains(string(raw), "budget") {
+		t.Errorf("empty budget should be omitted, got:\n%s", raw)
+	}
+}
@@ -0,0 +1,123 @@
+package registry
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ZiaoLiu-1/pskill/internal/config"
+)
+
+// Registry types accepted in config.yaml.
+const (
+	TypeSkillsMP = "skillsmp"
+	TypeGit      = "git"
+	TypeDir      = "dir"
+	TypeIndex    = "index"
+)
+
+// FromConfig builds the composite registry described by cfg.Registries,
+// ordered by priority. Without any entries it falls back to skillsmp at
+// cfg.RegistryURL.
+func FromConfig(cfg config.Config) *Multi {
+	if len(cfg.Registries) == 0 {
+		r, err := New(config.RegistryConfig{Name: TypeSkillsMP, Type: TypeSkillsMP}, cfg)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "warn: registry %s: %v\n", TypeSkillsMP, err)
+			return NewMulti()
+		}
+		return NewMulti(r)
+	}
+	m := NewMulti()
+	for _, rc := range cfg.Registries {
+		r, err := New(rc, cfg)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "warn: registry %s: %v\n", rc.Name, err)
+			continue
+		}
+		m.Add(r, rc.Priority, rc.Scope)
+	}
+	return m
+}
+
+// configure applies the registry's network settings and the process-wide
+// settings in cfg to a client.
+func configure(c *Client, rc config.RegistryConfig, cfg config.Config) error {
+	h, err := newHTTPClient(rc)
+	if err != nil {
+		return err
+	}
+	c.http = h
+	c.offline = cfg.Offline
+	c.cache.SetMaxSize(int64(cfg.CacheMaxMB) << 20)
+	return nil
+}
+
+// New builds a single registry from its config entry.
+func New(rc config.RegistryConfig, cfg config.Config) (Registry, error) {
+	name := rc.Name
+	if name == "" {
+		name = rc.Type
+	}
+	switch rc.Type {
+	case TypeSkillsMP, "":
+		u := rc.URL
+		if u == "" {
+			u = cfg.RegistryURL
+		}
+		if name == "" {
+			name = TypeSkillsMP
+		}
+		key, err := apiKey(rc, name, cfg)
+		if err != nil {
+			return nil, err
+		}
+		if key == "" {
+			key = cfg.RegistryAPIKey
+		}
+		s := NewSkillsMP(name, u, cfg.CacheDir, key)
+		if err := configure(s.client, rc, cfg); err != nil {
+			return nil, err
+		}
+		return s, nil
+	case TypeGit:
+		if rc.URL == "" {
+			return nil, fmt.Errorf("git registry needs url")
+		}
+		g := NewGitRepo(name, rc.URL, rc.Ref, cfg.CacheDir)
+		g.git.SetOffline(cfg.Offline).SetConfig(gitConfig(rc)...)
+		return g, nil
+	case TypeDir:
+		if rc.Path == "" {
+			return nil, fmt.Errorf("dir registry needs path")
+		}
+		return NewDir(name, expandHome(rc.Path)), nil
+	case TypeIndex:
+		if rc.URL == "" {
+			return nil, fmt.Errorf("index registry needs url")
+		}
+		key, err := apiKey(rc, name, cfg)
+		if err != nil {
+			return nil, err
+		}
+		x := NewIndex(name, rc.URL, cfg.CacheDir, key)
+		if err := configure(x.client, rc, cfg); err != nil {
+			return nil, err
+		}
+		return x, nil
+	default:
+		return nil, fmt.Errorf("unknown type %q", rc.Type)
+	}
+}
+
+func expandHome(p string) string {
+	if !strings.HasPrefix(p, "~/") {
+		return p
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return p
+	}
+	return filepath.Join(home, p[2:])
+}
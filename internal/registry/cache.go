@@ -1,19 +1,40 @@
 package registry
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
+	"net/http"
 	"os"
 	"path/filepath"
+	"sort"
+	"strings"
 	"time"
 )
 
+// Cache keeps registry responses and downloads as one JSON file per key.
+// A file's modification time is its last use, which drives LRU eviction
+// once the total size passes maxSize.
 type Cache struct {
 	cacheDir string
+	maxSize  int64 // bytes; 0 means unbounded
+}
+
+// CacheItem describes one cache file for `pskill cache ls`.
+type CacheItem struct {
+	Key      string    `json:"key"`
+	Size     int64     `json:"size"`
+	StoredAt time.Time `json:"storedAt"`
+	UsedAt   time.Time `json:"usedAt"`
+	Corrupt  bool      `json:"corrupt,omitempty"`
 }
 
 type cacheEntry struct {
-	StoredAt time.Time       `json:"storedAt"`
-	Data     json.RawMessage `json:"data"`
+	StoredAt     time.Time       `json:"storedAt"`
+	ETag         string          `json:"etag,omitempty"`
+	LastModified string          `json:"lastModified,omitempty"`
+	Data         json.RawMessage `json:"data,omitempty"`
+	Body         []byte          `json:"body,omitempty"` // non-JSON payloads such as SKILL.md or archives
 }
 
 func NewCache(cacheDir string) *Cache {
@@ -21,27 +42,229 @@ func NewCache(cacheDir string) *Cache {
 	return &Cache{cacheDir: cacheDir}
 }
 
+// SetMaxSize bounds the cache to n bytes, evicting least recently used
+// entries on write.
+func (c *Cache) SetMaxSize(n int64) {
+	c.maxSize = n
+}
+
 func (c *Cache) Store(key string, value interface{}) {
 	raw, _ := json.Marshal(value)
-	entry := cacheEntry{StoredAt: time.Now(), Data: raw}
-	payload, _ := json.Marshal(entry)
-	_ = os.WriteFile(filepath.Join(c.cacheDir, sanitizeFileName(key)+".json"), payload, 0o644)
+	c.put(key, cacheEntry{StoredAt: time.Now(), Data: raw})
 }
 
 func (c *Cache) Load(key string, ttl time.Duration) ([]byte, bool) {
-	path := filepath.Join(c.cacheDir, sanitizeFileName(key)+".json")
+	data, storedAt, ok := c.Peek(key)
+	if !ok || time.Since(storedAt) > ttl {
+		return nil, false
+	}
+	return data, true
+}
+
+// Peek returns an entry and when it was stored, however old it is.
+func (c *Cache) Peek(key string) ([]byte, time.Time, bool) {
+	entry, ok := c.lookup(key)
+	return entry.Data, entry.StoredAt, ok
+}
+
+// lookup reads an entry and marks it used. Unreadable entries are removed
+// so they are refetched rather than missed forever.
+func (c *Cache) lookup(key string) (cacheEntry, bool) {
+	path := c.path(key)
 	raw, err := os.ReadFile(path)
 	if err != nil {
-		return nil, false
+		return cacheEntry{}, false
 	}
 	var entry cacheEntry
 	if err := json.Unmarshal(raw, &entry); err != nil {
-		return nil, false
+		_ = os.Remove(path)
+		return cacheEntry{}, false
 	}
-	if time.Since(entry.StoredAt) > ttl {
-		return nil, false
+	now := time.Now()
+	_ = os.Chtimes(path, now, now)
+	return entry, true
+}
+
+// put writes an entry atomically so concurrent pskill processes never see
+// a partial file.
+func (c *Cache) put(key string, entry cacheEntry) {
+	payload, err := json.Marshal(entry)
+	if err != nil {
+		return
+	}
+	tmp, err := os.CreateTemp(c.cacheDir, ".tmp-*")
+	if err != nil {
+		return
 	}
-	return entry.Data, true
+	_, werr := tmp.Write(payload)
+	cerr := tmp.Close()
+	if werr != nil || cerr != nil || os.Rename(tmp.Name(), c.path(key)) != nil {
+		_ = os.Remove(tmp.Name())
+		return
+	}
+	if c.maxSize > 0 {
+		_, _, _ = c.evict(c.maxSize)
+	}
+}
+
+// List returns every cache entry, most recently used first.
+func (c *Cache) List() ([]CacheItem, error) {
+	return c.scan(true)
+}
+
+// scan lists entry files; with read set it also decodes each one for its
+// storage time, flagging those that do not parse.
+func (c *Cache) scan(read bool) ([]CacheItem, error) {
+	files, err := os.ReadDir(c.cacheDir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var items []CacheItem
+	for _, f := range files {
+		if f.IsDir() || !strings.HasSuffix(f.Name(), ".json") {
+			continue
+		}
+		info, err := f.Info()
+		if err != nil {
+			continue
+		}
+		item := CacheItem{Key: strings.TrimSuffix(f.Name(), ".json"), Size: info.Size(), UsedAt: info.ModTime()}
+		if read {
+			var head struct {
+				StoredAt time.Time `json:"storedAt"`
+			}
+			if raw, err := os.ReadFile(filepath.Join(c.cacheDir, f.Name())); err != nil || json.Unmarshal(raw, &head) != nil {
+				item.Corrupt = true
+			}
+			item.StoredAt = head.StoredAt
+		}
+		items = append(items, item)
+	}
+	sort.Slice(items, func(i, j int) bool { return items[i].UsedAt.After(items[j].UsedAt) })
+	return items, nil
+}
+
+// Clear removes every entry and returns how many were removed.
+func (c *Cache) Clear() (int, error) {
+	files, err := os.ReadDir(c.cacheDir)
+	if err != nil && !os.IsNotExist(err) {
+		return 0, err
+	}
+	n := 0
+	for _, f := range files {
+		if f.IsDir() || !(strings.HasSuffix(f.Name(), ".json") || strings.HasPrefix(f.Name(), ".tmp-")) {
+			continue
+		}
+		if err := os.Remove(filepath.Join(c.cacheDir, f.Name())); err != nil {
+			return n, err
+		}
+		if strings.HasSuffix(f.Name(), ".json") {
+			n++
+		}
+	}
+	return n, nil
+}
+
+// Prune removes corrupt entries, entries stored more than maxAge ago (when
+// maxAge > 0) and abandoned temp files, then evicts down to the size limit.
+// It returns the number of entries removed and the bytes freed.
+func (c *Cache) Prune(maxAge time.Duration) (int, int64, error) {
+	items, err := c.List()
+	if err != nil {
+		return 0, 0, err
+	}
+	removed, freed := 0, int64(0)
+	for _, it := range items {
+		if it.Corrupt || (maxAge > 0 && time.Since(it.StoredAt) > maxAge) {
+			if os.Remove(filepath.Join(c.cacheDir, it.Key+".json")) == nil {
+				removed++
+				freed += it.Size
+			}
+		}
+	}
+	tmps, _ := filepath.Glob(filepath.Join(c.cacheDir, ".tmp-*"))
+	for _, p := range tmps {
+		// Leave writes that may still be in progress alone.
+		if fi, err := os.Stat(p); err == nil && time.Since(fi.ModTime()) > time.Hour {
+			_ = os.Remove(p)
+		}
+	}
+	if c.maxSize > 0 {
+		n, b, err := c.evict(c.maxSize)
+		removed += n
+		freed += b
+		if err != nil {
+			return removed, freed, err
+		}
+	}
+	return removed, freed, nil
+}
+
+// evict removes least recently used entries until the cache fits in max
+// bytes.
+func (c *Cache) evict(max int64) (int, int64, error) {
+	items, err := c.scan(false)
+	if err != nil {
+		return 0, 0, err
+	}
+	var total int64
+	for _, it := range items {
+		total += it.Size
+	}
+	removed, freed := 0, int64(0)
+	for i := len(items) - 1; i >= 0 && total > max; i-- {
+		if err := os.Remove(filepath.Join(c.cacheDir, items[i].Key+".json")); err != nil && !os.IsNotExist(err) {
+			return removed, freed, err
+		}
+		total -= items[i].Size
+		removed++
+		freed += items[i].Size
+	}
+	return removed, freed, nil
+}
+
+// touch marks entry as fresh again after the server answered 304, picking
+// up any new validators from header.
+func (c *Cache) touch(key string, entry cacheEntry, header http.Header) {
+	entry.StoredAt = time.Now()
+	if v := header.Get("ETag"); v != "" {
+		entry.ETag = v
+	}
+	if v := header.Get("Last-Modified"); v != "" {
+		entry.LastModified = v
+	}
+	c.put(key, entry)
+}
+
+func (c *Cache) path(key string) string {
+	return filepath.Join(c.cacheDir, sanitizeFileName(key)+".json")
+}
+
+// validators returns the conditional request headers for a cached entry.
+func (e cacheEntry) validators() http.Header {
+	h := http.Header{}
+	if e.ETag != "" {
+		h.Set("If-None-Match", e.ETag)
+	}
+	if e.LastModified != "" {
+		h.Set("If-Modified-Since", e.LastModified)
+	}
+	return h
+}
+
+// newEntry builds a cache entry from a response, keeping its validators.
+func newEntry(header http.Header) cacheEntry {
+	return cacheEntry{StoredAt: time.Now(), ETag: header.Get("ETag"), LastModified: header.Get("Last-Modified")}
+}
+
+// hashKey shortens keys built from URLs, which may be too long for a file
+// name.
+func hashKey(in string) string {
+	sum := sha256.Sum256([]byte(in))
+	return hex.EncodeToString(sum[:12])
 }
 
 func sanitizeFileName(in string) string {
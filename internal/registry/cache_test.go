@@ -2,6 +2,10 @@ package registry
 
 import (
 	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 )
@@ -50,6 +54,100 @@ func TestCache_Miss(t *testing.T) {
 	}
 }
 
+func TestCache_CorruptEntryIsDropped(t *testing.T) {
+	dir := t.TempDir()
+	c := NewCache(dir)
+	path := filepath.Join(dir, "broken.json")
+	if err := os.WriteFile(path, []byte(`{"storedAt":"2024-`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	items, _ := c.List()
+	if len(items) != 1 || !items[0].Corrupt {
+		t.Fatalf("expected a corrupt entry, got %+v", items)
+	}
+	if _, ok := c.Load("broken", time.Hour); ok {
+		t.Fatal("expected miss for truncated entry")
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Error("truncated entry was not removed")
+	}
+}
+
+func TestCache_EvictsLeastRecentlyUsed(t *testing.T) {
+	dir := t.TempDir()
+	c := NewCache(dir)
+	blob := strings.Repeat("x", 1000)
+	old := time.Now().Add(-time.Hour)
+	for i, key := range []string{"a", "b", "c"} {
+		c.Store(key, blob)
+		at := old.Add(time.Duration(i) * time.Minute)
+		_ = os.Chtimes(filepath.Join(dir, key+".json"), at, at)
+	}
+	// Reading a marks it used, so b is now the least recently used.
+	if _, ok := c.Load("a", time.Hour); !ok {
+		t.Fatal("expected hit for a")
+	}
+
+	c.SetMaxSize(2500)
+	c.Store("d", blob)
+
+	items, _ := c.List()
+	var keys []string
+	for _, it := range items {
+		keys = append(keys, it.Key)
+	}
+	if len(keys) != 2 || keys[0] != "d" || keys[1] != "a" {
+		t.Errorf("expected [d a] to survive eviction, got %v", keys)
+	}
+}
+
+func TestCache_PruneAndClear(t *testing.T) {
+	dir := t.TempDir()
+	c := NewCache(dir)
+	c.Store("fresh", "v")
+	c.put("old", cacheEntry{StoredAt: time.Now().Add(-48 * time.Hour), Data: json.RawMessage(`"v"`)})
+	_ = os.WriteFile(filepath.Join(dir, "bad.json"), []byte("{"), 0o644)
+
+	n, freed, err := c.Prune(24 * time.Hour)
+	if err != nil || n != 2 || freed == 0 {
+		t.Fatalf("Prune = %d, %d, %v; want 2 removed", n, freed, err)
+	}
+	if _, ok := c.Load("fresh", time.Hour); !ok {
+		t.Error("fresh entry was pruned")
+	}
+
+	if n, err := c.Clear(); err != nil || n != 1 {
+		t.Errorf("Clear = %d, %v", n, err)
+	}
+	if items, _ := c.List(); len(items) != 0 {
+		t.Errorf("expected empty cache, got %+v", items)
+	}
+}
+
+func TestCache_ConcurrentWrites(t *testing.T) {
+	dir := t.TempDir()
+	writers := []*Cache{NewCache(dir), NewCache(dir)}
+	big := strings.Repeat("y", 64<<10)
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(c *Cache) {
+			defer wg.Done()
+			for j := 0; j < 20; j++ {
+				c.Store("shared", big)
+				if raw, ok := c.Load("shared", time.Hour); ok && len(raw) != len(big)+2 {
+					t.Errorf("read a partial entry of %d bytes", len(raw))
+				}
+			}
+		}(writers[i%2])
+	}
+	wg.Wait()
+	if tmps, _ := filepath.Glob(filepath.Join(dir, ".tmp-*")); len(tmps) != 0 {
+		t.Errorf("temp files left behind: %v", tmps)
+	}
+}
+
 func TestSanitizeFileName(t *testing.T) {
 	tests := []struct {
 		input string
@@ -1,27 +1,38 @@
 package registry
 
 import (
+	"bytes"
+	"context"
+	"crypto/tls"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"net/url"
 	"os"
 	"path/filepath"
+	"sync"
 	"time"
+
+	"github.com/ZiaoLiu-1/pskill/internal/archive"
 )
 
 // SkillResult is the unified result type used across the app.
 type SkillResult struct {
-	ID          string `json:"id"`
-	Name        string `json:"name"`
-	Author      string `json:"author"`
-	Description string `json:"description"`
-	GithubURL   string `json:"githubUrl"`
-	SkillURL    string `json:"skillUrl"`
-	Stars       int64  `json:"stars"`
-	UpdatedAt   int64  `json:"updatedAt"`
-	Score       float64 `json:"score,omitempty"` // AI search score
+	ID          string    `json:"id"`
+	Name        string    `json:"name"`
+	Author      string    `json:"author"`
+	Description string    `json:"description"`
+	GithubURL   string    `json:"githubUrl"`
+	SkillURL    string    `json:"skillUrl"`
+	Stars       int64     `json:"stars"`
+	UpdatedAt   int64     `json:"updatedAt"`
+	Score       float64   `json:"score,omitempty"`      // AI search score
+	Registry    string    `json:"registry,omitempty"`   // name of the registry that returned it
+	ArchiveURL  string    `json:"archiveUrl,omitempty"` // tar.gz of the skill dir, set by self-hosted registries
+	Version     string    `json:"version,omitempty"`    // latest published version, when the registry tracks versions
+	CachedAt    time.Time `json:"-"`                    // when a stale copy served offline was cached; zero when live
 }
 
 // --- API response structures ---
@@ -42,9 +53,9 @@ type searchResponse struct {
 }
 
 type aiSearchResult struct {
-	FileID   string      `json:"file_id"`
-	Filename string      `json:"filename"`
-	Score    float64     `json:"score"`
+	FileID   string       `json:"file_id"`
+	Filename string       `json:"filename"`
+	Score    float64      `json:"score"`
 	Skill    *SkillResult `json:"skill,omitempty"`
 }
 
@@ -64,10 +75,14 @@ type apiError struct {
 
 // Client talks to the skillsmp.com API.
 type Client struct {
-	baseURL string
-	apiKey  string
-	cache   *Cache
-	http    *http.Client
+	baseURL    string
+	apiKey     string
+	cache      *Cache
+	http       *http.Client
+	retry      retryPolicy
+	sleep      func(context.Context, time.Duration) error
+	offline    bool
+	refreshing sync.Map // cache keys being revalidated
 }
 
 func NewClient(baseURL, cacheDir, apiKey string) *Client {
@@ -78,11 +93,18 @@ func NewClient(baseURL, cacheDir, apiKey string) *Client {
 		http: &http.Client{
 			Timeout: 20 * time.Second,
 		},
+		retry: defaultRetry,
+		sleep: sleepCtx,
 	}
 }
 
 // Search performs a keyword search via /api/v1/skills/search.
-func (c *Client) Search(query string, limit int, page int, sortBy string) ([]SkillResult, int, error) {
+func (c *Client) Search(ctx context.Context, query string, limit int, page int, sortBy string) ([]SkillResult, int, error) {
+	p, err := c.searchPage(ctx, query, limit, page, sortBy)
+	return p.Items, p.Total, err
+}
+
+func (c *Client) searchPage(ctx context.Context, query string, limit int, page int, sortBy string) (Page, error) {
 	if sortBy == "" {
 		sortBy = "recent"
 	}
@@ -90,65 +112,54 @@ func (c *Client) Search(query string, limit int, page int, sortBy string) ([]Ski
 		page = 1
 	}
 	cacheKey := fmt.Sprintf("search_%s_%d_%d_%s", query, limit, page, sortBy)
-	if data, ok := c.cache.Load(cacheKey, 5*time.Minute); ok {
-		var resp searchResponse
-		if json.Unmarshal(data, &resp) == nil && resp.Success {
-			return resp.Data.Skills, resp.Data.Pagination.Total, nil
-		}
-	}
-
 	reqURL := fmt.Sprintf("%s/api/v1/skills/search?q=%s&page=%d&limit=%d&sortBy=%s",
 		c.baseURL, url.QueryEscape(query), page, limit, url.QueryEscape(sortBy))
 
-	body, err := c.doGet(reqURL)
-	if err != nil {
-		return nil, 0, err
-	}
-
 	var resp searchResponse
-	if err := json.Unmarshal(body, &resp); err != nil {
-		return nil, 0, fmt.Errorf("parse response: %w", err)
-	}
-	if !resp.Success {
-		msg := "unknown error"
-		if resp.Error != nil {
-			msg = resp.Error.Message
+	cachedAt, err := c.cachedGet(ctx, cacheKey, reqURL, 5*time.Minute, func(body []byte) error {
+		resp = searchResponse{}
+		if err := json.Unmarshal(body, &resp); err != nil {
+			return fmt.Errorf("parse response: %w", err)
+		}
+		if !resp.Success {
+			msg := "unknown error"
+			if resp.Error != nil {
+				msg = resp.Error.Message
+			}
+			return fmt.Errorf("API error: %s", msg)
 		}
-		return nil, 0, fmt.Errorf("API error: %s", msg)
+		return nil
+	})
+	if err != nil {
+		return Page{}, err
 	}
-
-	c.cache.Store(cacheKey, resp)
-	return resp.Data.Skills, resp.Data.Pagination.Total, nil
+	pg := resp.Data.Pagination
+	return Page{Items: markCached(resp.Data.Skills, cachedAt), Total: pg.Total, HasNext: pg.HasNext}, nil
 }
 
 // AISearch performs semantic search via /api/v1/skills/ai-search.
-func (c *Client) AISearch(query string) ([]SkillResult, error) {
+func (c *Client) AISearch(ctx context.Context, query string) ([]SkillResult, error) {
 	cacheKey := fmt.Sprintf("ai_search_%s", query)
-	if data, ok := c.cache.Load(cacheKey, 10*time.Minute); ok {
-		var results []SkillResult
-		if json.Unmarshal(data, &results) == nil {
-			return results, nil
-		}
-	}
-
 	reqURL := fmt.Sprintf("%s/api/v1/skills/ai-search?q=%s",
 		c.baseURL, url.QueryEscape(query))
 
-	body, err := c.doGet(reqURL)
-	if err != nil {
-		return nil, err
-	}
-
 	var resp aiSearchResponse
-	if err := json.Unmarshal(body, &resp); err != nil {
-		return nil, fmt.Errorf("parse response: %w", err)
-	}
-	if !resp.Success {
-		msg := "unknown error"
-		if resp.Error != nil {
-			msg = resp.Error.Message
+	cachedAt, err := c.cachedGet(ctx, cacheKey, reqURL, 10*time.Minute, func(body []byte) error {
+		resp = aiSearchResponse{}
+		if err := json.Unmarshal(body, &resp); err != nil {
+			return fmt.Errorf("parse response: %w", err)
 		}
-		return nil, fmt.Errorf("API error: %s", msg)
+		if !resp.Success {
+			msg := "unknown error"
+			if resp.Error != nil {
+				msg = resp.Error.Message
+			}
+			return fmt.Errorf("API error: %s", msg)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
 
 	// Extract skills from AI search results
@@ -161,50 +172,123 @@ func (c *Client) AISearch(query string) ([]SkillResult, error) {
 		sk.Score = item.Score
 		results = append(results, sk)
 	}
-
-	c.cache.Store(cacheKey, results)
-	return results, nil
+	return markCached(results, cachedAt), nil
 }
 
 // Trending returns skills sorted by stars (most popular).
-func (c *Client) Trending(limit int, page int) ([]SkillResult, int, error) {
-	return c.Search("*", limit, page, "stars")
+func (c *Client) Trending(ctx context.Context, limit int, page int) ([]SkillResult, int, error) {
+	return c.Search(ctx, "*", limit, page, "stars")
 }
 
 // DownloadSkill downloads a skill's SKILL.md from its GitHub URL.
-func (c *Client) DownloadSkill(name, githubURL, destination string) error {
+func (c *Client) DownloadSkill(ctx context.Context, name, githubURL, destination string) error {
 	_ = os.MkdirAll(destination, 0o755)
 
 	// Try to fetch raw SKILL.md from GitHub
 	if githubURL != "" {
 		rawURL := githubToRaw(githubURL)
 		if rawURL != "" {
-			req, _ := http.NewRequest(http.MethodGet, rawURL, nil)
-			resp, err := c.http.Do(req)
-			if err == nil {
-				defer resp.Body.Close()
-				if resp.StatusCode == 200 {
-					raw, err := io.ReadAll(resp.Body)
-					if err == nil && len(raw) > 0 {
-						return os.WriteFile(filepath.Join(destination, "SKILL.md"), raw, 0o644)
-					}
-				}
+			raw, err := c.download(ctx, rawURL, false)
+			if err == nil && len(raw) > 0 {
+				return os.WriteFile(filepath.Join(destination, "SKILL.md"), raw, 0o644)
+			}
+			if ctx.Err() != nil {
+				return ctx.Err()
 			}
 		}
 	}
+	if c.offline {
+		return ErrOffline
+	}
 
 	// Fallback: synthesize minimal SKILL.md
 	content := fmt.Sprintf("---\nname: %s\ndescription: Installed via pskill\n---\n\n# %s\n\nInstalled from skillsmp.com registry.\n", name, name)
 	return os.WriteFile(filepath.Join(destination, "SKILL.md"), []byte(content), 0o644)
 }
 
-// doGet performs an authenticated GET request.
-func (c *Client) doGet(reqURL string) ([]byte, error) {
-	req, err := http.NewRequest(http.MethodGet, reqURL, nil)
+// DownloadArchive fetches a skill tarball and unpacks it into destination.
+func (c *Client) DownloadArchive(ctx context.Context, archiveURL, destination string) error {
+	body, err := c.download(ctx, archiveURL, true)
 	if err != nil {
-		return nil, err
+		return err
+	}
+	return archive.ExtractTarGz(bytes.NewReader(body), destination)
+}
+
+// doGet performs an authenticated GET request with retries.
+func (c *Client) doGet(ctx context.Context, reqURL string) ([]byte, error) {
+	res, err := c.do(ctx, reqURL, true, nil)
+	return res.body, err
+}
+
+// response is a successful reply. notModified marks a 304 answer to a
+// conditional request, which has no body.
+type response struct {
+	body        []byte
+	header      http.Header
+	notModified bool
+}
+
+// do runs a GET with bounded retries, adding the conditional headers in
+// cond. Network errors and 5xx responses are retried with jittered
+// exponential backoff; 429 waits for Retry-After when it is short enough
+// and otherwise returns a *RateLimitError.
+func (c *Client) do(ctx context.Context, reqURL string, auth bool, cond http.Header) (response, error) {
+	if c.offline {
+		return response{}, ErrOffline
+	}
+	var lastErr error
+	for attempt := 0; ; attempt++ {
+		body, status, header, err := c.once(ctx, reqURL, auth, cond)
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return response{}, ctxErr
+		}
+		wait := c.retry.backoff(attempt)
+		switch {
+		case err != nil:
+			var certErr *tls.CertificateVerificationError
+			if errors.As(err, &certErr) {
+				// Retrying will not help; the CA is missing from the trust store.
+				return response{}, fmt.Errorf("%w (behind a TLS-inspecting proxy, set caCert for this registry)", err)
+			}
+			lastErr = err
+		case status == http.StatusTooManyRequests:
+			if d, ok := parseRetryAfter(header.Get("Retry-After"), time.Now()); ok {
+				wait = d
+			}
+			lastErr = &RateLimitError{RetryAfter: wait}
+			if wait > c.retry.maxWait || attempt+1 >= c.retry.attempts {
+				return response{}, lastErr
+			}
+		case status >= 500:
+			lastErr = httpError(status, body)
+		case status == http.StatusNotModified:
+			return response{header: header, notModified: true}, nil
+		case status >= 300:
+			return response{}, httpError(status, body)
+		default:
+			return response{body: body, header: header}, nil
+		}
+		if attempt+1 >= c.retry.attempts {
+			return response{}, lastErr
+		}
+		logHTTP("retry", reqURL, status, fmt.Sprintf("attempt=%d wait=%s", attempt+1, wait))
+		if err := c.sleep(ctx, wait); err != nil {
+			return response{}, err
+		}
+	}
+}
+
+// once performs a single request and returns the body, status and headers.
+func (c *Client) once(ctx context.Context, reqURL string, auth bool, cond http.Header) ([]byte, int, http.Header, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, reqURL, nil)
+	if err != nil {
+		return nil, 0, nil, err
+	}
+	for k, v := range cond {
+		req.Header[k] = v
 	}
-	if c.apiKey != "" {
+	if auth && c.apiKey != "" {
 		req.Header.Set("Authorization", "Bearer "+c.apiKey)
 	}
 	logHTTP("request", reqURL, 0, "")
@@ -212,30 +296,27 @@ func (c *Client) doGet(reqURL string) ([]byte, error) {
 	resp, err := c.http.Do(req)
 	if err != nil {
 		logHTTP("error", reqURL, 0, err.Error())
-		return nil, fmt.Errorf("request failed: %w", err)
+		return nil, 0, nil, fmt.Errorf("request failed: %w", err)
 	}
 	defer resp.Body.Close()
 
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
 		logHTTP("error", reqURL, resp.StatusCode, err.Error())
-		return nil, fmt.Errorf("read response: %w", err)
-	}
-
-	if resp.StatusCode >= 400 {
-		var apiErr struct {
-			Error *apiError `json:"error"`
-		}
-		if json.Unmarshal(body, &apiErr) == nil && apiErr.Error != nil {
-			logHTTP("response", reqURL, resp.StatusCode, apiErr.Error.Message)
-			return nil, fmt.Errorf("HTTP %d: %s", resp.StatusCode, apiErr.Error.Message)
-		}
-		logHTTP("response", reqURL, resp.StatusCode, "")
-		return nil, fmt.Errorf("HTTP %d", resp.StatusCode)
+		return nil, resp.StatusCode, resp.Header, fmt.Errorf("read response: %w", err)
 	}
 	logHTTP("response", reqURL, resp.StatusCode, "")
+	return body, resp.StatusCode, resp.Header, nil
+}
 
-	return body, nil
+func httpError(status int, body []byte) error {
+	var apiErr struct {
+		Error *apiError `json:"error"`
+	}
+	if json.Unmarshal(body, &apiErr) == nil && apiErr.Error != nil {
+		return fmt.Errorf("HTTP %d: %s", status, apiErr.Error.Message)
+	}
+	return fmt.Errorf("HTTP %d", status)
 }
 
 func logHTTP(kind, reqURL string, status int, msg string) {
@@ -0,0 +1,176 @@
+package registry
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// flakyServer fails the first len(fail) requests with the given handlers and
+// then answers an empty search result.
+func flakyServer(t *testing.T, fail ...http.HandlerFunc) (*httptest.Server, *int32) {
+	t.Helper()
+	var hits int32
+	hs := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := int(atomic.AddInt32(&hits, 1))
+		if n <= len(fail) {
+			fail[n-1](w, r)
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]any{
+			"success": true,
+			"data": map[string]any{
+				"skills":     []SkillResult{{Name: "ok"}},
+				"pagination": map[string]int{"total": 1},
+			},
+		})
+	}))
+	t.Cleanup(hs.Close)
+	return hs, &hits
+}
+
+func status(code int, header ...string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		for i := 0; i+1 < len(header); i += 2 {
+			w.Header().Set(header[i], header[i+1])
+		}
+		w.WriteHeader(code)
+	}
+}
+
+// testClient returns a client with a fast retry policy whose sleeps are
+// recorded instead of taken.
+func testClient(t *testing.T, baseURL string) (*Client, *[]time.Duration) {
+	c := NewClient(baseURL, t.TempDir(), "")
+	c.retry = retryPolicy{attempts: 3, base: 10 * time.Millisecond, max: 40 * time.Millisecond, maxWait: 5 * time.Second}
+	var slept []time.Duration
+	c.sleep = func(ctx context.Context, d time.Duration) error {
+		slept = append(slept, d)
+		return ctx.Err()
+	}
+	return c, &slept
+}
+
+func TestClient_RetriesServerErrors(t *testing.T) {
+	hs, hits := flakyServer(t, status(http.StatusBadGateway), status(http.StatusServiceUnavailable))
+	c, slept := testClient(t, hs.URL)
+
+	items, _, err := c.Search(context.Background(), "x", 10, 1, "stars")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(items) != 1 || *hits != 3 {
+		t.Errorf("items=%v hits=%d, want 1 item after 3 requests", items, *hits)
+	}
+	if len(*slept) != 2 {
+		t.Fatalf("expected 2 backoffs, got %v", *slept)
+	}
+	for i, d := range *slept {
+		if d < 5*time.Millisecond<<uint(i) || d > 40*time.Millisecond {
+			t.Errorf("backoff %d = %s out of range", i, d)
+		}
+	}
+}
+
+func TestClient_GivesUpAfterAttempts(t *testing.T) {
+	fail := status(http.StatusInternalServerError)
+	hs, hits := flakyServer(t, fail, fail, fail, fail)
+	c, _ := testClient(t, hs.URL)
+
+	if _, _, err := c.Search(context.Background(), "x", 10, 1, "stars"); err == nil {
+		t.Fatal("expected error after exhausting retries")
+	}
+	if *hits != 3 {
+		t.Errorf("expected 3 attempts, got %d", *hits)
+	}
+}
+
+func TestClient_DoesNotRetryClientErrors(t *testing.T) {
+	hs, hits := flakyServer(t, status(http.StatusNotFound))
+	c, _ := testClient(t, hs.URL)
+
+	if _, _, err := c.Search(context.Background(), "x", 10, 1, "stars"); err == nil {
+		t.Fatal("expected 404 error")
+	}
+	if *hits != 1 {
+		t.Errorf("404 was retried: %d requests", *hits)
+	}
+}
+
+func TestClient_HonorsRetryAfter(t *testing.T) {
+	hs, _ := flakyServer(t, status(http.StatusTooManyRequests, "Retry-After", "2"))
+	c, slept := testClient(t, hs.URL)
+
+	if _, _, err := c.Search(context.Background(), "x", 10, 1, "stars"); err != nil {
+		t.Fatal(err)
+	}
+	if len(*slept) != 1 || (*slept)[0] != 2*time.Second {
+		t.Errorf("expected a single 2s wait, got %v", *slept)
+	}
+}
+
+func TestClient_RateLimitTooLong(t *testing.T) {
+	hs, hits := flakyServer(t, status(http.StatusTooManyRequests, "Retry-After", "120"))
+	c, slept := testClient(t, hs.URL)
+
+	_, _, err := c.Search(context.Background(), "x", 10, 1, "stars")
+	var rl *RateLimitError
+	if !errors.As(err, &rl) || rl.RetryAfter != 2*time.Minute {
+		t.Fatalf("expected RateLimitError of 2m, got %v", err)
+	}
+	if rl.Error() != "rate limited, retry in 120s" {
+		t.Errorf("unexpected message %q", rl.Error())
+	}
+	if *hits != 1 || len(*slept) != 0 {
+		t.Errorf("long Retry-After should not be waited out: hits=%d slept=%v", *hits, *slept)
+	}
+}
+
+func TestClient_Cancel(t *testing.T) {
+	release := make(chan struct{})
+	hs := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}))
+	defer hs.Close()
+	defer close(release)
+	c, _ := testClient(t, hs.URL)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	errc := make(chan error, 1)
+	go func() {
+		_, _, err := c.Search(ctx, "x", 10, 1, "stars")
+		errc <- err
+	}()
+	cancel()
+	select {
+	case err := <-errc:
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("expected context.Canceled, got %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("request was not cancelled")
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	for in, want := range map[string]time.Duration{
+		"7":                             7 * time.Second,
+		"Mon, 01 Jan 2024 00:00:30 GMT": 30 * time.Second,
+		"Sun, 31 Dec 2023 23:00:00 GMT": 0,
+	} {
+		if got, ok := parseRetryAfter(in, now); !ok || got != want {
+			t.Errorf("parseRetryAfter(%q) = %s, %v; want %s", in, got, ok, want)
+		}
+	}
+	if _, ok := parseRetryAfter("soon", now); ok {
+		t.Error("expected garbage Retry-After to be rejected")
+	}
+}
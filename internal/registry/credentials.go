@@ -0,0 +1,156 @@
+package registry
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/ZiaoLiu-1/pskill/internal/config"
+)
+
+// CredentialsPath is the file pskill login writes tokens to.
+func CredentialsPath(cfg config.Config) string {
+	return filepath.Join(cfg.HomeDir, "credentials")
+}
+
+// LoadCredentials reads the registry name → token map at path. A missing
+// file is empty; one that other users can read is refused, since it holds
+// secrets.
+func LoadCredentials(path string) (map[string]string, error) {
+	info, err := os.Stat(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return map[string]string{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if runtime.GOOS != "windows" && info.Mode().Perm()&0o077 != 0 {
+		return nil, fmt.Errorf("%s is accessible by other users (mode %04o); run chmod 600 %s", path, info.Mode().Perm(), path)
+	}
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	tokens := map[string]string{}
+	if err := yaml.Unmarshal(raw, &tokens); err != nil {
+		return nil, fmt.Errorf("parse %s: %w", path, err)
+	}
+	return tokens, nil
+}
+
+// SaveCredential stores token for registry name, or removes the entry when
+// token is empty. It reports whether the file changed.
+func SaveCredential(path, name, token string) (bool, error) {
+	tokens, err := LoadCredentials(path)
+	if err != nil {
+		return false, err
+	}
+	if tokens[name] == token {
+		return false, nil
+	}
+	if token == "" {
+		delete(tokens, name)
+	} else {
+		tokens[name] = token
+	}
+	raw, err := yaml.Marshal(tokens)
+	if err != nil {
+		return false, err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return false, err
+	}
+	// CreateTemp makes the file 0600 before anything is written to it.
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".credentials-*")
+	if err != nil {
+		return false, err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(raw); err != nil {
+		tmp.Close()
+		return false, err
+	}
+	if err := tmp.Close(); err != nil {
+		return false, err
+	}
+	return true, os.Rename(tmp.Name(), path)
+}
+
+// apiKey picks the token for a registry: an explicit credentials reference
+// first, then a token saved by pskill login, then a plaintext apiKey.
+func apiKey(rc config.RegistryConfig, name string, cfg config.Config) (string, error) {
+	if rc.Credentials != "" {
+		return ResolveCredentials(rc.Credentials)
+	}
+	tokens, err := LoadCredentials(CredentialsPath(cfg))
+	if err != nil {
+		return "", err
+	}
+	if tok := tokens[name]; tok != "" {
+		return tok, nil
+	}
+	return rc.APIKey, nil
+}
+
+// ResolveCredentials reads a secret reference: "env:VAR" reads an
+// environment variable, "file:path" the trimmed contents of a file and
+// "helper:command" the first line a shell command prints.
+func ResolveCredentials(ref string) (string, error) {
+	switch {
+	case strings.HasPrefix(ref, "env:"):
+		v := os.Getenv(strings.TrimPrefix(ref, "env:"))
+		if v == "" {
+			return "", fmt.Errorf("credentials %s: variable is not set", ref)
+		}
+		return v, nil
+	case strings.HasPrefix(ref, "file:"):
+		raw, err := os.ReadFile(expandHome(strings.TrimPrefix(ref, "file:")))
+		if err != nil {
+			return "", fmt.Errorf("credentials %s: %w", ref, err)
+		}
+		return strings.TrimSpace(string(raw)), nil
+	case strings.HasPrefix(ref, "helper:"):
+		return runHelper(strings.TrimPrefix(ref, "helper:"))
+	default:
+		return "", fmt.Errorf("credentials %q: want env:VAR, file:path or helper:command", ref)
+	}
+}
+
+// helperTokens caches helper output for the life of the process, since the
+// TUI rebuilds its registries for every search.
+var helperTokens sync.Map
+
+// runHelper runs a credential helper such as "pass show pskill/acme". Its
+// stderr is passed through so helpers can prompt.
+func runHelper(command string) (string, error) {
+	if tok, ok := helperTokens.Load(command); ok {
+		return tok.(string), nil
+	}
+	var cmd *exec.Cmd
+	if runtime.GOOS == "windows" {
+		cmd = exec.Command("cmd", "/C", command)
+	} else {
+		cmd = exec.Command("sh", "-c", command)
+	}
+	var stdout bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("credential helper %q: %w", command, err)
+	}
+	tok, _, _ := strings.Cut(stdout.String(), "\n")
+	tok = strings.TrimSpace(tok)
+	if tok == "" {
+		return "", fmt.Errorf("credential helper %q printed no token", command)
+	}
+	helperTokens.Store(command, tok)
+	return tok, nil
+}
@@ -0,0 +1,93 @@
+package registry
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/ZiaoLiu-1/pskill/internal/config"
+)
+
+func TestSaveCredential(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "credentials")
+	if changed, err := SaveCredential(path, "acme", "tok-1"); err != nil || !changed {
+		t.Fatalf("SaveCredential = %v, %v", changed, err)
+	}
+	if _, err := SaveCredential(path, "other", "tok-2"); err != nil {
+		t.Fatal(err)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if runtime.GOOS != "windows" && info.Mode().Perm() != 0o600 {
+		t.Errorf("mode = %04o, want 0600", info.Mode().Perm())
+	}
+	tokens, err := LoadCredentials(path)
+	if err != nil || tokens["acme"] != "tok-1" || tokens["other"] != "tok-2" {
+		t.Fatalf("LoadCredentials = %v, %v", tokens, err)
+	}
+
+	if changed, _ := SaveCredential(path, "acme", ""); !changed {
+		t.Error("removing a saved token should report a change")
+	}
+	if changed, _ := SaveCredential(path, "acme", ""); changed {
+		t.Error("removing a missing token should not")
+	}
+	tokens, _ = LoadCredentials(path)
+	if _, ok := tokens["acme"]; ok || tokens["other"] != "tok-2" {
+		t.Errorf("after logout: %v", tokens)
+	}
+}
+
+func TestLoadCredentials_RefusesLoosePermissions(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("no unix permissions")
+	}
+	path := filepath.Join(t.TempDir(), "credentials")
+	if err := os.WriteFile(path, []byte("acme: tok\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadCredentials(path); err == nil || !strings.Contains(err.Error(), "chmod 600") {
+		t.Fatalf("err = %v, want a chmod hint", err)
+	}
+}
+
+func TestAPIKeyPrecedence(t *testing.T) {
+	home := t.TempDir()
+	cfg := config.Config{HomeDir: home}
+	rc := config.RegistryConfig{Name: "acme", APIKey: "plain"}
+
+	if key, _ := apiKey(rc, "acme", cfg); key != "plain" {
+		t.Errorf("without login: %q", key)
+	}
+	if _, err := SaveCredential(CredentialsPath(cfg), "acme", "saved"); err != nil {
+		t.Fatal(err)
+	}
+	if key, _ := apiKey(rc, "acme", cfg); key != "saved" {
+		t.Errorf("after login: %q, want the saved token over apiKey", key)
+	}
+	t.Setenv("ACME_TOKEN", "from-env")
+	rc.Credentials = "env:ACME_TOKEN"
+	if key, _ := apiKey(rc, "acme", cfg); key != "from-env" {
+		t.Errorf("with credentials ref: %q", key)
+	}
+}
+
+func TestResolveCredentials_Helper(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("helper test uses sh")
+	}
+	tok, err := ResolveCredentials("helper:printf 'tok-from-helper\\nignored\\n'")
+	if err != nil || tok != "tok-from-helper" {
+		t.Fatalf("helper = %q, %v", tok, err)
+	}
+	if _, err := ResolveCredentials("helper:exit 3"); err == nil {
+		t.Error("expected a failing helper to error")
+	}
+	if _, err := ResolveCredentials("helper:true"); err == nil {
+		t.Error("expected a helper printing nothing to error")
+	}
+}
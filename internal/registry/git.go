@@ -0,0 +1,65 @@
+package registry
+
+import (
+	"context"
+	"time"
+
+	"github.com/ZiaoLiu-1/pskill/internal/source"
+)
+
+// checkoutTTL is how long a git registry's checkout is served before the
+// next call fetches it again.
+const checkoutTTL = 10 * time.Minute
+
+// GitRepo serves the skills in a git repository. The checkout lives in the
+// shared source cache and is refreshed once it is older than checkoutTTL.
+type GitRepo struct {
+	name string
+	url  string
+	ref  string
+	git  *source.Git
+}
+
+func NewGitRepo(name, repoURL, ref, cacheDir string) *GitRepo {
+	return &GitRepo{name: name, url: repoURL, ref: ref, git: source.NewGit(cacheDir).SetMaxAge(checkoutTTL)}
+}
+
+func (g *GitRepo) Name() string { return g.name }
+
+func (g *GitRepo) Search(ctx context.Context, query string, limit, page int) ([]SkillResult, int, error) {
+	d, err := g.checkout(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+	return d.Search(ctx, query, limit, page)
+}
+
+func (g *GitRepo) Get(ctx context.Context, name string) (SkillResult, error) {
+	d, err := g.checkout(ctx)
+	if err != nil {
+		return SkillResult{}, err
+	}
+	r, err := d.Get(ctx, name)
+	r.GithubURL = g.url
+	return r, err
+}
+
+func (g *GitRepo) Trending(ctx context.Context, limit, page int) ([]SkillResult, int, error) {
+	return g.Search(ctx, "", limit, page)
+}
+
+func (g *GitRepo) Fetch(ctx context.Context, r SkillResult, dest string) error {
+	d, err := g.checkout(ctx)
+	if err != nil {
+		return err
+	}
+	return d.Fetch(ctx, r, dest)
+}
+
+func (g *GitRepo) checkout(ctx context.Context) (*Dir, error) {
+	dir, err := g.git.Fetch(ctx, g.url, g.ref)
+	if err != nil {
+		return nil, err
+	}
+	return NewDir(g.name, dir), nil
+}
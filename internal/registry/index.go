@@ -0,0 +1,109 @@
+package registry
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Index is a static JSON skill index served over HTTP, for example from a
+// GitHub Pages site. The document is either a list of SkillResult or an
+// object with a "skills" list. Each entry's archiveUrl (a tar.gz) or
+// skillUrl (a single SKILL.md) may be relative to the index URL.
+type Index struct {
+	name   string
+	url    string
+	client *Client
+}
+
+func NewIndex(name, indexURL, cacheDir, apiKey string) *Index {
+	return &Index{name: name, url: indexURL, client: NewClient(indexURL, cacheDir, apiKey)}
+}
+
+func (x *Index) Name() string { return x.name }
+
+func (x *Index) Search(ctx context.Context, query string, limit, page int) ([]SkillResult, int, error) {
+	all, err := x.all(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+	items, total := filterResults(all, query, limit, page)
+	return items, total, nil
+}
+
+func (x *Index) Get(ctx context.Context, name string) (SkillResult, error) {
+	all, err := x.all(ctx)
+	if err != nil {
+		return SkillResult{}, err
+	}
+	return findByName(all, name)
+}
+
+func (x *Index) Trending(ctx context.Context, limit, page int) ([]SkillResult, int, error) {
+	return x.Search(ctx, "", limit, page)
+}
+
+func (x *Index) Fetch(ctx context.Context, r SkillResult, dest string) error {
+	if r.ArchiveURL != "" {
+		return x.client.DownloadArchive(ctx, x.resolve(r.ArchiveURL), dest)
+	}
+	raw := r.SkillURL
+	if raw == "" {
+		raw = githubToRaw(r.GithubURL)
+	}
+	if raw == "" {
+		return fmt.Errorf("%s: index entry has no skillUrl", r.Name)
+	}
+	body, err := x.client.download(ctx, x.resolve(raw), true)
+	if err != nil {
+		return fmt.Errorf("fetch %s: %w", r.Name, err)
+	}
+	if err := os.MkdirAll(dest, 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(dest, "SKILL.md"), body, 0o644)
+}
+
+func (x *Index) all(ctx context.Context) ([]SkillResult, error) {
+	var items []SkillResult
+	cachedAt, err := x.client.cachedGet(ctx, "index_"+x.url, x.url, 5*time.Minute, func(body []byte) error {
+		items = nil
+		if err := json.Unmarshal(body, &items); err != nil {
+			var doc struct {
+				Skills []SkillResult `json:"skills"`
+			}
+			if err := json.Unmarshal(body, &doc); err != nil {
+				return fmt.Errorf("parse index %s: %w", x.url, err)
+			}
+			items = doc.Skills
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	for i := range items {
+		items[i].Registry = x.name
+	}
+	return markCached(items, cachedAt), nil
+}
+
+func (x *Index) resolve(ref string) string {
+	if strings.Contains(ref, "://") {
+		return ref
+	}
+	base, err := url.Parse(x.url)
+	if err != nil {
+		return ref
+	}
+	rel, err := url.Parse(ref)
+	if err != nil {
+		return ref
+	}
+	return base.ResolveReference(rel).String()
+}
@@ -0,0 +1,186 @@
+package registry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// ErrDone is returned by Iterator.NextPage after the last page.
+var ErrDone = errors.New("no more results")
+
+// MaxPageSize is the largest page registries are asked for; iterators
+// split bigger requests into several pages.
+const MaxPageSize = 100
+
+// Page is one page of results and whether another follows it.
+type Page struct {
+	Items   []SkillResult
+	Total   int
+	HasNext bool
+}
+
+// PageFunc fetches page n (1-based) of limit items.
+type PageFunc func(ctx context.Context, limit, n int) (Page, error)
+
+// Iterator streams results page by page. Pages are fetched only when asked
+// for, and once one is returned the next is prefetched in the background.
+// Items seen on an earlier page are dropped, since listings can shift while
+// they are paged through. It is safe for concurrent use; calls are
+// serialised.
+type Iterator struct {
+	fetch PageFunc
+	limit int
+
+	mu      sync.Mutex
+	next    int // page number NextPage returns
+	done    bool
+	total   int
+	seen    map[string]bool
+	pending chan pageResult // prefetch of page next, if started
+}
+
+type pageResult struct {
+	page Page
+	err  error
+}
+
+// NewIterator pages through fetch with pages of limit items.
+func NewIterator(limit int, fetch PageFunc) *Iterator {
+	if limit < 1 || limit > MaxPageSize {
+		limit = MaxPageSize
+	}
+	return &Iterator{fetch: fetch, limit: limit, next: 1, seen: map[string]bool{}}
+}
+
+// Pager is implemented by registries whose listings report whether another
+// page follows, which is more reliable than counting against the total.
+type Pager interface {
+	SearchPage(ctx context.Context, query string, limit, n int) (Page, error)
+	TrendingPage(ctx context.Context, limit, n int) (Page, error)
+}
+
+// SearchPages iterates a search on any registry, following its hasNext
+// flag where it has one and its total otherwise.
+func SearchPages(r Registry, query string, limit int) *Iterator {
+	return NewIterator(limit, func(ctx context.Context, limit, n int) (Page, error) {
+		return searchPage(ctx, r, query, limit, n)
+	})
+}
+
+// TrendingPages iterates trending skills on any registry.
+func TrendingPages(r Registry, limit int) *Iterator {
+	return NewIterator(limit, func(ctx context.Context, limit, n int) (Page, error) {
+		return trendingPage(ctx, r, limit, n)
+	})
+}
+
+func searchPage(ctx context.Context, r Registry, query string, limit, n int) (Page, error) {
+	if p, ok := r.(Pager); ok {
+		return p.SearchPage(ctx, query, limit, n)
+	}
+	items, total, err := r.Search(ctx, query, limit, n)
+	return Page{Items: items, Total: total, HasNext: n*limit < total && len(items) > 0}, err
+}
+
+func trendingPage(ctx context.Context, r Registry, limit, n int) (Page, error) {
+	if p, ok := r.(Pager); ok {
+		return p.TrendingPage(ctx, limit, n)
+	}
+	items, total, err := r.Trending(ctx, limit, n)
+	return Page{Items: items, Total: total, HasNext: n*limit < total && len(items) > 0}, err
+}
+
+// NextPage returns the unseen items of the next page, or ErrDone. After an
+// error the same page is tried again on the next call.
+func (it *Iterator) NextPage(ctx context.Context) ([]SkillResult, error) {
+	it.mu.Lock()
+	defer it.mu.Unlock()
+	if it.done {
+		return nil, ErrDone
+	}
+	var res pageResult
+	if it.pending != nil {
+		select {
+		case res = <-it.pending:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+		it.pending = nil
+		// A prefetch started under a context that has since been cancelled
+		// says nothing about the page; fetch it again.
+		stopped := errors.Is(res.err, context.Canceled) || errors.Is(res.err, context.DeadlineExceeded)
+		if stopped && ctx.Err() == nil {
+			res.page, res.err = it.fetch(ctx, it.limit, it.next)
+		}
+	} else {
+		res.page, res.err = it.fetch(ctx, it.limit, it.next)
+	}
+	if res.err != nil {
+		return nil, fmt.Errorf("page %d: %w", it.next, res.err)
+	}
+
+	it.next++
+	it.total = res.page.Total
+	if !res.page.HasNext || len(res.page.Items) == 0 {
+		it.done = true
+	} else {
+		it.prefetch(ctx)
+	}
+	items := make([]SkillResult, 0, len(res.page.Items))
+	for _, r := range res.page.Items {
+		key := r.Registry + "/" + r.Name
+		if it.seen[key] {
+			continue
+		}
+		it.seen[key] = true
+		items = append(items, r)
+	}
+	return items, nil
+}
+
+// prefetch starts fetching page it.next; callers hold it.mu.
+func (it *Iterator) prefetch(ctx context.Context) {
+	ch := make(chan pageResult, 1)
+	it.pending = ch
+	n := it.next
+	go func() {
+		p, err := it.fetch(ctx, it.limit, n)
+		ch <- pageResult{page: p, err: err}
+	}()
+}
+
+// HasNext reports whether NextPage may return more items.
+func (it *Iterator) HasNext() bool {
+	it.mu.Lock()
+	defer it.mu.Unlock()
+	return !it.done
+}
+
+// Total is the result count reported by the last page fetched.
+func (it *Iterator) Total() int {
+	it.mu.Lock()
+	defer it.mu.Unlock()
+	return it.total
+}
+
+// Collect reads pages until max items are gathered or the results run
+// out. max <= 0 reads every page.
+func (it *Iterator) Collect(ctx context.Context, max int) ([]SkillResult, error) {
+	var out []SkillResult
+	for max <= 0 || len(out) < max {
+		items, err := it.NextPage(ctx)
+		if errors.Is(err, ErrDone) {
+			break
+		}
+		if err != nil {
+			return out, err
+		}
+		out = append(out, items...)
+	}
+	if max > 0 && len(out) > max {
+		out = out[:max]
+	}
+	return out, nil
+}
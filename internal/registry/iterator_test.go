@@ -0,0 +1,182 @@
+package registry
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+)
+
+// pagedFetch serves total numbered skills in pages and records which pages
+// were requested.
+type pagedFetch struct {
+	mu    sync.Mutex
+	total int
+	calls []int
+	fail  map[int]error
+}
+
+func (p *pagedFetch) fetch(ctx context.Context, limit, n int) (Page, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.calls = append(p.calls, n)
+	if err := p.fail[n]; err != nil {
+		delete(p.fail, n)
+		return Page{}, err
+	}
+	var items []SkillResult
+	for i := (n - 1) * limit; i < n*limit && i < p.total; i++ {
+		items = append(items, SkillResult{Name: fmt.Sprintf("skill-%03d", i)})
+	}
+	return Page{Items: items, Total: p.total, HasNext: n*limit < p.total}, nil
+}
+
+func (p *pagedFetch) requested() []int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return append([]int(nil), p.calls...)
+}
+
+func TestIterator_PagesLazilyWithPrefetch(t *testing.T) {
+	src := &pagedFetch{total: 25}
+	it := NewIterator(10, src.fetch)
+	if got := src.requested(); len(got) != 0 {
+		t.Fatalf("fetched before NextPage: %v", got)
+	}
+
+	var names []string
+	for {
+		items, err := it.NextPage(context.Background())
+		if errors.Is(err, ErrDone) {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, r := range items {
+			names = append(names, r.Name)
+		}
+	}
+	if len(names) != 25 || names[24] != "skill-024" {
+		t.Fatalf("got %d items, last %q", len(names), names[len(names)-1])
+	}
+	if it.HasNext() || it.Total() != 25 {
+		t.Errorf("HasNext = %v, Total = %d after the last page", it.HasNext(), it.Total())
+	}
+	if got := src.requested(); fmt.Sprint(got) != "[1 2 3]" {
+		t.Errorf("pages requested = %v, want each once", got)
+	}
+}
+
+func TestIterator_RetriesFailedPageAndDedupes(t *testing.T) {
+	src := &pagedFetch{total: 30, fail: map[int]error{2: errors.New("boom")}}
+	it := NewIterator(10, func(ctx context.Context, limit, n int) (Page, error) {
+		p, err := src.fetch(ctx, limit, n)
+		if n == 3 && err == nil {
+			// A listing that shifted: page 3 repeats the last item of page 2.
+			p.Items = append([]SkillResult{{Name: "skill-019"}}, p.Items...)
+		}
+		return p, err
+	})
+	ctx := context.Background()
+	if _, err := it.NextPage(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := it.NextPage(ctx); err == nil {
+		t.Fatal("expected page 2 to fail")
+	}
+	if items, err := it.NextPage(ctx); err != nil || len(items) != 10 || items[0].Name != "skill-010" {
+		t.Fatalf("retry of page 2 = %v, %v", items, err)
+	}
+	items, err := it.NextPage(ctx)
+	if err != nil || len(items) != 10 || items[0].Name != "skill-020" {
+		t.Fatalf("page 3 = %d items starting %v, %v", len(items), items, err)
+	}
+}
+
+func TestIterator_RefetchesPrefetchCancelledByOldContext(t *testing.T) {
+	src := &pagedFetch{total: 20}
+	var mu sync.Mutex
+	hang := true
+	it := NewIterator(10, func(ctx context.Context, limit, n int) (Page, error) {
+		mu.Lock()
+		h := hang
+		mu.Unlock()
+		if n == 2 && h {
+			<-ctx.Done()
+			return Page{}, ctx.Err()
+		}
+		return src.fetch(ctx, limit, n)
+	})
+	first, cancel := context.WithCancel(context.Background())
+	if _, err := it.NextPage(first); err != nil {
+		t.Fatal(err)
+	}
+	cancel() // e.g. the TUI tab lost focus while page 2 was prefetching
+	mu.Lock()
+	hang = false
+	mu.Unlock()
+
+	items, err := it.NextPage(context.Background())
+	if err != nil || len(items) != 10 {
+		t.Fatalf("page 2 after cancelled prefetch = %d items, %v", len(items), err)
+	}
+}
+
+func TestIterator_Collect(t *testing.T) {
+	src := &pagedFetch{total: 250}
+	items, err := NewIterator(0, src.fetch).Collect(context.Background(), 200)
+	if err != nil || len(items) != 200 {
+		t.Fatalf("Collect(200) = %d, %v", len(items), err)
+	}
+	all, err := NewIterator(0, src.fetch).Collect(context.Background(), 0)
+	if err != nil || len(all) != 250 {
+		t.Fatalf("Collect(all) = %d, %v", len(all), err)
+	}
+}
+
+func TestTrendingPages_FollowsHasNext(t *testing.T) {
+	var pages []int
+	var mu sync.Mutex
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+		mu.Lock()
+		pages = append(pages, page)
+		mu.Unlock()
+		var resp searchResponse
+		resp.Success = true
+		resp.Data.Skills = []SkillResult{{Name: fmt.Sprintf("p%d", page)}}
+		// The total overstates what the API will serve; hasNext is authoritative.
+		resp.Data.Pagination.Total = 100
+		resp.Data.Pagination.HasNext = page < 2
+		_ = json.NewEncoder(w).Encode(resp)
+	}))
+	defer ts.Close()
+
+	reg := NewMulti(NewSkillsMP("api", ts.URL, t.TempDir(), ""))
+	items, err := TrendingPages(reg, 1).Collect(context.Background(), 0)
+	if err != nil || len(items) != 2 {
+		t.Fatalf("Collect = %v, %v", items, err)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if fmt.Sprint(pages) != "[1 2]" {
+		t.Errorf("pages requested = %v, want [1 2]", pages)
+	}
+}
+
+func TestTrendingPages_Registry(t *testing.T) {
+	root := t.TempDir()
+	for i := 0; i < 7; i++ {
+		writeSkill(t, root, fmt.Sprintf("s%d", i), "d")
+	}
+	items, err := TrendingPages(NewDir("local", root), 3).Collect(context.Background(), 0)
+	if err != nil || len(items) != 7 {
+		t.Fatalf("Collect = %d, %v", len(items), err)
+	}
+}
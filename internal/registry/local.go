@@ -0,0 +1,72 @@
+package registry
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+
+	"github.com/ZiaoLiu-1/pskill/internal/source"
+)
+
+// Dir serves every SKILL.md found under a local directory.
+type Dir struct {
+	name string
+	root string
+}
+
+func NewDir(name, root string) *Dir {
+	return &Dir{name: name, root: root}
+}
+
+func (d *Dir) Name() string { return d.name }
+
+func (d *Dir) Search(ctx context.Context, query string, limit, page int) ([]SkillResult, int, error) {
+	all, err := d.all()
+	if err != nil {
+		return nil, 0, err
+	}
+	items, total := filterResults(all, query, limit, page)
+	return items, total, nil
+}
+
+func (d *Dir) Get(ctx context.Context, name string) (SkillResult, error) {
+	all, err := d.all()
+	if err != nil {
+		return SkillResult{}, err
+	}
+	return findByName(all, name)
+}
+
+func (d *Dir) Trending(ctx context.Context, limit, page int) ([]SkillResult, int, error) {
+	return d.Search(ctx, "", limit, page)
+}
+
+func (d *Dir) Fetch(ctx context.Context, r SkillResult, dest string) error {
+	found, err := d.Get(ctx, r.Name)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(dest, 0o755); err != nil {
+		return err
+	}
+	return copyTree(filepath.Join(d.root, filepath.FromSlash(found.ID)), dest)
+}
+
+// all lists the skills under root. ID holds the path relative to root.
+func (d *Dir) all() ([]SkillResult, error) {
+	found, err := source.FindSkills(d.root)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]SkillResult, 0, len(found))
+	for _, f := range found {
+		out = append(out, SkillResult{
+			ID:          f.Subdir,
+			Name:        f.Name,
+			Description: f.Description,
+			SkillURL:    f.Dir,
+			Registry:    d.name,
+		})
+	}
+	return out, nil
+}
@@ -0,0 +1,241 @@
+package registry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Multi combines several registries. Searches fan out in parallel and are
+// merged in priority order; name lookups walk the registries in priority
+// order and honour each registry's scope.
+type Multi struct {
+	members []member
+}
+
+type member struct {
+	reg      Registry
+	priority int
+	scopes   []string // name patterns such as "@acme/*"
+}
+
+// NewMulti combines regs with equal priority, in the given order.
+func NewMulti(regs ...Registry) *Multi {
+	m := &Multi{}
+	for _, r := range regs {
+		m.Add(r, 0, nil)
+	}
+	return m
+}
+
+// Add appends a registry. Lower priority values are consulted first;
+// registries with equal priority keep insertion order. Scoped names
+// ("@scope/name") resolve only from registries whose scopes match them.
+func (m *Multi) Add(r Registry, priority int, scopes []string) {
+	m.members = append(m.members, member{reg: r, priority: priority, scopes: scopes})
+	sort.SliceStable(m.members, func(i, j int) bool { return m.members[i].priority < m.members[j].priority })
+}
+
+func (m *Multi) Name() string { return "all" }
+
+// Registries returns the member registries in priority order.
+func (m *Multi) Registries() []Registry {
+	out := make([]Registry, 0, len(m.members))
+	for _, mb := range m.members {
+		out = append(out, mb.reg)
+	}
+	return out
+}
+
+// Search queries every registry in parallel for the same page. The total is
+// the sum of member totals less duplicates. An error is returned only when
+// every registry failed.
+func (m *Multi) Search(ctx context.Context, query string, limit, page int) ([]SkillResult, int, error) {
+	p, err := m.SearchPage(ctx, query, limit, page)
+	return p.Items, p.Total, err
+}
+
+// SearchPage is Search for iterators: another page follows while any
+// registry has one.
+func (m *Multi) SearchPage(ctx context.Context, query string, limit, n int) (Page, error) {
+	return m.gather(func(r Registry) (Page, error) {
+		return searchPage(ctx, r, query, limit, n)
+	}, limit, false)
+}
+
+// Get resolves a skill name. A scoped name is looked up by its bare name in
+// the registries that own the scope; other names are tried in every
+// registry in priority order.
+func (m *Multi) Get(ctx context.Context, name string) (SkillResult, error) {
+	var errs []error
+	for _, mb := range m.candidates(name) {
+		res, err := mb.reg.Get(ctx, BareName(name))
+		if err == nil {
+			return res, nil
+		}
+		if ctx.Err() != nil {
+			return SkillResult{}, ctx.Err()
+		}
+		if !errors.Is(err, ErrNotFound) {
+			errs = append(errs, fmt.Errorf("%s: %w", mb.reg.Name(), err))
+		}
+	}
+	if len(errs) > 0 {
+		return SkillResult{}, errors.Join(append([]error{ErrNotFound}, errs...)...)
+	}
+	return SkillResult{}, ErrNotFound
+}
+
+// GetFrom resolves name in the named registry only.
+func (m *Multi) GetFrom(ctx context.Context, registryName, name string) (SkillResult, error) {
+	for _, mb := range m.members {
+		if mb.reg.Name() == registryName {
+			return mb.reg.Get(ctx, BareName(name))
+		}
+	}
+	return SkillResult{}, fmt.Errorf("registry %q is not configured", registryName)
+}
+
+func (m *Multi) Trending(ctx context.Context, limit, page int) ([]SkillResult, int, error) {
+	p, err := m.TrendingPage(ctx, limit, page)
+	return p.Items, p.Total, err
+}
+
+func (m *Multi) TrendingPage(ctx context.Context, limit, n int) (Page, error) {
+	return m.gather(func(r Registry) (Page, error) {
+		return trendingPage(ctx, r, limit, n)
+	}, limit, true)
+}
+
+// Fetch routes to the registry that produced r. A result without one is
+// fetched from the first registry owning its scope; an unscoped name with
+// no registry cannot be routed and is an error.
+func (m *Multi) Fetch(ctx context.Context, r SkillResult, dest string) error {
+	if r.Registry != "" {
+		for _, mb := range m.members {
+			if mb.reg.Name() == r.Registry {
+				return mb.reg.Fetch(ctx, r, dest)
+			}
+		}
+		return fmt.Errorf("registry %q is not configured", r.Registry)
+	}
+	if strings.HasPrefix(r.Name, "@") {
+		if c := m.candidates(r.Name); len(c) > 0 {
+			r.Name = BareName(r.Name)
+			return c[0].reg.Fetch(ctx, r, dest)
+		}
+	}
+	return fmt.Errorf("no registry to fetch %s from", r.Name)
+}
+
+// AISearch uses semantic search where a member supports it and keyword
+// search elsewhere.
+func (m *Multi) AISearch(ctx context.Context, query string) ([]SkillResult, error) {
+	p, err := m.gather(func(r Registry) (Page, error) {
+		if s, ok := r.(SemanticSearcher); ok {
+			res, err := s.AISearch(ctx, query)
+			return Page{Items: res, Total: len(res)}, err
+		}
+		return searchPage(ctx, r, query, 15, 1)
+	}, 0, false)
+	return p.Items, err
+}
+
+// candidates returns the members allowed to resolve name, in priority order.
+func (m *Multi) candidates(name string) []member {
+	if !strings.HasPrefix(name, "@") {
+		return m.members
+	}
+	var out []member
+	for _, mb := range m.members {
+		for _, pattern := range mb.scopes {
+			if ok, _ := path.Match(pattern, name); ok {
+				out = append(out, mb)
+				break
+			}
+		}
+	}
+	return out
+}
+
+func (m *Multi) gather(call func(Registry) (Page, error), limit int, byStars bool) (Page, error) {
+	type answer struct {
+		page Page
+		err  error
+	}
+	answers := make([]answer, len(m.members))
+	var wg sync.WaitGroup
+	for i, mb := range m.members {
+		wg.Add(1)
+		go func(i int, r Registry) {
+			defer wg.Done()
+			p, err := call(r)
+			answers[i] = answer{p, err}
+		}(i, mb.reg)
+	}
+	wg.Wait()
+
+	out := Page{Items: []SkillResult{}}
+	var errs []error
+	for i, a := range answers {
+		if a.err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", m.members[i].reg.Name(), a.err))
+			continue
+		}
+		out.Total += a.page.Total
+		out.HasNext = out.HasNext || a.page.HasNext
+		for _, it := range a.page.Items {
+			if containsSame(out.Items, it) {
+				out.Total--
+				continue
+			}
+			out.Items = append(out.Items, it)
+		}
+	}
+	if len(errs) == len(m.members) && len(errs) > 0 {
+		return Page{}, errors.Join(errs...)
+	}
+	if byStars {
+		sortByStars(out.Items)
+	}
+	if limit > 0 && len(out.Items) > limit {
+		out.Items = out.Items[:limit]
+	}
+	return out, nil
+}
+
+// containsSame reports whether items already holds the same skill as it:
+// equal names that share a source URL, or share a description when neither
+// has one. Same-named but different skills are both kept so the caller can
+// see where each came from.
+func containsSame(items []SkillResult, it SkillResult) bool {
+	for _, o := range items {
+		if o.Name != it.Name {
+			continue
+		}
+		if o.GithubURL != "" || it.GithubURL != "" {
+			if o.GithubURL == it.GithubURL {
+				return true
+			}
+			continue
+		}
+		if o.Description == it.Description {
+			return true
+		}
+	}
+	return false
+}
+
+// BareName strips an "@scope/" prefix from a skill name.
+func BareName(name string) string {
+	if strings.HasPrefix(name, "@") {
+		if i := strings.Index(name, "/"); i >= 0 {
+			return name[i+1:]
+		}
+	}
+	return name
+}
@@ -0,0 +1,170 @@
+package registry
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// ErrOffline is returned when a request needs the network while offline
+// and nothing usable is cached.
+var ErrOffline = errors.New("offline: no cached copy")
+
+// staleWindow is how long past its TTL a cache entry is still served
+// straight away while a fresh copy is fetched in the background.
+const staleWindow = 24 * time.Hour
+
+// backgroundRefresh enables serving stale entries while they refresh in the
+// background. Only a long-running process lives to see the refresh land.
+var backgroundRefresh bool
+
+// SetBackgroundRefresh lets stale cache entries be served at once and
+// refreshed in the background for the rest of the process. The TUI sets
+// it; one-shot commands refresh a stale entry before using it.
+func SetBackgroundRefresh(v bool) { backgroundRefresh = v }
+
+// cachedGet fetches reqURL through the cache and hands the body to parse.
+// Fresh entries are used as is. With background refresh on, stale ones are
+// served at once and refreshed in the background; otherwise, and for
+// entries older than staleWindow, they are refetched and only used when the
+// request fails. Refetches are conditional, so an unchanged resource costs
+// a 304. Offline, any cached copy is served. The returned time is when a
+// served stale copy was stored, zero otherwise.
+func (c *Client) cachedGet(ctx context.Context, key, reqURL string, ttl time.Duration, parse func([]byte) error) (time.Time, error) {
+	entry, cached := c.cache.lookup(key)
+	if cached && parse(entry.Data) != nil {
+		cached = false
+	}
+	var cond http.Header
+	if cached {
+		age := time.Since(entry.StoredAt)
+		if age <= ttl {
+			return time.Time{}, nil
+		}
+		if backgroundRefresh && !c.offline && age <= ttl+staleWindow {
+			c.revalidate(key, reqURL, entry)
+			return entry.StoredAt, nil
+		}
+		cond = entry.validators()
+	}
+
+	res, err := c.do(ctx, reqURL, true, cond)
+	switch {
+	case err != nil:
+	case res.notModified && cached:
+		// parse already ran on the cached copy.
+		c.cache.touch(key, entry, res.header)
+		return time.Time{}, nil
+	default:
+		if err = parse(res.body); err == nil {
+			fresh := newEntry(res.header)
+			fresh.Data = res.body
+			c.cache.put(key, fresh)
+			return time.Time{}, nil
+		}
+	}
+	if !cached || ctx.Err() != nil || parse(entry.Data) != nil {
+		return time.Time{}, err
+	}
+	logHTTP("stale", reqURL, 0, err.Error())
+	return entry.StoredAt, nil
+}
+
+// revalidate refreshes a cache entry in the background, once per key.
+func (c *Client) revalidate(key, reqURL string, entry cacheEntry) {
+	if _, busy := c.refreshing.LoadOrStore(key, true); busy {
+		return
+	}
+	go func() {
+		defer c.refreshing.Delete(key)
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		res, err := c.do(ctx, reqURL, true, entry.validators())
+		switch {
+		case err != nil:
+		case res.notModified:
+			c.cache.touch(key, entry, res.header)
+		case json.Valid(res.body):
+			fresh := newEntry(res.header)
+			fresh.Data = res.body
+			c.cache.put(key, fresh)
+		}
+	}()
+}
+
+// download fetches a file such as a raw SKILL.md or an archive. A cached
+// copy is revalidated with a conditional request, so an unchanged file
+// costs a 304, and is served as is while offline.
+func (c *Client) download(ctx context.Context, reqURL string, auth bool) ([]byte, error) {
+	key := "download_" + hashKey(reqURL)
+	entry, cached := c.cache.lookup(key)
+	cached = cached && entry.Body != nil
+	var cond http.Header
+	if cached {
+		cond = entry.validators()
+	}
+	res, err := c.do(ctx, reqURL, auth, cond)
+	switch {
+	case err != nil:
+		if cached && errors.Is(err, ErrOffline) {
+			return entry.Body, nil
+		}
+		return nil, err
+	case res.notModified:
+		if !cached {
+			return nil, fmt.Errorf("HTTP 304 for uncached %s", reqURL)
+		}
+		c.cache.touch(key, entry, res.header)
+		return entry.Body, nil
+	}
+	fresh := newEntry(res.header)
+	fresh.Body = res.body
+	c.cache.put(key, fresh)
+	return res.body, nil
+}
+
+func markCached(items []SkillResult, at time.Time) []SkillResult {
+	if !at.IsZero() {
+		for i := range items {
+			items[i].CachedAt = at
+		}
+	}
+	return items
+}
+
+// StaleNote describes the oldest cached copy among items, such as
+// "cached 3 hours ago", or returns "" when all of them are live.
+func StaleNote(items []SkillResult) string {
+	var oldest time.Time
+	for _, it := range items {
+		if !it.CachedAt.IsZero() && (oldest.IsZero() || it.CachedAt.Before(oldest)) {
+			oldest = it.CachedAt
+		}
+	}
+	if oldest.IsZero() {
+		return ""
+	}
+	return "cached " + ago(time.Since(oldest))
+}
+
+func ago(d time.Duration) string {
+	plural := func(n int, unit string) string {
+		if n == 1 {
+			return fmt.Sprintf("1 %s ago", unit)
+		}
+		return fmt.Sprintf("%d %ss ago", n, unit)
+	}
+	switch {
+	case d < time.Minute:
+		return "just now"
+	case d < time.Hour:
+		return plural(int(d/time.Minute), "minute")
+	case d < 48*time.Hour:
+		return plural(int(d/time.Hour), "hour")
+	default:
+		return plural(int(d/(24*time.Hour)), "day")
+	}
+}
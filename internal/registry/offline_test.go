@@ -0,0 +1,194 @@
+package registry
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// seedCache writes a search response for query "x" stored at the given time.
+func seedCache(t *testing.T, c *Client, name string, storedAt time.Time) {
+	t.Helper()
+	body, _ := json.Marshal(map[string]any{
+		"success": true,
+		"data": map[string]any{
+			"skills":     []SkillResult{{Name: name}},
+			"pagination": map[string]int{"total": 1},
+		},
+	})
+	payload, _ := json.Marshal(cacheEntry{StoredAt: storedAt, Data: body})
+	path := filepath.Join(c.cache.cacheDir, sanitizeFileName("search_x_10_1_stars")+".json")
+	if err := os.WriteFile(path, payload, 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestClient_OfflineServesStaleCache(t *testing.T) {
+	c := NewClient("http://127.0.0.1:0", t.TempDir(), "")
+	c.offline = true
+
+	if _, _, err := c.Search(context.Background(), "x", 10, 1, "stars"); !errors.Is(err, ErrOffline) {
+		t.Fatalf("expected ErrOffline without cache, got %v", err)
+	}
+
+	storedAt := time.Now().Add(-72 * time.Hour)
+	seedCache(t, c, "old", storedAt)
+	items, _, err := c.Search(context.Background(), "x", 10, 1, "stars")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(items) != 1 || items[0].Name != "old" || !items[0].CachedAt.Equal(storedAt) {
+		t.Fatalf("expected stale cached item, got %+v", items)
+	}
+	if note := StaleNote(items); note != "cached 3 days ago" {
+		t.Errorf("StaleNote = %q", note)
+	}
+}
+
+func TestClient_StaleWhileRevalidate(t *testing.T) {
+	SetBackgroundRefresh(true)
+	t.Cleanup(func() { SetBackgroundRefresh(false) })
+	hs, hits := flakyServer(t)
+	c, _ := testClient(t, hs.URL)
+	storedAt := time.Now().Add(-time.Hour)
+	seedCache(t, c, "stale", storedAt)
+
+	items, _, err := c.Search(context.Background(), "x", 10, 1, "stars")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(items) != 1 || items[0].Name != "stale" || !items[0].CachedAt.Equal(storedAt) {
+		t.Fatalf("expected stale entry served and marked, got %+v", items)
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		data, storedAt, ok := c.cache.Peek("search_x_10_1_stars")
+		if ok && time.Since(storedAt) < time.Minute {
+			var resp searchResponse
+			_ = json.Unmarshal(data, &resp)
+			if len(resp.Data.Skills) != 1 || resp.Data.Skills[0].Name != "ok" {
+				t.Fatalf("unexpected refreshed entry %s", data)
+			}
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("cache was not refreshed in the background (hits=%d)", *hits)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestClient_RefreshesStaleBeforeServing(t *testing.T) {
+	hs, _ := flakyServer(t)
+	c, _ := testClient(t, hs.URL)
+	seedCache(t, c, "stale", time.Now().Add(-time.Hour))
+
+	items, _, err := c.Search(context.Background(), "x", 10, 1, "stars")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(items) != 1 || items[0].Name != "ok" || !items[0].CachedAt.IsZero() {
+		t.Fatalf("expected a refreshed live result, got %+v", items)
+	}
+}
+
+func TestClient_FallsBackWhenUnreachable(t *testing.T) {
+	fail := status(http.StatusBadGateway)
+	hs, _ := flakyServer(t, fail, fail, fail)
+	c, _ := testClient(t, hs.URL)
+	storedAt := time.Now().Add(-5 * 24 * time.Hour)
+	seedCache(t, c, "old", storedAt)
+
+	items, _, err := c.Search(context.Background(), "x", 10, 1, "stars")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(items) != 1 || !items[0].CachedAt.Equal(storedAt) {
+		t.Fatalf("expected cached fallback, got %+v", items)
+	}
+}
+
+func TestAgo(t *testing.T) {
+	for d, want := range map[time.Duration]string{
+		10 * time.Second: "just now",
+		time.Minute:      "1 minute ago",
+		5 * time.Hour:    "5 hours ago",
+		50 * time.Hour:   "2 days ago",
+	} {
+		if got := ago(d); got != want {
+			t.Errorf("ago(%s) = %q, want %q", d, got, want)
+		}
+	}
+}
+
+func TestClient_ConditionalSearch(t *testing.T) {
+	var notModified int
+	hs := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			notModified++
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		t.Errorf("unconditional request %s", r.URL)
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer hs.Close()
+	c, _ := testClient(t, hs.URL)
+	seedCache(t, c, "cached", time.Now().Add(-48*time.Hour))
+	entry, _ := c.cache.lookup("search_x_10_1_stars")
+	entry.ETag = `"v1"`
+	c.cache.put("search_x_10_1_stars", entry)
+
+	items, _, err := c.Search(context.Background(), "x", 10, 1, "stars")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if notModified != 1 || len(items) != 1 || items[0].Name != "cached" || !items[0].CachedAt.IsZero() {
+		t.Fatalf("expected revalidated cache hit, got %+v (304s=%d)", items, notModified)
+	}
+	if _, storedAt, _ := c.cache.Peek("search_x_10_1_stars"); time.Since(storedAt) > time.Minute {
+		t.Errorf("304 did not refresh StoredAt: %s", storedAt)
+	}
+}
+
+func TestClient_ConditionalDownload(t *testing.T) {
+	const lastMod = "Mon, 01 Jan 2024 00:00:00 GMT"
+	var full, notModified int
+	hs := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("If-Modified-Since") == lastMod {
+			notModified++
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		full++
+		w.Header().Set("Last-Modified", lastMod)
+		_, _ = w.Write([]byte("# Skill\n"))
+	}))
+	defer hs.Close()
+	c, _ := testClient(t, hs.URL)
+
+	for i := 0; i < 3; i++ {
+		body, err := c.download(context.Background(), hs.URL+"/SKILL.md", false)
+		if err != nil || string(body) != "# Skill\n" {
+			t.Fatalf("download %d = %q, %v", i, body, err)
+		}
+	}
+	if full != 1 || notModified != 2 {
+		t.Errorf("expected 1 full and 2 conditional fetches, got %d and %d", full, notModified)
+	}
+
+	c.offline = true
+	if body, err := c.download(context.Background(), hs.URL+"/SKILL.md", false); err != nil || string(body) != "# Skill\n" {
+		t.Errorf("offline download = %q, %v", body, err)
+	}
+	if _, err := c.download(context.Background(), hs.URL+"/other.md", false); !errors.Is(err, ErrOffline) {
+		t.Errorf("expected ErrOffline for uncached download, got %v", err)
+	}
+}
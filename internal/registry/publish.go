@@ -0,0 +1,214 @@
+package registry
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// ErrVersionExists is returned when a registry already holds the version
+// being published.
+var ErrVersionExists = errors.New("version already published")
+
+// Publisher is implemented by registries that accept uploads.
+type Publisher interface {
+	Publish(ctx context.Context, meta PublishMeta, archive []byte) (SkillResult, error)
+}
+
+// PublishMeta is the metadata sent with a skill archive.
+type PublishMeta struct {
+	Name        string   `json:"name"`
+	Version     string   `json:"version"`
+	Description string   `json:"description"`
+	Tags        []string `json:"tags,omitempty"`
+}
+
+// publishPath is the upload endpoint, relative to the registry base URL:
+//
+//	POST /api/v1/skills/{name}/versions
+//	multipart/form-data: "metadata" (PublishMeta as JSON), "archive" (tar.gz)
+//
+// It answers 201 with {"success": true, "data": SkillResult} and 409 when
+// the version exists.
+func publishPath(name string) string {
+	return "/api/v1/skills/" + url.PathEscape(name) + "/versions"
+}
+
+// Publish uploads a packaged skill. Uploads are not retried: a repeated
+// attempt after a lost response would only report a conflict.
+func (c *Client) Publish(ctx context.Context, meta PublishMeta, archive []byte) (SkillResult, error) {
+	if c.offline {
+		return SkillResult{}, ErrOffline
+	}
+	var body bytes.Buffer
+	mw := multipart.NewWriter(&body)
+	metaJSON, _ := json.Marshal(meta)
+	if err := mw.WriteField("metadata", string(metaJSON)); err != nil {
+		return SkillResult{}, err
+	}
+	fw, err := mw.CreateFormFile("archive", meta.Name+"-"+meta.Version+".tar.gz")
+	if err != nil {
+		return SkillResult{}, err
+	}
+	if _, err := fw.Write(archive); err != nil {
+		return SkillResult{}, err
+	}
+	if err := mw.Close(); err != nil {
+		return SkillResult{}, err
+	}
+
+	reqURL := strings.TrimRight(c.baseURL, "/") + publishPath(meta.Name)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, reqURL, &body)
+	if err != nil {
+		return SkillResult{}, err
+	}
+	req.Header.Set("Content-Type", mw.FormDataContentType())
+	req.Header.Set("Content-Length", strconv.Itoa(body.Len()))
+	if c.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+c.apiKey)
+	}
+	logHTTP("publish", reqURL, 0, "")
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return SkillResult{}, fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	raw, _ := io.ReadAll(resp.Body)
+	logHTTP("response", reqURL, resp.StatusCode, "")
+
+	switch {
+	case resp.StatusCode == http.StatusConflict:
+		return SkillResult{}, fmt.Errorf("%s %s: %w", meta.Name, meta.Version, ErrVersionExists)
+	case resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusMethodNotAllowed:
+		return SkillResult{}, fmt.Errorf("registry does not accept uploads (%w)", httpError(resp.StatusCode, raw))
+	case resp.StatusCode >= 300:
+		return SkillResult{}, httpError(resp.StatusCode, raw)
+	}
+	var out struct {
+		Success bool        `json:"success"`
+		Data    SkillResult `json:"data"`
+	}
+	if err := json.Unmarshal(raw, &out); err != nil {
+		return SkillResult{}, fmt.Errorf("parse response: %w", err)
+	}
+	return out.Data, nil
+}
+
+func (s *SkillsMP) Publish(ctx context.Context, meta PublishMeta, archive []byte) (SkillResult, error) {
+	r, err := s.client.Publish(ctx, meta, archive)
+	r.Registry = s.name
+	return r, err
+}
+
+// Publisher returns the named registry's upload interface. With an empty
+// name it picks the only registry that accepts uploads.
+func (m *Multi) Publisher(name string) (Publisher, string, error) {
+	var found []member
+	for _, mb := range m.members {
+		if _, ok := mb.reg.(Publisher); !ok {
+			continue
+		}
+		if name == "" || mb.reg.Name() == name {
+			found = append(found, mb)
+		}
+	}
+	switch {
+	case len(found) == 1:
+		return found[0].reg.(Publisher), found[0].reg.Name(), nil
+	case name != "":
+		return nil, "", fmt.Errorf("registry %q not configured or does not accept uploads", name)
+	case len(found) == 0:
+		return nil, "", errors.New("no configured registry accepts uploads")
+	default:
+		return nil, "", errors.New("several registries accept uploads; choose one with --registry")
+	}
+}
+
+// InstallSpec is the specifier to pass to `pskill add` for version of a
+// skill published to registryName: scoped as @scope/name when the registry
+// owns a scope, and pinned as name@version when a version is given.
+func (m *Multi) InstallSpec(registryName, name, version string) string {
+	spec := name
+	for _, mb := range m.members {
+		if mb.reg.Name() != registryName {
+			continue
+		}
+		for _, pattern := range mb.scopes {
+			prefix := strings.TrimSuffix(pattern, "*")
+			if strings.HasPrefix(prefix, "@") && strings.HasSuffix(prefix, "/") {
+				spec = prefix + name
+				break
+			}
+		}
+	}
+	if version != "" {
+		spec += "@" + version
+	}
+	return spec
+}
+
+// SplitVersion splits an install specifier such as @acme/pdf@1.2.0 into
+// the skill name and version. The version is "" when none is given.
+func SplitVersion(spec string) (name, version string) {
+	if i := strings.LastIndex(spec, "@"); i > 0 {
+		return spec[:i], spec[i+1:]
+	}
+	return spec, ""
+}
+
+// AtVersion pins r to a published version. Only registries that serve
+// archives can fetch a version other than the one they list.
+func AtVersion(r SkillResult, version string) (SkillResult, error) {
+	if version == "" || version == r.Version {
+		return r, nil
+	}
+	if r.ArchiveURL == "" {
+		return r, fmt.Errorf("%s cannot serve version %s of %s", r.Registry, version, r.Name)
+	}
+	u, err := url.Parse(r.ArchiveURL)
+	if err != nil {
+		return r, err
+	}
+	q := u.Query()
+	q.Set("version", version)
+	u.RawQuery = q.Encode()
+	r.ArchiveURL, r.Version = u.String(), version
+	return r, nil
+}
+
+// compareVersions orders dotted numeric versions such as 1.10.0 > 1.9.2.
+// A leading "v" and any pre-release or build suffix are ignored.
+func compareVersions(a, b string) int {
+	trim := func(v string) []string {
+		v = strings.TrimPrefix(v, "v")
+		if i := strings.IndexAny(v, "-+"); i >= 0 {
+			v = v[:i]
+		}
+		return strings.Split(v, ".")
+	}
+	pa, pb := trim(a), trim(b)
+	for i := 0; i < len(pa) || i < len(pb); i++ {
+		var x, y int
+		if i < len(pa) {
+			x, _ = strconv.Atoi(pa[i])
+		}
+		if i < len(pb) {
+			y, _ = strconv.Atoi(pb[i])
+		}
+		if x != y {
+			if x < y {
+				return -1
+			}
+			return 1
+		}
+	}
+	return 0
+}
@@ -0,0 +1,157 @@
+package registry
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ZiaoLiu-1/pskill/internal/archive"
+)
+
+func packSkill(t *testing.T, name, body string) []byte {
+	t.Helper()
+	root := t.TempDir()
+	writeSkill(t, root, name, "Published skill")
+	if err := os.WriteFile(filepath.Join(root, name, "notes.txt"), []byte(body), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if err := archive.WriteTarGz(&buf, filepath.Join(root, name)); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestServer_PublishVersions(t *testing.T) {
+	hs, srv := newTestServer(t)
+	srv.EnablePublish("s3cret")
+	ctx := context.Background()
+	reg := NewSkillsMP("team", hs.URL, t.TempDir(), "s3cret")
+	meta := PublishMeta{Name: "lint-rules", Version: "1.0.0", Description: "Published skill"}
+
+	res, err := reg.Publish(ctx, meta, packSkill(t, "lint-rules", "v1"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Name != "lint-rules" || res.Version != "1.0.0" || res.Registry != "team" {
+		t.Fatalf("unexpected result: %+v", res)
+	}
+	if _, err := reg.Publish(ctx, meta, packSkill(t, "lint-rules", "again")); !errors.Is(err, ErrVersionExists) {
+		t.Fatalf("expected ErrVersionExists, got %v", err)
+	}
+
+	// An older version is stored but does not replace the served copy.
+	meta.Version = "0.9.0"
+	if _, err := reg.Publish(ctx, meta, packSkill(t, "lint-rules", "old")); err != nil {
+		t.Fatal(err)
+	}
+	meta.Version = "1.10.0"
+	if _, err := reg.Publish(ctx, meta, packSkill(t, "lint-rules", "v1.10")); err != nil {
+		t.Fatal(err)
+	}
+	got, err := reg.Get(ctx, "lint-rules")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Version != "1.10.0" {
+		t.Errorf("expected served version 1.10.0, got %q", got.Version)
+	}
+	dest := t.TempDir()
+	if err := reg.Fetch(ctx, got, dest); err != nil {
+		t.Fatal(err)
+	}
+	if raw, _ := os.ReadFile(filepath.Join(dest, "notes.txt")); string(raw) != "v1.10" {
+		t.Errorf("served copy is %q, want v1.10", raw)
+	}
+
+	old, err := AtVersion(got, "0.9.0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	dest = t.TempDir()
+	if err := reg.Fetch(ctx, old, dest); err != nil {
+		t.Fatal(err)
+	}
+	if raw, _ := os.ReadFile(filepath.Join(dest, "notes.txt")); string(raw) != "old" {
+		t.Errorf("pinned copy is %q, want old", raw)
+	}
+	if _, err := NewClient(hs.URL, t.TempDir(), "").doGet(ctx, hs.URL+"/api/v1/skills/..%2F..%2Fx/download?version=1.0.0"); err == nil {
+		t.Error("expected an invalid name to be rejected")
+	}
+}
+
+func TestServer_PublishRejects(t *testing.T) {
+	hs, srv := newTestServer(t)
+	ctx := context.Background()
+	meta := PublishMeta{Name: "pdf-tools", Version: "1.0.0"}
+
+	anon := NewSkillsMP("team", hs.URL, t.TempDir(), "")
+	if _, err := anon.Publish(ctx, meta, packSkill(t, "pdf-tools", "x")); err == nil || !strings.Contains(err.Error(), "does not accept uploads") {
+		t.Errorf("expected uploads to be disabled, got %v", err)
+	}
+
+	srv.EnablePublish("s3cret")
+	if _, err := anon.Publish(ctx, meta, packSkill(t, "pdf-tools", "x")); err == nil || !strings.Contains(err.Error(), "401") {
+		t.Errorf("expected 401 without token, got %v", err)
+	}
+	authed := NewSkillsMP("team", hs.URL, t.TempDir(), "s3cret")
+	if _, err := authed.Publish(ctx, meta, packSkill(t, "pdf-tools", "x")); !errors.Is(err, ErrVersionExists) {
+		t.Errorf("expected conflict for a skill served from the directory, got %v", err)
+	}
+	bad := PublishMeta{Name: "fresh", Version: "one"}
+	if _, err := authed.Publish(ctx, bad, packSkill(t, "fresh", "x")); err == nil || !strings.Contains(err.Error(), "semver") {
+		t.Errorf("expected semver error, got %v", err)
+	}
+}
+
+func TestMulti_PublisherAndInstallSpec(t *testing.T) {
+	m := &Multi{}
+	m.Add(NewDir("local", t.TempDir()), 0, nil)
+	if _, _, err := m.Publisher(""); err == nil {
+		t.Error("expected error with no publishing registry")
+	}
+	m.Add(NewSkillsMP("acme", "http://example.invalid", t.TempDir(), ""), 0, []string{"@acme/*"})
+	m.Add(NewSkillsMP("public", "http://example.invalid", t.TempDir(), ""), 1, nil)
+	if _, _, err := m.Publisher(""); err == nil || !strings.Contains(err.Error(), "--registry") {
+		t.Errorf("expected ambiguity error, got %v", err)
+	}
+	if _, name, err := m.Publisher("acme"); err != nil || name != "acme" {
+		t.Errorf("Publisher(acme) = %q, %v", name, err)
+	}
+	if _, _, err := m.Publisher("local"); err == nil {
+		t.Error("expected dir registry to refuse uploads")
+	}
+	if got := m.InstallSpec("acme", "pdf", "1.2.0"); got != "@acme/pdf@1.2.0" {
+		t.Errorf("InstallSpec(acme) = %q", got)
+	}
+	if got := m.InstallSpec("public", "pdf", ""); got != "pdf" {
+		t.Errorf("InstallSpec(public) = %q", got)
+	}
+	for spec, want := range map[string]string{"@acme/pdf@1.2.0": "@acme/pdf 1.2.0", "pdf@2.0.0": "pdf 2.0.0", "@acme/pdf": "@acme/pdf ", "pdf": "pdf "} {
+		if name, version := SplitVersion(spec); name+" "+version != want {
+			t.Errorf("SplitVersion(%q) = %q, %q", spec, name, version)
+		}
+	}
+}
+
+func TestCompareVersions(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"1.0.0", "1.0.0", 0},
+		{"1.10.0", "1.9.2", 1},
+		{"v2.0.0", "1.99.99", 1},
+		{"1.0.0-rc.1", "1.0.0", 0},
+		{"0.9.0", "1.0.0", -1},
+	}
+	for _, tt := range tests {
+		if got := compareVersions(tt.a, tt.b); got != tt.want {
+			t.Errorf("compareVersions(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,112 @@
+package registry
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/ZiaoLiu-1/pskill/internal/store"
+)
+
+// ErrNotFound is returned by Get when a registry has no skill by that name.
+var ErrNotFound = errors.New("skill not found")
+
+// Registry is a source of installable skills. Callers should use the
+// composite returned by FromConfig rather than a concrete backend.
+type Registry interface {
+	Name() string
+	Search(ctx context.Context, query string, limit, page int) ([]SkillResult, int, error)
+	Get(ctx context.Context, name string) (SkillResult, error)
+	Trending(ctx context.Context, limit, page int) ([]SkillResult, int, error)
+	// Fetch writes the skill described by r into dest.
+	Fetch(ctx context.Context, r SkillResult, dest string) error
+}
+
+// SemanticSearcher is implemented by registries with a meaning-based search.
+type SemanticSearcher interface {
+	AISearch(ctx context.Context, query string) ([]SkillResult, error)
+}
+
+// filterResults applies a case-insensitive substring query to name,
+// description and author, orders by stars and returns one page. An empty
+// query or "*" matches everything.
+func filterResults(all []SkillResult, query string, limit, page int) ([]SkillResult, int) {
+	q := strings.ToLower(strings.TrimSpace(query))
+	matched := []SkillResult{}
+	for _, r := range all {
+		if q == "" || q == "*" ||
+			strings.Contains(strings.ToLower(r.Name), q) ||
+			strings.Contains(strings.ToLower(r.Description), q) ||
+			strings.Contains(strings.ToLower(r.Author), q) {
+			matched = append(matched, r)
+		}
+	}
+	sortByStars(matched)
+	return paginate(matched, limit, page), len(matched)
+}
+
+func sortByStars(items []SkillResult) {
+	sort.SliceStable(items, func(i, j int) bool {
+		if items[i].Stars != items[j].Stars {
+			return items[i].Stars > items[j].Stars
+		}
+		return items[i].Name < items[j].Name
+	})
+}
+
+func paginate(items []SkillResult, limit, page int) []SkillResult {
+	if page < 1 {
+		page = 1
+	}
+	if limit <= 0 {
+		return items
+	}
+	start := (page - 1) * limit
+	if start >= len(items) {
+		return []SkillResult{}
+	}
+	end := start + limit
+	if end > len(items) {
+		end = len(items)
+	}
+	return items[start:end]
+}
+
+func findByName(all []SkillResult, name string) (SkillResult, error) {
+	for _, r := range all {
+		if r.Name == name {
+			return r, nil
+		}
+	}
+	return SkillResult{}, ErrNotFound
+}
+
+// copyTree copies a skill directory, skipping VCS metadata and bookkeeping
+// files.
+func copyTree(src, dst string) error {
+	return filepath.WalkDir(src, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, _ := filepath.Rel(src, path)
+		target := filepath.Join(dst, rel)
+		if d.IsDir() {
+			if d.Name() == ".git" {
+				return filepath.SkipDir
+			}
+			return os.MkdirAll(target, 0o755)
+		}
+		if store.IsBookkeeping(rel) {
+			return nil
+		}
+		raw, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		return os.WriteFile(target, raw, 0o644)
+	})
+}
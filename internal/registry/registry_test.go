@@ -0,0 +1,182 @@
+package registry
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ZiaoLiu-1/pskill/internal/config"
+)
+
+func writeSkill(t *testing.T, root, rel, desc string) {
+	t.Helper()
+	dir := filepath.Join(root, rel)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	md := fmt.Sprintf("---\nname: %s\ndescription: %s\n---\n\nBody\n", filepath.Base(rel), desc)
+	if err := os.WriteFile(filepath.Join(dir, "SKILL.md"), []byte(md), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestDir_SearchGetFetch(t *testing.T) {
+	root := t.TempDir()
+	writeSkill(t, root, "skills/go-review", "Review Go code")
+	writeSkill(t, root, "skills/pdf-tools", "Work with PDF files")
+	os.WriteFile(filepath.Join(root, "skills/pdf-tools/extract.py"), []byte("print()\n"), 0o644)
+
+	d := NewDir("team", root)
+	items, total, err := d.Search(context.Background(), "pdf", 10, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if total != 1 || items[0].Name != "pdf-tools" || items[0].Registry != "team" {
+		t.Fatalf("unexpected search result: %d %+v", total, items)
+	}
+	if _, err := d.Get(context.Background(), "missing"); err != ErrNotFound {
+		t.Errorf("Get(missing) err = %v, want ErrNotFound", err)
+	}
+
+	dest := filepath.Join(t.TempDir(), "pdf-tools")
+	if err := d.Fetch(context.Background(), items[0], dest); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(dest, "extract.py")); err != nil {
+		t.Errorf("supporting file not fetched: %v", err)
+	}
+}
+
+func TestIndex_SearchAndFetch(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/index.json":
+			fmt.Fprint(w, `{"skills":[{"name":"a","stars":1,"skillUrl":"skills/a/SKILL.md"},{"name":"b","stars":5}]}`)
+		case "/skills/a/SKILL.md":
+			fmt.Fprint(w, "---\nname: a\n---\n")
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	x := NewIndex("static", srv.URL+"/index.json", t.TempDir(), "")
+	items, total, err := x.Trending(context.Background(), 10, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if total != 2 || items[0].Name != "b" {
+		t.Fatalf("expected b first by stars, got %+v", items)
+	}
+	got, err := x.Get(context.Background(), "a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	dest := t.TempDir()
+	if err := x.Fetch(context.Background(), got, dest); err != nil {
+		t.Fatal(err)
+	}
+	if raw, _ := os.ReadFile(filepath.Join(dest, "SKILL.md")); len(raw) == 0 {
+		t.Error("SKILL.md not written")
+	}
+}
+
+func TestMulti_DedupesAndRoutesFetch(t *testing.T) {
+	first, second := t.TempDir(), t.TempDir()
+	writeSkill(t, first, "shared", "same skill")
+	writeSkill(t, second, "shared", "same skill")
+	writeSkill(t, first, "clash", "from first")
+	writeSkill(t, second, "clash", "from second")
+	writeSkill(t, second, "only-second", "second only")
+
+	m := NewMulti(NewDir("one", first), NewDir("two", second))
+	items, total, err := m.Search(context.Background(), "", 10, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(items) != 4 || total != 4 {
+		t.Fatalf("expected 4 results after dedupe, got %d (total %d): %+v", len(items), total, items)
+	}
+	for _, it := range items {
+		if it.Name == "shared" && it.Registry != "one" {
+			t.Errorf("expected shared from first registry, got %s", it.Registry)
+		}
+	}
+
+	r, err := m.Get(context.Background(), "only-second")
+	if err != nil {
+		t.Fatal(err)
+	}
+	dest := t.TempDir()
+	if err := m.Fetch(context.Background(), r, dest); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(dest, "SKILL.md")); err != nil {
+		t.Errorf("fetch not routed to second registry: %v", err)
+	}
+}
+
+func TestFromConfig_DefaultsToSkillsMP(t *testing.T) {
+	m := FromConfig(config.Config{RegistryURL: "https://example.invalid", CacheDir: t.TempDir()})
+	regs := m.Registries()
+	if len(regs) != 1 || regs[0].Name() != "skillsmp" {
+		t.Fatalf("unexpected default registries: %v", regs)
+	}
+}
+
+func TestMulti_PriorityAndScope(t *testing.T) {
+	public, internal := t.TempDir(), t.TempDir()
+	writeSkill(t, public, "pdf-tools", "public pdf")
+	writeSkill(t, internal, "pdf-tools", "internal pdf")
+
+	m := NewMulti()
+	m.Add(NewDir("public", public), 10, nil)
+	m.Add(NewDir("acme", internal), 20, []string{"@acme/*"})
+
+	r, err := m.Get(context.Background(), "pdf-tools")
+	if err != nil || r.Registry != "public" {
+		t.Fatalf("unscoped name should resolve by priority: %+v, %v", r, err)
+	}
+	r, err = m.Get(context.Background(), "@acme/pdf-tools")
+	if err != nil || r.Registry != "acme" || r.Description != "internal pdf" {
+		t.Fatalf("scoped name should resolve from acme: %+v, %v", r, err)
+	}
+	if _, err := m.Get(context.Background(), "@other/pdf-tools"); err != ErrNotFound {
+		t.Errorf("unknown scope should not resolve, got %v", err)
+	}
+}
+
+func TestMulti_FetchNeverFallsBack(t *testing.T) {
+	public, internal := t.TempDir(), t.TempDir()
+	writeSkill(t, public, "pdf-tools", "public pdf")
+	writeSkill(t, internal, "pdf-tools", "internal pdf")
+
+	m := NewMulti()
+	m.Add(NewDir("public", public), 10, nil)
+	m.Add(NewDir("acme", internal), 20, []string{"@acme/*"})
+
+	ctx := context.Background()
+	if err := m.Fetch(ctx, SkillResult{Name: "pdf-tools"}, t.TempDir()); err == nil {
+		t.Error("unscoped result without a registry should not be fetched")
+	}
+	if err := m.Fetch(ctx, SkillResult{Name: "pdf-tools", Registry: "gone"}, t.TempDir()); err == nil {
+		t.Error("unknown registry should not fall back to the first one")
+	}
+	if err := m.Fetch(ctx, SkillResult{Name: "@other/pdf-tools"}, t.TempDir()); err == nil {
+		t.Error("unowned scope should not be fetched")
+	}
+
+	dest := t.TempDir()
+	if err := m.Fetch(ctx, SkillResult{Name: "@acme/pdf-tools"}, dest); err != nil {
+		t.Fatal(err)
+	}
+	raw, _ := os.ReadFile(filepath.Join(dest, "SKILL.md"))
+	if !strings.Contains(string(raw), "internal pdf") {
+		t.Errorf("scoped fetch not routed to acme: %q", raw)
+	}
+}
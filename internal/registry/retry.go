@@ -0,0 +1,77 @@
+package registry
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// RateLimitError is returned when a registry answers 429 and the requested
+// wait is too long to sit through, or retries ran out.
+type RateLimitError struct {
+	RetryAfter time.Duration
+}
+
+func (e *RateLimitError) Error() string {
+	return fmt.Sprintf("rate limited, retry in %ds", int(math.Ceil(e.RetryAfter.Seconds())))
+}
+
+type retryPolicy struct {
+	attempts int           // total tries, including the first
+	base     time.Duration // first backoff
+	max      time.Duration // backoff cap
+	maxWait  time.Duration // longest Retry-After we wait out in-process
+}
+
+var defaultRetry = retryPolicy{attempts: 4, base: 500 * time.Millisecond, max: 8 * time.Second, maxWait: 30 * time.Second}
+
+// backoff returns the jittered delay before retry number attempt+1: a
+// uniform pick from the upper half of base·2^attempt, capped at max.
+func (p retryPolicy) backoff(attempt int) time.Duration {
+	d := p.base << uint(attempt)
+	if d <= 0 || d > p.max {
+		d = p.max
+	}
+	half := d / 2
+	if half <= 0 {
+		return d
+	}
+	return half + time.Duration(rand.Int63n(int64(half)+1))
+}
+
+// parseRetryAfter understands both delta-seconds and HTTP-date forms.
+func parseRetryAfter(v string, now time.Time) (time.Duration, bool) {
+	v = strings.TrimSpace(v)
+	if v == "" {
+		return 0, false
+	}
+	if secs, err := strconv.Atoi(v); err == nil && secs >= 0 {
+		return time.Duration(secs) * time.Second, true
+	}
+	if t, err := http.ParseTime(v); err == nil {
+		if d := t.Sub(now); d > 0 {
+			return d, true
+		}
+		return 0, true
+	}
+	return 0, false
+}
+
+func sleepCtx(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
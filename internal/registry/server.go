@@ -0,0 +1,419 @@
+package registry
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/ZiaoLiu-1/pskill/internal/archive"
+	"github.com/ZiaoLiu-1/pskill/internal/search"
+	"github.com/ZiaoLiu-1/pskill/internal/skill"
+	"github.com/ZiaoLiu-1/pskill/internal/source"
+)
+
+// Server exposes a directory of skills over the same JSON contract Client
+// consumes, so a team can point registryUrl at it.
+type Server struct {
+	root    string
+	engine  *search.Engine
+	baseURL string
+
+	mu     sync.Mutex // bleve indexes are opened per call and must not overlap
+	skills map[string]SkillResult
+	dirs   map[string]string
+
+	publish   bool
+	token     string
+	publishMu sync.Mutex // serialises uploads; held across Reload
+}
+
+// publishedDir holds every uploaded archive as <name>/<version>.tar.gz.
+// FindSkills skips dot directories, so it is never served as a skill.
+const publishedDir = ".pskill-published"
+
+// maxUpload caps the size of a published archive.
+const maxUpload = 32 << 20
+
+// NewServer indexes every SKILL.md under root into a fresh index at
+// indexDir. baseURL is the externally visible address used in archive links.
+func NewServer(root, indexDir, baseURL string) (*Server, error) {
+	engine := search.NewEngine(indexDir)
+	if err := engine.Clear(); err != nil {
+		return nil, err
+	}
+	s := &Server{
+		root:    root,
+		engine:  engine,
+		baseURL: strings.TrimRight(baseURL, "/"),
+	}
+	return s, s.Reload()
+}
+
+// Reload rescans root and reindexes every skill.
+func (s *Server) Reload() error {
+	found, err := source.FindSkills(s.root)
+	if err != nil {
+		return err
+	}
+	skills := map[string]SkillResult{}
+	dirs := map[string]string{}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, f := range found {
+		if _, dup := dirs[f.Name]; dup {
+			fmt.Fprintf(os.Stderr, "warn: duplicate skill %s in %s, keeping first\n", f.Name, f.Subdir)
+			continue
+		}
+		dirs[f.Name] = f.Dir
+	}
+	skipped, err := s.engine.IndexSkillsByPath(dirs)
+	if err != nil {
+		return err
+	}
+	for name, err := range skipped {
+		fmt.Fprintf(os.Stderr, "warn: index %s: %v\n", name, err)
+		delete(dirs, name)
+	}
+	for _, f := range found {
+		if dirs[f.Name] != f.Dir {
+			continue
+		}
+		var updated int64
+		if fi, err := os.Stat(filepath.Join(f.Dir, "SKILL.md")); err == nil {
+			updated = fi.ModTime().Unix()
+		}
+		version := s.latestVersion(f.Name)
+		if version == "" {
+			if sk, err := skill.ParseFile(filepath.Join(f.Dir, "SKILL.md"), ""); err == nil {
+				version = sk.Version
+			}
+		}
+		skills[f.Name] = SkillResult{
+			ID:          f.Subdir,
+			Name:        f.Name,
+			Description: f.Description,
+			UpdatedAt:   updated,
+			Version:     version,
+		}
+	}
+	s.skills, s.dirs = skills, dirs
+	return nil
+}
+
+// Len returns the number of served skills.
+func (s *Server) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.skills)
+}
+
+// EnablePublish accepts uploads on POST /api/v1/skills/{name}/versions.
+// A non-empty token must be sent as a Bearer credential.
+func (s *Server) EnablePublish(token string) {
+	s.publish = true
+	s.token = token
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	p := strings.TrimSuffix(r.URL.Path, "/")
+	if r.Method == http.MethodPost && s.publish && strings.HasPrefix(p, "/api/v1/skills/") && strings.HasSuffix(p, "/versions") {
+		s.handlePublish(w, r, strings.TrimSuffix(strings.TrimPrefix(p, "/api/v1/skills/"), "/versions"))
+		return
+	}
+	if r.Method != http.MethodGet {
+		writeAPIError(w, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "only GET is supported")
+		return
+	}
+	switch {
+	case p == "/api/v1/skills/search":
+		s.handleSearch(w, r)
+	case p == "/api/v1/skills/ai-search":
+		s.handleAISearch(w, r)
+	case strings.HasPrefix(p, "/api/v1/skills/") && strings.HasSuffix(p, "/download"):
+		s.handleDownload(w, r, strings.TrimSuffix(strings.TrimPrefix(p, "/api/v1/skills/"), "/download"))
+	default:
+		writeAPIError(w, http.StatusNotFound, "NOT_FOUND", "unknown endpoint")
+	}
+}
+
+func (s *Server) handleSearch(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	page, _ := strconv.Atoi(q.Get("page"))
+	if page < 1 {
+		page = 1
+	}
+	limit, _ := strconv.Atoi(q.Get("limit"))
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+
+	s.mu.Lock()
+	var items []SkillResult
+	var total int
+	var err error
+	if q.Get("sortBy") == "recent" {
+		// Relevance is meaningless for a recency listing; rank every hit by mtime.
+		var hits []search.Result
+		hits, total, err = s.engine.SearchPage(q.Get("q"), len(s.skills)+1, 0)
+		items = s.results(hits)
+		sort.SliceStable(items, func(i, j int) bool { return items[i].UpdatedAt > items[j].UpdatedAt })
+		items = paginate(items, limit, page)
+	} else {
+		var hits []search.Result
+		hits, total, err = s.engine.SearchPage(q.Get("q"), limit, (page-1)*limit)
+		items = s.results(hits)
+	}
+	s.mu.Unlock()
+	if err != nil {
+		writeAPIError(w, http.StatusBadRequest, "BAD_QUERY", err.Error())
+		return
+	}
+
+	var resp searchResponse
+	resp.Success = true
+	resp.Data.Skills = items
+	resp.Data.Pagination.Page = page
+	resp.Data.Pagination.Limit = limit
+	resp.Data.Pagination.Total = total
+	resp.Data.Pagination.TotalPages = (total + limit - 1) / limit
+	resp.Data.Pagination.HasNext = page*limit < total
+	writeTagged(w, r, "application/json", encodeJSON(resp))
+}
+
+func (s *Server) handleAISearch(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query().Get("q")
+	s.mu.Lock()
+	hits, _, err := s.engine.SearchPage(query, 20, 0)
+	items := s.results(hits)
+	s.mu.Unlock()
+	if err != nil {
+		writeAPIError(w, http.StatusBadRequest, "BAD_QUERY", err.Error())
+		return
+	}
+
+	var resp aiSearchResponse
+	resp.Success = true
+	resp.Data.SearchQuery = query
+	for i := range items {
+		it := items[i]
+		resp.Data.Data = append(resp.Data.Data, aiSearchResult{
+			FileID:   it.ID,
+			Filename: it.Name + "/SKILL.md",
+			Score:    it.Score,
+			Skill:    &it,
+		})
+	}
+	writeTagged(w, r, "application/json", encodeJSON(resp))
+}
+
+func (s *Server) handleDownload(w http.ResponseWriter, r *http.Request, name string) {
+	if v := r.URL.Query().Get("version"); v != "" {
+		if !skill.ValidName(name) || !skill.ValidVersion(v) {
+			writeAPIError(w, http.StatusNotFound, "NOT_FOUND", "skill "+name+" "+v+" not found")
+			return
+		}
+		raw, err := os.ReadFile(s.archivePath(name, v))
+		if err != nil {
+			writeAPIError(w, http.StatusNotFound, "NOT_FOUND", "skill "+name+" "+v+" not found")
+			return
+		}
+		w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", name+"-"+v+".tar.gz"))
+		writeTagged(w, r, "application/gzip", raw)
+		return
+	}
+	s.mu.Lock()
+	dir, ok := s.dirs[name]
+	s.mu.Unlock()
+	if !ok {
+		writeAPIError(w, http.StatusNotFound, "NOT_FOUND", "skill "+name+" not found")
+		return
+	}
+	var buf bytes.Buffer
+	if err := archive.WriteTarGz(&buf, dir); err != nil {
+		fmt.Fprintf(os.Stderr, "warn: archive %s: %v\n", name, err)
+		writeAPIError(w, http.StatusInternalServerError, "ARCHIVE_FAILED", err.Error())
+		return
+	}
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", name+".tar.gz"))
+	writeTagged(w, r, "application/gzip", buf.Bytes())
+}
+
+func (s *Server) handlePublish(w http.ResponseWriter, r *http.Request, name string) {
+	if s.token != "" && r.Header.Get("Authorization") != "Bearer "+s.token {
+		writeAPIError(w, http.StatusUnauthorized, "UNAUTHORIZED", "missing or invalid token")
+		return
+	}
+	r.Body = http.MaxBytesReader(w, r.Body, maxUpload)
+	if err := r.ParseMultipartForm(maxUpload); err != nil {
+		writeAPIError(w, http.StatusBadRequest, "BAD_UPLOAD", err.Error())
+		return
+	}
+	var meta PublishMeta
+	if err := json.Unmarshal([]byte(r.FormValue("metadata")), &meta); err != nil {
+		writeAPIError(w, http.StatusBadRequest, "BAD_METADATA", "metadata: "+err.Error())
+		return
+	}
+	switch {
+	case meta.Name != name:
+		writeAPIError(w, http.StatusBadRequest, "BAD_METADATA", fmt.Sprintf("metadata name %q does not match %q", meta.Name, name))
+		return
+	case !skill.ValidName(name):
+		writeAPIError(w, http.StatusBadRequest, "BAD_METADATA", fmt.Sprintf("invalid skill name %q", name))
+		return
+	case !skill.ValidVersion(meta.Version):
+		writeAPIError(w, http.StatusBadRequest, "BAD_METADATA", fmt.Sprintf("version %q is not semver", meta.Version))
+		return
+	}
+	f, _, err := r.FormFile("archive")
+	if err != nil {
+		writeAPIError(w, http.StatusBadRequest, "BAD_UPLOAD", "archive: "+err.Error())
+		return
+	}
+	defer f.Close()
+	raw, err := io.ReadAll(f)
+	if err != nil {
+		writeAPIError(w, http.StatusBadRequest, "BAD_UPLOAD", err.Error())
+		return
+	}
+
+	s.publishMu.Lock()
+	defer s.publishMu.Unlock()
+	status, code, err := s.storeVersion(name, meta.Version, raw)
+	if err != nil {
+		writeAPIError(w, status, code, err.Error())
+		return
+	}
+	if err := s.Reload(); err != nil {
+		writeAPIError(w, http.StatusInternalServerError, "RELOAD_FAILED", err.Error())
+		return
+	}
+	s.mu.Lock()
+	res := s.skills[name]
+	s.mu.Unlock()
+	res.ArchiveURL = s.baseURL + "/api/v1/skills/" + name + "/download"
+	res.Version = meta.Version
+	writeJSON(w, http.StatusCreated, map[string]interface{}{"success": true, "data": res})
+}
+
+// storeVersion records an uploaded archive and, when it is the newest
+// version, replaces the served copy of the skill. Callers hold publishMu.
+func (s *Server) storeVersion(name, version string, raw []byte) (int, string, error) {
+	dest := s.archivePath(name, version)
+	if _, err := os.Stat(dest); err == nil {
+		return http.StatusConflict, "VERSION_EXISTS", fmt.Errorf("%s %s is already published", name, version)
+	}
+	latest := s.latestVersion(name)
+	s.mu.Lock()
+	_, served := s.dirs[name]
+	s.mu.Unlock()
+	if served && latest == "" {
+		return http.StatusConflict, "NOT_PUBLISHED", fmt.Errorf("%s is served from the registry directory and cannot be published over", name)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(dest), 0o755); err != nil {
+		return http.StatusInternalServerError, "STORE_FAILED", err
+	}
+	tmp, err := os.MkdirTemp(filepath.Join(s.root, publishedDir), ".tmp-")
+	if err != nil {
+		return http.StatusInternalServerError, "STORE_FAILED", err
+	}
+	defer os.RemoveAll(tmp)
+	if err := archive.ExtractTarGz(bytes.NewReader(raw), tmp); err != nil {
+		return http.StatusBadRequest, "BAD_ARCHIVE", fmt.Errorf("archive: %w", err)
+	}
+	if _, err := os.Stat(filepath.Join(tmp, "SKILL.md")); err != nil {
+		return http.StatusBadRequest, "BAD_ARCHIVE", fmt.Errorf("archive has no SKILL.md")
+	}
+	if err := os.WriteFile(dest, raw, 0o644); err != nil {
+		return http.StatusInternalServerError, "STORE_FAILED", err
+	}
+	if latest != "" && compareVersions(version, latest) < 0 {
+		return 0, "", nil
+	}
+
+	live := filepath.Join(s.root, name)
+	old := tmp + ".old"
+	if err := os.Rename(live, old); err != nil && !os.IsNotExist(err) {
+		return http.StatusInternalServerError, "STORE_FAILED", err
+	}
+	if err := os.Rename(tmp, live); err != nil {
+		_ = os.Rename(old, live)
+		return http.StatusInternalServerError, "STORE_FAILED", err
+	}
+	_ = os.RemoveAll(old)
+	return 0, "", nil
+}
+
+func (s *Server) archivePath(name, version string) string {
+	return filepath.Join(s.root, publishedDir, name, version+".tar.gz")
+}
+
+// latestVersion is the highest published version of name, or "".
+func (s *Server) latestVersion(name string) string {
+	entries, _ := os.ReadDir(filepath.Join(s.root, publishedDir, name))
+	latest := ""
+	for _, e := range entries {
+		v, ok := strings.CutSuffix(e.Name(), ".tar.gz")
+		if !ok || !skill.ValidVersion(v) {
+			continue
+		}
+		if latest == "" || compareVersions(v, latest) > 0 {
+			latest = v
+		}
+	}
+	return latest
+}
+
+// results maps engine hits to served skills; callers hold s.mu.
+func (s *Server) results(hits []search.Result) []SkillResult {
+	out := make([]SkillResult, 0, len(hits))
+	for _, h := range hits {
+		sk, ok := s.skills[h.Name]
+		if !ok {
+			continue
+		}
+		sk.Score = h.Score
+		sk.ArchiveURL = s.baseURL + "/api/v1/skills/" + sk.Name + "/download"
+		out = append(out, sk)
+	}
+	return out
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func encodeJSON(v interface{}) []byte {
+	raw, _ := json.Marshal(v)
+	return append(raw, '\n')
+}
+
+// writeTagged sends body with an ETag derived from its content, or a bare
+// 304 when the client already holds that version.
+func writeTagged(w http.ResponseWriter, r *http.Request, contentType string, body []byte) {
+	tag := `"` + hashKey(string(body)) + `"`
+	w.Header().Set("ETag", tag)
+	if r.Header.Get("If-None-Match") == tag {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	w.Header().Set("Content-Type", contentType)
+	_, _ = w.Write(body)
+}
+
+func writeAPIError(w http.ResponseWriter, status int, code, msg string) {
+	writeJSON(w, status, map[string]interface{}{
+		"success": false,
+		"error":   apiError{Code: code, Message: msg},
+	})
+}
@@ -0,0 +1,126 @@
+package registry
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func newTestServer(t *testing.T) (*httptest.Server, *Server) {
+	t.Helper()
+	root := t.TempDir()
+	writeSkill(t, root, "team/pdf-tools", "Extract text from PDF documents")
+	writeSkill(t, root, "team/go-review", "Review Go code for idioms")
+	os.WriteFile(filepath.Join(root, "team/pdf-tools/extract.py"), []byte("print()\n"), 0o644)
+
+	hs := httptest.NewUnstartedServer(nil)
+	hs.Start()
+	srv, err := NewServer(root, filepath.Join(t.TempDir(), "index"), hs.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	hs.Config.Handler = srv
+	t.Cleanup(hs.Close)
+	return hs, srv
+}
+
+func TestServer_ClientContract(t *testing.T) {
+	hs, srv := newTestServer(t)
+	if srv.Len() != 2 {
+		t.Fatalf("expected 2 served skills, got %d", srv.Len())
+	}
+	client := NewClient(hs.URL, t.TempDir(), "")
+
+	items, total, err := client.Search(context.Background(), "pdf", 10, 1, "stars")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if total != 1 || items[0].Name != "pdf-tools" || items[0].ArchiveURL == "" {
+		t.Fatalf("unexpected search: total=%d %+v", total, items)
+	}
+
+	all, total, err := client.Search(context.Background(), "*", 1, 2, "recent")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if total != 2 || len(all) != 1 {
+		t.Errorf("expected page 2 of 2 with one item, got total=%d len=%d", total, len(all))
+	}
+
+	ai, err := client.AISearch(context.Background(), "review idioms")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ai) == 0 || ai[0].Name != "go-review" || ai[0].Score == 0 {
+		t.Errorf("unexpected ai-search: %+v", ai)
+	}
+}
+
+func TestServer_FetchArchive(t *testing.T) {
+	hs, _ := newTestServer(t)
+	reg := NewSkillsMP("team", hs.URL, t.TempDir(), "")
+	r, err := reg.Get(context.Background(), "pdf-tools")
+	if err != nil {
+		t.Fatal(err)
+	}
+	dest := t.TempDir()
+	if err := reg.Fetch(context.Background(), r, dest); err != nil {
+		t.Fatal(err)
+	}
+	for _, f := range []string{"SKILL.md", "extract.py"} {
+		if _, err := os.Stat(filepath.Join(dest, f)); err != nil {
+			t.Errorf("%s missing after fetch: %v", f, err)
+		}
+	}
+
+	if _, err := NewClient(hs.URL, t.TempDir(), "").doGet(context.Background(), hs.URL+"/api/v1/skills/nope/download"); err == nil {
+		t.Error("expected 404 for unknown skill")
+	}
+}
+
+func TestServer_ETag(t *testing.T) {
+	hs, _ := newTestServer(t)
+	get := func(tag string) *http.Response {
+		req, _ := http.NewRequest(http.MethodGet, hs.URL+"/api/v1/skills/pdf-tools/download", nil)
+		if tag != "" {
+			req.Header.Set("If-None-Match", tag)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		return resp
+	}
+	first := get("")
+	tag := first.Header.Get("ETag")
+	if first.StatusCode != http.StatusOK || tag == "" {
+		t.Fatalf("expected 200 with ETag, got %d %q", first.StatusCode, tag)
+	}
+	if resp := get(tag); resp.StatusCode != http.StatusNotModified {
+		t.Errorf("expected 304 for matching ETag, got %d", resp.StatusCode)
+	}
+}
+
+func TestNewServer_KeepsForeignFilesInIndexDir(t *testing.T) {
+	root, indexDir := t.TempDir(), t.TempDir()
+	writeSkill(t, root, "pdf-tools", "Extract text from PDF documents")
+	keep := filepath.Join(indexDir, "notes.txt")
+	os.WriteFile(keep, []byte("mine\n"), 0o644)
+
+	for i := 0; i < 2; i++ {
+		srv, err := NewServer(root, indexDir, "http://localhost")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if srv.Len() != 1 {
+			t.Fatalf("expected 1 served skill, got %d", srv.Len())
+		}
+	}
+	if raw, err := os.ReadFile(keep); err != nil || string(raw) != "mine\n" {
+		t.Errorf("--index dir contents removed: %q, %v", raw, err)
+	}
+}
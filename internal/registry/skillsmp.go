@@ -0,0 +1,66 @@
+package registry
+
+import "context"
+
+// SkillsMP adapts the skillsmp.com API client to the Registry interface.
+type SkillsMP struct {
+	name   string
+	client *Client
+}
+
+func NewSkillsMP(name, baseURL, cacheDir, apiKey string) *SkillsMP {
+	if name == "" {
+		name = "skillsmp"
+	}
+	return &SkillsMP{name: name, client: NewClient(baseURL, cacheDir, apiKey)}
+}
+
+func (s *SkillsMP) Name() string { return s.name }
+
+func (s *SkillsMP) Search(ctx context.Context, query string, limit, page int) ([]SkillResult, int, error) {
+	items, total, err := s.client.Search(ctx, query, limit, page, "stars")
+	return s.tag(items), total, err
+}
+
+func (s *SkillsMP) Get(ctx context.Context, name string) (SkillResult, error) {
+	items, _, err := s.client.Search(ctx, name, 10, 1, "stars")
+	if err != nil {
+		return SkillResult{}, err
+	}
+	return findByName(s.tag(items), name)
+}
+
+func (s *SkillsMP) Trending(ctx context.Context, limit, page int) ([]SkillResult, int, error) {
+	items, total, err := s.client.Trending(ctx, limit, page)
+	return s.tag(items), total, err
+}
+
+// SearchPage is Search with the API's hasNext flag.
+func (s *SkillsMP) SearchPage(ctx context.Context, query string, limit, n int) (Page, error) {
+	p, err := s.client.searchPage(ctx, query, limit, n, "stars")
+	p.Items = s.tag(p.Items)
+	return p, err
+}
+
+func (s *SkillsMP) TrendingPage(ctx context.Context, limit, n int) (Page, error) {
+	return s.SearchPage(ctx, "*", limit, n)
+}
+
+func (s *SkillsMP) Fetch(ctx context.Context, r SkillResult, dest string) error {
+	if r.ArchiveURL != "" {
+		return s.client.DownloadArchive(ctx, r.ArchiveURL, dest)
+	}
+	return s.client.DownloadSkill(ctx, r.Name, r.GithubURL, dest)
+}
+
+func (s *SkillsMP) AISearch(ctx context.Context, query string) ([]SkillResult, error) {
+	items, err := s.client.AISearch(ctx, query)
+	return s.tag(items), err
+}
+
+func (s *SkillsMP) tag(items []SkillResult) []SkillResult {
+	for i := range items {
+		items[i].Registry = s.name
+	}
+	return items
+}
@@ -0,0 +1,87 @@
+package registry
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"time"
+
+	"github.com/ZiaoLiu-1/pskill/internal/config"
+)
+
+// ProxyDirect as a registry's proxy bypasses any proxy set in the environment.
+const ProxyDirect = "direct"
+
+// newHTTPClient builds the HTTP client for a registry, applying its proxy,
+// CA bundle and client certificate. Without any of them the client behaves
+// like the default one, HTTPS_PROXY and NO_PROXY included.
+func newHTTPClient(rc config.RegistryConfig) (*http.Client, error) {
+	tr := http.DefaultTransport.(*http.Transport).Clone()
+	switch rc.Proxy {
+	case "":
+	case ProxyDirect:
+		tr.Proxy = nil
+	default:
+		u, err := url.Parse(rc.Proxy)
+		if err != nil || u.Host == "" {
+			return nil, fmt.Errorf("proxy %q: want a URL such as http://proxy:3128", rc.Proxy)
+		}
+		tr.Proxy = http.ProxyURL(u)
+	}
+
+	if rc.CACert != "" || rc.ClientCert != "" || rc.ClientKey != "" {
+		tr.TLSClientConfig = &tls.Config{MinVersion: tls.VersionTLS12}
+	}
+	if rc.CACert != "" {
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		pem, err := os.ReadFile(expandHome(rc.CACert))
+		if err != nil {
+			return nil, fmt.Errorf("caCert: %w", err)
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("caCert %s: no PEM certificates found", rc.CACert)
+		}
+		tr.TLSClientConfig.RootCAs = pool
+	}
+	if rc.ClientCert != "" || rc.ClientKey != "" {
+		if rc.ClientCert == "" || rc.ClientKey == "" {
+			return nil, fmt.Errorf("clientCert and clientKey must be set together")
+		}
+		cert, err := tls.LoadX509KeyPair(expandHome(rc.ClientCert), expandHome(rc.ClientKey))
+		if err != nil {
+			return nil, fmt.Errorf("client certificate: %w", err)
+		}
+		tr.TLSClientConfig.Certificates = []tls.Certificate{cert}
+	}
+	return &http.Client{Transport: tr, Timeout: 20 * time.Second}, nil
+}
+
+// gitConfig translates a registry's network settings into git -c options.
+// git replaces its CA bundle rather than extending it, so CACert should hold
+// every root the remote needs.
+func gitConfig(rc config.RegistryConfig) []string {
+	var kv []string
+	switch rc.Proxy {
+	case "":
+	case ProxyDirect:
+		kv = append(kv, "http.proxy=")
+	default:
+		kv = append(kv, "http.proxy="+rc.Proxy)
+	}
+	if rc.CACert != "" {
+		kv = append(kv, "http.sslCAInfo="+expandHome(rc.CACert))
+	}
+	if rc.ClientCert != "" {
+		kv = append(kv, "http.sslCert="+expandHome(rc.ClientCert))
+	}
+	if rc.ClientKey != "" {
+		kv = append(kv, "http.sslKey="+expandHome(rc.ClientKey))
+	}
+	return kv
+}
@@ -0,0 +1,101 @@
+package registry
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/ZiaoLiu-1/pskill/internal/config"
+)
+
+func searchHandler(w http.ResponseWriter, r *http.Request) {
+	var resp searchResponse
+	resp.Success = true
+	resp.Data.Skills = []SkillResult{{Name: "via-" + r.Host}}
+	resp.Data.Pagination.Total = 1
+	_ = json.NewEncoder(w).Encode(resp)
+}
+
+func TestNew_CACert(t *testing.T) {
+	ts := httptest.NewTLSServer(http.HandlerFunc(searchHandler))
+	defer ts.Close()
+	cfg := config.Config{HomeDir: t.TempDir(), CacheDir: t.TempDir()}
+	rc := config.RegistryConfig{Name: "corp", Type: TypeSkillsMP, URL: ts.URL}
+
+	r, err := New(rc, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := r.Search(context.Background(), "x", 1, 1); err == nil || !strings.Contains(err.Error(), "caCert") {
+		t.Fatalf("err = %v, want an unknown CA rejected with a caCert hint", err)
+	}
+
+	ca := filepath.Join(t.TempDir(), "ca.pem")
+	block := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: ts.Certificate().Raw})
+	if err := os.WriteFile(ca, block, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	rc.CACert = ca
+	cfg.CacheDir = t.TempDir()
+	if r, err = New(rc, cfg); err != nil {
+		t.Fatal(err)
+	}
+	items, _, err := r.Search(context.Background(), "x", 1, 1)
+	if err != nil || len(items) != 1 {
+		t.Fatalf("Search with caCert = %v, %v", items, err)
+	}
+}
+
+func TestNew_Proxy(t *testing.T) {
+	var proxied atomic.Int32
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// A forward proxy sees the absolute URL of the upstream request.
+		if r.URL.Host == "registry.invalid" {
+			proxied.Add(1)
+		}
+		searchHandler(w, r)
+	}))
+	defer proxy.Close()
+
+	cfg := config.Config{HomeDir: t.TempDir(), CacheDir: t.TempDir()}
+	rc := config.RegistryConfig{Name: "corp", Type: TypeSkillsMP, URL: "http://registry.invalid", Proxy: proxy.URL}
+	r, err := New(rc, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := r.Search(context.Background(), "x", 1, 1); err != nil {
+		t.Fatal(err)
+	}
+	if proxied.Load() != 1 {
+		t.Errorf("proxied requests = %d, want 1", proxied.Load())
+	}
+}
+
+func TestNewHTTPClient_Errors(t *testing.T) {
+	for _, rc := range []config.RegistryConfig{
+		{Proxy: "not a url"},
+		{CACert: filepath.Join(t.TempDir(), "missing.pem")},
+		{ClientCert: "cert.pem"},
+	} {
+		if _, err := newHTTPClient(rc); err == nil {
+			t.Errorf("newHTTPClient(%+v) succeeded", rc)
+		}
+	}
+	if _, err := newHTTPClient(config.RegistryConfig{Proxy: ProxyDirect}); err != nil {
+		t.Errorf("direct: %v", err)
+	}
+}
+
+func TestGitConfig(t *testing.T) {
+	got := strings.Join(gitConfig(config.RegistryConfig{Proxy: "http://p:3128", CACert: "/ca.pem"}), " ")
+	if got != "http.proxy=http://p:3128 http.sslCAInfo=/ca.pem" {
+		t.Errorf("gitConfig = %q", got)
+	}
+}
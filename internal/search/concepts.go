@@ -0,0 +1,65 @@
+package search
+
+import (
+	"sort"
+
+	"github.com/ZiaoLiu-1/pskill/internal/skill"
+)
+
+// extraConcepts extends the tag taxonomy with words that mean the same
+// thing in a request as in a skill description, so "tests for my api"
+// meets "test suites for REST endpoints". Together they act as a small
+// bundled embedding: each concept is one dimension.
+var extraConcepts = map[string][]string{
+	"backend":     {"rest", "http", "route", "request", "response", "openapi", "swagger", "webhook", "service"},
+	"testing":     {"suite", "spec", "assert", "assertion", "mock", "fixture", "regression", "qa"},
+	"docs":        {"docstring", "comment", "wiki", "manual", "reference"},
+	"devops":      {"container", "cluster", "release", "rollout", "ship", "workflow", "actions"},
+	"database":    {"table", "orm", "db", "index", "column"},
+	"security":    {"password", "token", "credential", "permission", "xss", "csrf", "injection", "cve"},
+	"frontend":    {"page", "style", "stylesheet", "dom", "widget", "button", "form", "web"},
+	"data":        {"dataset", "spreadsheet", "excel", "xlsx", "plot", "graph", "statistics", "report"},
+	"writing":     {"write", "draft", "copy", "article", "post", "letter", "tone"},
+	"review":      {"quality", "smell", "simplify", "clean", "readability"},
+	"generate":    {"generate", "scaffold", "produce", "author", "build", "implement", "add", "write", "boilerplate"},
+	"fix":         {"fix", "debug", "bug", "error", "crash", "troubleshoot", "diagnose", "repair", "failure", "stacktrace"},
+	"explain":     {"explain", "summarize", "summary", "describe", "understand", "walkthrough", "onboard"},
+	"performance": {"performance", "fast", "slow", "latency", "profile", "profiling", "benchmark", "optimize", "speed", "memory"},
+	"documents":   {"pdf", "docx", "word", "pptx", "slide", "presentation", "powerpoint", "document"},
+}
+
+// conceptsOf maps a term stem to the concepts it signals.
+var conceptsOf = buildConcepts()
+
+func buildConcepts() map[string][]string {
+	all := map[string][]string{}
+	for c, words := range skill.Taxonomy {
+		all[c] = append(all[c], words...)
+	}
+	for c, words := range extraConcepts {
+		all[c] = append(all[c], words...)
+	}
+	out := map[string][]string{}
+	for c, words := range all {
+		for _, w := range words {
+			for _, t := range skill.Terms(w) {
+				if !containsString(out[t.Stem], c) {
+					out[t.Stem] = append(out[t.Stem], c)
+				}
+			}
+		}
+	}
+	for _, cs := range out {
+		sort.Strings(cs)
+	}
+	return out
+}
+
+func containsString(items []string, s string) bool {
+	for _, it := range items {
+		if it == s {
+			return true
+		}
+	}
+	return false
+}
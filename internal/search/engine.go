@@ -1,19 +1,40 @@
 package search
 
 import (
+	"errors"
+	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 
 	"github.com/blevesearch/bleve/v2"
+	"github.com/blevesearch/bleve/v2/search/query"
 
 	"github.com/ZiaoLiu-1/pskill/internal/skill"
+	"github.com/ZiaoLiu-1/pskill/internal/store"
 )
 
+// Result is one ranked skill. Score blends Keyword, the BM25 score scaled
+// to the best hit, with Semantic, the similarity of the query's and the
+// skill's term and concept vectors. Why names the words that matched.
 type Result struct {
 	Name        string  `json:"name"`
 	Description string  `json:"description"`
 	Score       float64 `json:"score"`
+	Keyword     float64 `json:"keyword"`
+	Semantic    float64 `json:"semantic"`
+	Why         string  `json:"why,omitempty"`
+}
+
+// keywordWeight is the share of BM25 in the hybrid score.
+const keywordWeight = 0.5
+
+// Page is one page of results and the facet counts of every match.
+type Page struct {
+	Results []Result `json:"results"`
+	Total   int      `json:"total"`
+	Facets  []Facet  `json:"facets,omitempty"`
 }
 
 type indexedSkill struct {
@@ -21,81 +42,550 @@ type indexedSkill struct {
 	Description string `json:"description"`
 	Body        string `json:"body"`
 	Tags        string `json:"tags"`
+
+	// Facets, as keywords.
+	Tag       []string `json:"tag"`
+	CLI       []string `json:"cli"`
+	Installed []string `json:"installed"`
+	Source    []string `json:"source"`
+	Author    []string `json:"author"`
 }
 
+// schemaVersion is stored in the index. Bump it whenever the mapping,
+// the indexed fields or the vectors file change shape: an index written
+// with another version is rebuilt from the engine's source on open.
+const schemaVersion = "4"
+
+var schemaKey = []byte("pskill.schema")
+
+// Source lists the skills an index should hold, name → skill directory.
+type Source func() (map[string]string, error)
+
 type Engine struct {
 	indexDir string
+	source   Source
+	facets   func(name string) facetValues
 }
 
+// NewEngine returns an engine over indexDir. An index that is missing,
+// corrupt or from another schema version is replaced by an empty one.
 func NewEngine(indexDir string) *Engine {
 	return &Engine{indexDir: indexDir}
 }
 
+// NewStoreEngine returns an engine over indexDir that rebuilds the index
+// from the skills in storeDir whenever it has to be replaced, and indexes
+// their links, source and install scope as facets.
+func NewStoreEngine(indexDir, storeDir string) *Engine {
+	return &Engine{indexDir: indexDir, source: StoreSource(storeDir), facets: storeFacets(storeDir)}
+}
+
+// StoreSource lists the skills in a pskill store, including linked dev
+// skills and excluding quarantined ones.
+func StoreSource(storeDir string) Source {
+	return func() (map[string]string, error) {
+		st := store.NewManager(storeDir)
+		names, err := st.ListSkills()
+		if err != nil {
+			return nil, err
+		}
+		dirs := make(map[string]string, len(names))
+		for _, name := range names {
+			dirs[name] = st.SkillDir(name)
+		}
+		return dirs, nil
+	}
+}
+
 func (e *Engine) IndexSkill(sk skill.Skill) error {
 	idx, err := e.openOrCreate()
 	if err != nil {
 		return err
 	}
 	defer idx.Close()
-	doc := indexedSkill{
-		Name:        sk.Name,
-		Description: sk.Description,
-		Body:        sk.Body,
-		Tags:        strings.Join(sk.Tags, " "),
+	doc := e.document(sk)
+	if err := idx.Index(sk.Name, doc); err != nil {
+		return err
 	}
-	return idx.Index(sk.Name, doc)
+	vs, err := e.loadVectors()
+	if err != nil {
+		return err
+	}
+	vs.Docs[sk.Name] = newTermDoc(doc)
+	return e.saveVectors(vs)
 }
 
 func (e *Engine) IndexSkillByPath(name, dir string) error {
-	path := filepath.Join(dir, "SKILL.md")
-	raw, err := os.ReadFile(path)
+	sk, err := parseSkillDir(name, dir)
 	if err != nil {
 		return err
 	}
-	sk, err := skill.Parse(raw, path, "")
+	return e.IndexSkill(sk)
+}
+
+// IndexSkillsByPath indexes many skills, name → skill directory, in one
+// batch that rewrites the vectors once; use it over IndexSkillByPath for
+// more than a single skill. Skills whose SKILL.md cannot be read are left
+// out and returned in skipped, keyed by name.
+func (e *Engine) IndexSkillsByPath(dirs map[string]string) (skipped map[string]error, err error) {
+	if len(dirs) == 0 {
+		return nil, nil
+	}
+	idx, err := e.openOrCreate()
+	if err != nil {
+		return nil, err
+	}
+	defer idx.Close()
+	vs, err := e.loadVectors()
+	if err != nil {
+		return nil, err
+	}
+	if skipped, err = e.indexDirs(idx, dirs, vs); err != nil {
+		return skipped, err
+	}
+	return skipped, e.saveVectors(vs)
+}
+
+// DeleteSkill removes a skill from the index. Deleting a skill that is not
+// indexed is not an error.
+func (e *Engine) DeleteSkill(name string) error {
+	idx, err := e.openOrCreate()
 	if err != nil {
 		return err
 	}
-	sk.Name = name
-	return e.IndexSkill(sk)
+	defer idx.Close()
+	if err := idx.Delete(name); err != nil {
+		return err
+	}
+	vs, err := e.loadVectors()
+	if err != nil {
+		return err
+	}
+	if _, ok := vs.Docs[name]; !ok {
+		return nil
+	}
+	delete(vs.Docs, name)
+	return e.saveVectors(vs)
 }
 
-func (e *Engine) Search(query string, limit int) ([]Result, error) {
+// Names returns the names of every indexed skill, sorted.
+func (e *Engine) Names() ([]string, error) {
 	idx, err := e.openOrCreate()
 	if err != nil {
 		return nil, err
 	}
 	defer idx.Close()
-	q := bleve.NewQueryStringQuery(query)
-	req := bleve.NewSearchRequestOptions(q, limit, 0, false)
-	req.Fields = []string{"name", "description"}
+	count, err := idx.DocCount()
+	if err != nil {
+		return nil, err
+	}
+	resp, err := idx.Search(bleve.NewSearchRequestOptions(bleve.NewMatchAllQuery(), int(count), 0, false))
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(resp.Hits))
+	for _, h := range resp.Hits {
+		names = append(names, h.ID)
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// Reindex replaces the index with one built from the engine's source and
+// returns how many skills it holds. Skills whose SKILL.md cannot be read
+// are left out and returned in skipped.
+func (e *Engine) Reindex() (indexed int, skipped []error, err error) {
+	if e.source == nil {
+		return 0, nil, errors.New("search engine has no source to reindex from")
+	}
+	if err := e.Clear(); err != nil {
+		return 0, nil, err
+	}
+	idx, skipped, err := e.create()
+	if err != nil {
+		return 0, skipped, err
+	}
+	defer idx.Close()
+	count, err := idx.DocCount()
+	return int(count), skipped, err
+}
+
+func (e *Engine) Search(query string, limit int) ([]Result, error) {
+	out, _, err := e.SearchPage(query, limit, 0)
+	return out, err
+}
+
+// SearchPage returns one page of hits and the total hit count, as Query
+// does.
+func (e *Engine) SearchPage(text string, limit, offset int) ([]Result, int, error) {
+	p, err := e.Query(text, limit, offset)
+	return p.Results, p.Total, err
+}
+
+// Query returns one page of the skills matching text, written in the
+// syntax of ParseQuery, and facet counts over all of them. Free text is
+// ranked by keyword and semantic similarity together, so a skill can
+// match on meaning without sharing a word with the query. With no text or
+// "*", every skill passing the filters matches, sorted by name.
+func (e *Engine) Query(text string, limit, offset int) (Page, error) {
+	q := ParseQuery(text)
+	idx, err := e.openOrCreate()
+	if err != nil {
+		return Page{}, err
+	}
+	defer idx.Close()
+	count, err := idx.DocCount()
+	if err != nil {
+		return Page{}, err
+	}
+	req := bleve.NewSearchRequestOptions(filterQuery(q), int(count), 0, false)
+	req.Fields = append([]string{"description"}, facetFields...)
 	resp, err := idx.Search(req)
+	if err != nil {
+		return Page{}, err
+	}
+	values := map[string]facetValues{}
+	ranked := make([]Result, 0, len(resp.Hits))
+	for _, h := range resp.Hits {
+		fv := facetValues{}
+		for _, field := range facetFields {
+			fv[field] = asStrings(h.Fields[field])
+		}
+		values[h.ID] = fv
+		ranked = append(ranked, Result{Name: h.ID, Description: asString(h.Fields["description"])})
+	}
+
+	if t := strings.TrimSpace(q.Text); t != "" && t != "*" {
+		var only []string
+		if q.Filtered() {
+			only = make([]string, 0, len(values))
+			for name := range values {
+				only = append(only, name)
+			}
+		}
+		all, err := e.hybrid(idx, t, only, count)
+		if err != nil {
+			return Page{}, err
+		}
+		ranked = ranked[:0]
+		for _, r := range all {
+			if _, ok := values[r.Name]; ok {
+				ranked = append(ranked, r)
+			}
+		}
+	} else {
+		sort.Slice(ranked, func(i, j int) bool { return ranked[i].Name < ranked[j].Name })
+	}
+	names := make([]string, len(ranked))
+	for i, r := range ranked {
+		names[i] = r.Name
+	}
+	return Page{
+		Results: paginate(ranked, limit, offset),
+		Total:   len(ranked),
+		Facets:  countFacets(names, values),
+	}, nil
+}
+
+// hybrid ranks every skill that matches text by keyword or closely enough
+// by meaning. When only is set, keyword matching is limited to those
+// skills, which bounds the request to the filtered set rather than the
+// count skills in the index.
+func (e *Engine) hybrid(idx bleve.Index, text string, only []string, count uint64) ([]Result, error) {
+	size := int(count)
+	restrict := func(q query.Query) query.Query {
+		if only == nil {
+			return q
+		}
+		// The ID filter scores nothing, so keyword scores are unchanged.
+		ids := bleve.NewDocIDQuery(only)
+		ids.SetBoost(0)
+		return bleve.NewConjunctionQuery(q, ids)
+	}
+	if only != nil {
+		size = len(only)
+	}
+	resp, err := idx.Search(keywordRequest(restrict(keywordQuery(text, false)), size))
+	if err != nil {
+		return nil, err
+	}
+	// Nothing matched as typed: retry allowing typos.
+	fuzzy := false
+	if resp.Total == 0 {
+		if q := keywordQuery(text, true); q != nil {
+			if resp, err = idx.Search(keywordRequest(restrict(q), size)); err != nil {
+				return nil, err
+			}
+			fuzzy = true
+		}
+	}
+	vs, err := e.vectors(idx, count)
 	if err != nil {
 		return nil, err
 	}
-	out := make([]Result, 0, len(resp.Hits))
+
+	byName := map[string]*Result{}
+	closeTo := map[string]string{}
+	best := resp.MaxScore
 	for _, h := range resp.Hits {
-		item := Result{
-			Name:        h.ID,
-			Description: asString(h.Fields["description"]),
-			Score:       h.Score,
+		r := &Result{Name: h.ID, Description: asString(h.Fields["description"])}
+		if best > 0 {
+			r.Keyword = h.Score / best
+		}
+		byName[h.ID] = r
+		if fuzzy {
+			closeTo[h.ID] = "close to: " + strings.Join(matchedTerms(h.Locations), ", ")
 		}
-		out = append(out, item)
 	}
+	for name, m := range semanticSearch(vs, text) {
+		r, ok := byName[name]
+		if !ok {
+			if m.score < minSemantic {
+				continue
+			}
+			r = &Result{Name: name, Description: vs.Docs[name].Description}
+			byName[name] = r
+		}
+		r.Semantic, r.Why = m.score, m.why
+	}
+
+	out := make([]Result, 0, len(byName))
+	for _, r := range byName {
+		r.Score = keywordWeight*r.Keyword + (1-keywordWeight)*r.Semantic
+		switch {
+		case closeTo[r.Name] != "" && r.Why != "":
+			r.Why = closeTo[r.Name] + "; " + r.Why
+		case closeTo[r.Name] != "":
+			r.Why = closeTo[r.Name]
+		case r.Why == "":
+			r.Why = "keyword match"
+		}
+		out = append(out, *r)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Score != out[j].Score {
+			return out[i].Score > out[j].Score
+		}
+		return out[i].Name < out[j].Name
+	})
 	return out, nil
 }
 
+func keywordRequest(q query.Query, size int) *bleve.SearchRequest {
+	req := bleve.NewSearchRequestOptions(q, size, 0, false)
+	req.Fields = []string{"description"}
+	req.IncludeLocations = true
+	return req
+}
+
+// vectors loads the stored term counts, rebuilding them from the fields
+// bleve stores when they do not cover the same skills as the index, such
+// as for an index built before they existed.
+func (e *Engine) vectors(idx bleve.Index, count uint64) (vectorStore, error) {
+	vs, err := e.loadVectors()
+	if err != nil {
+		return vs, err
+	}
+	if len(vs.Docs) == int(count) {
+		return vs, nil
+	}
+	req := bleve.NewSearchRequestOptions(bleve.NewMatchAllQuery(), int(count), 0, false)
+	req.Fields = []string{"name", "description", "body", "tags"}
+	resp, err := idx.Search(req)
+	if err != nil {
+		return vs, err
+	}
+	vs = vectorStore{Docs: map[string]termDoc{}}
+	for _, h := range resp.Hits {
+		vs.Docs[h.ID] = newTermDoc(indexedSkill{
+			Name:        asString(h.Fields["name"]),
+			Description: asString(h.Fields["description"]),
+			Body:        asString(h.Fields["body"]),
+			Tags:        asString(h.Fields["tags"]),
+		})
+	}
+	_ = e.saveVectors(vs)
+	return vs, nil
+}
+
+func paginate(items []Result, limit, offset int) []Result {
+	if offset >= len(items) {
+		return []Result{}
+	}
+	items = items[offset:]
+	if limit > 0 && len(items) > limit {
+		items = items[:limit]
+	}
+	return items
+}
+
+// openOrCreate opens the index, replacing it when it is missing, cannot be
+// opened or was written with another schema version.
 func (e *Engine) openOrCreate() (bleve.Index, error) {
-	_ = os.MkdirAll(e.indexDir, 0o755)
 	idx, err := bleve.Open(e.indexDir)
 	if err == nil {
-		return idx, nil
+		v, err := idx.GetInternal(schemaKey)
+		if err == nil && string(v) == schemaVersion {
+			return idx, nil
+		}
+		idx.Close()
+	}
+	if err := e.Clear(); err != nil {
+		return nil, err
+	}
+	idx, _, err = e.create()
+	return idx, err
+}
+
+// create writes a new index stamped with the schema version and fills it
+// from the source, if any.
+func (e *Engine) create() (bleve.Index, []error, error) {
+	if err := os.MkdirAll(filepath.Dir(e.indexDir), 0o755); err != nil {
+		return nil, nil, err
+	}
+	m, err := newIndexMapping()
+	if err != nil {
+		return nil, nil, err
+	}
+	idx, err := bleve.New(e.indexDir, m)
+	if err != nil {
+		return nil, nil, fmt.Errorf("create search index: %w", err)
 	}
-	mapping := bleve.NewIndexMapping()
-	return bleve.New(e.indexDir, mapping)
+	if err := idx.SetInternal(schemaKey, []byte(schemaVersion)); err != nil {
+		idx.Close()
+		return nil, nil, err
+	}
+	if e.source == nil {
+		return idx, nil, nil
+	}
+	dirs, err := e.source()
+	if err != nil {
+		idx.Close()
+		return nil, nil, err
+	}
+	vs := vectorStore{Docs: map[string]termDoc{}}
+	failed, err := e.indexDirs(idx, dirs, vs)
+	skipped := make([]error, 0, len(failed))
+	for _, name := range sortedNames(failed) {
+		skipped = append(skipped, fmt.Errorf("%s: %w", name, failed[name]))
+	}
+	if err != nil {
+		idx.Close()
+		return nil, skipped, err
+	}
+	if err := e.saveVectors(vs); err != nil {
+		idx.Close()
+		return nil, skipped, err
+	}
+	return idx, skipped, nil
+}
+
+// indexDirs indexes the skills in dirs in one batch and adds their term
+// counts to vs. Skills that cannot be read or indexed are returned in
+// skipped, keyed by name.
+func (e *Engine) indexDirs(idx bleve.Index, dirs map[string]string, vs vectorStore) (skipped map[string]error, err error) {
+	skipped = map[string]error{}
+	batch := idx.NewBatch()
+	docs := map[string]termDoc{}
+	for _, name := range sortedNames(dirs) {
+		sk, err := parseSkillDir(name, dirs[name])
+		if err != nil {
+			skipped[name] = err
+			continue
+		}
+		doc := e.document(sk)
+		if err := batch.Index(name, doc); err != nil {
+			skipped[name] = err
+			continue
+		}
+		docs[name] = newTermDoc(doc)
+	}
+	if err := idx.Batch(batch); err != nil {
+		return skipped, err
+	}
+	for name, td := range docs {
+		vs.Docs[name] = td
+	}
+	return skipped, nil
+}
+
+// Clear deletes the files bleve and the vectors keep in the index
+// directory, leaving the directory and anything else in it alone.
+func (e *Engine) Clear() error {
+	for _, name := range []string{"index_meta.json", "store", vectorsFile} {
+		if err := os.RemoveAll(filepath.Join(e.indexDir, name)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// document is what gets indexed for sk. The author in SKILL.md wins over
+// the one the store knows.
+func (e *Engine) document(sk skill.Skill) indexedSkill {
+	fv := facetValues{}
+	if e.facets != nil {
+		fv = e.facets(sk.Name)
+	}
+	fv[FacetTag] = sk.Tags
+	if sk.Author != "" {
+		fv[FacetAuthor] = []string{sk.Author}
+	}
+	fv = fv.normalize()
+	return indexedSkill{
+		Name:        sk.Name,
+		Description: sk.Description,
+		Body:        sk.Body,
+		Tags:        strings.Join(sk.Tags, " "),
+		Tag:         fv[FacetTag],
+		CLI:         fv[FacetCLI],
+		Installed:   fv[FacetInstalled],
+		Source:      fv[FacetSource],
+		Author:      fv[FacetAuthor],
+	}
+}
+
+func parseSkillDir(name, dir string) (skill.Skill, error) {
+	path := filepath.Join(dir, "SKILL.md")
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return skill.Skill{}, err
+	}
+	sk, err := skill.Parse(raw, path, "")
+	if err != nil {
+		return skill.Skill{}, err
+	}
+	sk.Name = name
+	return sk, nil
 }
 
 func asString(v interface{}) string {
 	s, _ := v.(string)
 	return s
 }
+
+// asStrings reads a stored field that holds one value or several.
+func asStrings(v interface{}) []string {
+	switch v := v.(type) {
+	case string:
+		return []string{v}
+	case []interface{}:
+		out := make([]string, 0, len(v))
+		for _, x := range v {
+			if s, ok := x.(string); ok {
+				out = append(out, s)
+			}
+		}
+		return out
+	}
+	return nil
+}
+
+func sortedNames[V any](m map[string]V) []string {
+	names := make([]string, 0, len(m))
+	for name := range m {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,358 @@
+package search
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ZiaoLiu-1/pskill/internal/skill"
+	"github.com/ZiaoLiu-1/pskill/internal/store"
+)
+
+func newTestEngine(t *testing.T) *Engine {
+	t.Helper()
+	e := NewEngine(filepath.Join(t.TempDir(), "index"))
+	for _, sk := range []skill.Skill{
+		{Name: "rest-suite-gen", Description: "Generate unit test suites for REST endpoints", Body: "Reads route handlers and writes table-driven cases."},
+		{Name: "frontend-design", Description: "Build distinctive web pages with React and Tailwind", Tags: []string{"frontend", "design"}},
+		{Name: "pdf", Description: "Extract text and tables from PDF documents", Body: "Fill forms and merge files."},
+		{Name: "commit-writer", Description: "Draft conventional commit messages from staged changes"},
+	} {
+		if err := e.IndexSkill(sk); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return e
+}
+
+func TestSearch_MatchesByMeaning(t *testing.T) {
+	e := newTestEngine(t)
+	res, err := e.Search("api coverage", 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res) == 0 || res[0].Name != "rest-suite-gen" {
+		t.Fatalf("results = %+v, want rest-suite-gen first", res)
+	}
+	top := res[0]
+	// BM25 sees no shared stem: "api" and "coverage" appear nowhere.
+	if top.Keyword != 0 || top.Semantic <= 0 || !strings.Contains(top.Why, "suite") || !strings.Contains(top.Why, "endpoint") {
+		t.Errorf("top result = %+v, want a semantic-only match explained by suite and endpoint", top)
+	}
+	for _, r := range res {
+		if r.Name == "pdf" {
+			t.Errorf("unrelated skill matched: %+v", r)
+		}
+	}
+}
+
+func TestSearch_KeywordStillCounts(t *testing.T) {
+	e := newTestEngine(t)
+	res, err := e.Search("tailwind", 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res) != 1 || res[0].Name != "frontend-design" || res[0].Keyword != 1 {
+		t.Fatalf("results = %+v", res)
+	}
+
+	// Query-string syntax still works; a field query has no semantic side.
+	res, err = e.Search("name:pdf", 10)
+	if err != nil || len(res) != 1 || res[0].Name != "pdf" || res[0].Why == "" {
+		t.Fatalf("field query = %+v, %v", res, err)
+	}
+}
+
+func TestSearch_RebuildsMissingVectors(t *testing.T) {
+	e := newTestEngine(t)
+	if err := os.Remove(e.vectorsPath()); err != nil {
+		t.Fatal(err)
+	}
+	res, err := e.Search("slide decks and documents", 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res) == 0 || res[0].Name != "pdf" {
+		t.Fatalf("results = %+v, want pdf via rebuilt vectors", res)
+	}
+	if _, err := os.Stat(e.vectorsPath()); err != nil {
+		t.Errorf("vectors not saved after rebuild: %v", err)
+	}
+}
+
+func TestSearchPage_Paginates(t *testing.T) {
+	e := newTestEngine(t)
+	all, total, err := e.SearchPage("*", 10, 0)
+	if err != nil || total != 4 || len(all) != 4 {
+		t.Fatalf("match all = %d of %d, %v", len(all), total, err)
+	}
+	page, total, _ := e.SearchPage("generate write draft", 1, 1)
+	if total < 2 || len(page) != 1 {
+		t.Errorf("page 2 = %+v of %d", page, total)
+	}
+}
+
+// newStore writes one SKILL.md per description into a store directory.
+func newStore(t *testing.T, skills map[string]string) string {
+	t.Helper()
+	dir := filepath.Join(t.TempDir(), "store")
+	for name, desc := range skills {
+		os.MkdirAll(filepath.Join(dir, name), 0o755)
+		body := "---\nname: " + name + "\ndescription: " + desc + "\n---\nbody\n"
+		if err := os.WriteFile(filepath.Join(dir, name, "SKILL.md"), []byte(body), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func TestDeleteSkill(t *testing.T) {
+	e := newTestEngine(t)
+	if err := e.DeleteSkill("pdf"); err != nil {
+		t.Fatal(err)
+	}
+	if err := e.DeleteSkill("never-indexed"); err != nil {
+		t.Errorf("deleting an unknown skill: %v", err)
+	}
+	res, _ := e.Search("pdf documents", 10)
+	for _, r := range res {
+		if r.Name == "pdf" {
+			t.Fatalf("deleted skill still found: %+v", r)
+		}
+	}
+	names, _ := e.Names()
+	if strings.Join(names, ",") != "commit-writer,frontend-design,rest-suite-gen" {
+		t.Errorf("names = %v", names)
+	}
+}
+
+func TestOpen_RebuildsFromStore(t *testing.T) {
+	storeDir := newStore(t, map[string]string{"pdf": "Extract text from PDF documents", "lint": "Run linters"})
+	indexDir := filepath.Join(t.TempDir(), "index")
+	e := NewStoreEngine(indexDir, storeDir)
+
+	// A fresh index is filled from the store.
+	if names, err := e.Names(); err != nil || strings.Join(names, ",") != "lint,pdf" {
+		t.Fatalf("fresh index = %v, %v", names, err)
+	}
+
+	// An index from another schema version is rebuilt.
+	idx, err := e.openOrCreate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	idx.SetInternal(schemaKey, []byte("0"))
+	idx.Delete("lint")
+	idx.Close()
+	if names, _ := e.Names(); strings.Join(names, ",") != "lint,pdf" {
+		t.Errorf("after schema change = %v", names)
+	}
+
+	// So is a corrupt one, next to files that are not the index's.
+	os.WriteFile(filepath.Join(indexDir, "index_meta.json"), []byte("{not json"), 0o644)
+	os.WriteFile(filepath.Join(indexDir, "notes.txt"), []byte("keep"), 0o644)
+	res, err := e.Search("pdf", 10)
+	if err != nil || len(res) == 0 || res[0].Name != "pdf" {
+		t.Fatalf("search after corruption = %+v, %v", res, err)
+	}
+	if _, err := os.Stat(filepath.Join(indexDir, "notes.txt")); err != nil {
+		t.Error("rebuild removed an unrelated file")
+	}
+}
+
+func TestReindex(t *testing.T) {
+	storeDir := newStore(t, map[string]string{"pdf": "Extract text from PDF documents"})
+	os.MkdirAll(filepath.Join(storeDir, "empty"), 0o755)
+	e := NewStoreEngine(filepath.Join(t.TempDir(), "index"), storeDir)
+	e.IndexSkill(skill.Skill{Name: "gone", Description: "Removed from the store by hand"})
+
+	indexed, skipped, err := e.Reindex()
+	if err != nil || indexed != 1 || len(skipped) != 1 || !strings.Contains(skipped[0].Error(), "empty") {
+		t.Fatalf("Reindex = %d, %v, %v", indexed, skipped, err)
+	}
+	if names, _ := e.Names(); strings.Join(names, ",") != "pdf" {
+		t.Errorf("names = %v", names)
+	}
+	if _, _, err := NewEngine(t.TempDir()).Reindex(); err == nil {
+		t.Error("reindex without a source succeeded")
+	}
+}
+
+func TestIndexSkillsByPath(t *testing.T) {
+	storeDir := newStore(t, map[string]string{
+		"pdf":    "Extract text from PDF documents",
+		"commit": "Draft commit messages from staged changes",
+	})
+	os.MkdirAll(filepath.Join(storeDir, "empty"), 0o755)
+	e := NewEngine(filepath.Join(t.TempDir(), "index"))
+	dirs := map[string]string{}
+	for _, name := range []string{"pdf", "commit", "empty"} {
+		dirs[name] = filepath.Join(storeDir, name)
+	}
+
+	skipped, err := e.IndexSkillsByPath(dirs)
+	if err != nil || len(skipped) != 1 || skipped["empty"] == nil {
+		t.Fatalf("IndexSkillsByPath = %v, %v", skipped, err)
+	}
+	if names, _ := e.Names(); strings.Join(names, ",") != "commit,pdf" {
+		t.Errorf("names = %v", names)
+	}
+	res, err := e.Search("staged changes", 10)
+	if err != nil || len(res) == 0 || res[0].Name != "commit" || res[0].Semantic <= 0 {
+		t.Fatalf("search after batch = %+v, %v", res, err)
+	}
+}
+
+func TestSearch_FieldBoosts(t *testing.T) {
+	e := NewEngine(filepath.Join(t.TempDir(), "index"))
+	for _, sk := range []skill.Skill{
+		{Name: "notes", Description: "Keep meeting notes", Body: "Mentions docker once in passing."},
+		{Name: "compose", Description: "Write compose files", Tags: []string{"docker"}},
+		{Name: "docker", Description: "Build and run containers"},
+	} {
+		if err := e.IndexSkill(sk); err != nil {
+			t.Fatal(err)
+		}
+	}
+	res, err := e.Search("docker", 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var order []string
+	for _, r := range res {
+		order = append(order, r.Name)
+	}
+	if strings.Join(order, ",") != "docker,compose,notes" {
+		t.Errorf("order = %v, want name, then tag, then body match", order)
+	}
+
+	// Name prefixes match whole words of the name.
+	res, _ = e.Search("dock", 10)
+	if len(res) == 0 || res[0].Name != "docker" {
+		t.Errorf("prefix results = %+v", res)
+	}
+}
+
+func TestSearch_FuzzyFallback(t *testing.T) {
+	e := newTestEngine(t)
+	res, err := e.Search("tailwnd", 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res) != 1 || res[0].Name != "frontend-design" || !strings.Contains(res[0].Why, "close to: tailwind") {
+		t.Fatalf("results = %+v", res)
+	}
+
+	// An exact hit does not pull in near misses.
+	res, _ = e.Search("pdf", 10)
+	for _, r := range res {
+		if strings.Contains(r.Why, "close to") {
+			t.Errorf("fuzzy hit alongside exact ones: %+v", r)
+		}
+	}
+	// Query string syntax is taken literally.
+	if res, _ := e.Search("name:pfd", 10); len(res) != 0 {
+		t.Errorf("field query matched fuzzily: %+v", res)
+	}
+}
+
+func TestParseQuery(t *testing.T) {
+	q := ParseQuery(`tag:testing cli:Claude installed:project source:github -author:bob "react hooks" name:pdf tag:testing`)
+	if q.Text != `"react hooks" name:pdf` {
+		t.Errorf("text = %q", q.Text)
+	}
+	want := map[string]string{"tag": "testing", "cli": "claude", "installed": "project", "source": "github"}
+	for f, v := range want {
+		if got := q.Filters[f]; len(got) != 1 || got[0] != v {
+			t.Errorf("filter %s = %v, want %s", f, got, v)
+		}
+	}
+	if len(q.Exclude["author"]) != 1 || q.Exclude["author"][0] != "bob" {
+		t.Errorf("exclude = %v", q.Exclude)
+	}
+	if s := q.String(); s != `tag:testing cli:claude installed:project source:github -author:bob "react hooks" name:pdf` {
+		t.Errorf("String() = %s", s)
+	}
+
+	q = q.Toggle("tag", "testing").Toggle("cli", "cursor")
+	if q.Has("tag", "testing") || !q.Has("cli", "cursor") || !q.Has("cli", "claude") {
+		t.Errorf("toggled = %s", q)
+	}
+	if q = q.ToggleExclude("author", "bob"); q.Excludes("author", "bob") || !q.Filtered() {
+		t.Errorf("exclusion not removed: %s", q)
+	}
+	if q = q.ToggleExclude("source", "git"); !q.Excludes("source", "git") || !strings.Contains(q.String(), "-source:git") {
+		t.Errorf("exclusion not added: %s", q)
+	}
+	if ParseQuery(`author:"jane doe" x`).Filters["author"][0] != "jane doe" {
+		t.Error("quoted value not parsed")
+	}
+}
+
+func TestQuery_Facets(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	storeDir := newStore(t, map[string]string{
+		"api-tests": "Write unit tests for REST endpoints",
+		"ui-tests":  "Write browser tests with playwright",
+		"pdf":       "Extract text from PDF documents",
+	})
+	st := store.NewManager(storeDir)
+	st.WriteMeta("api-tests", store.Meta{SourceType: store.SourceGit, SourceURL: "https://github.com/acme/skills", Projects: []string{"/work/app"}})
+	st.WriteMeta("ui-tests", store.Meta{SourceType: store.SourceRegistry, Registry: "team", Author: "Bob"})
+	st.WriteMeta("pdf", store.Meta{SourceType: store.SourceImport, SourceURL: "/home/me/.claude/skills"})
+	for _, name := range []string{"api-tests", "pdf"} {
+		claude := filepath.Join(home, ".claude", "skills")
+		os.MkdirAll(claude, 0o755)
+		os.Symlink(filepath.Join(storeDir, name), filepath.Join(claude, name))
+	}
+
+	e := NewStoreEngine(filepath.Join(t.TempDir(), "index"), storeDir)
+	if _, _, err := e.Reindex(); err != nil {
+		t.Fatal(err)
+	}
+	names := func(query string) string {
+		t.Helper()
+		p, err := e.Query(query, 10, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var out []string
+		for _, r := range p.Results {
+			out = append(out, r.Name)
+		}
+		return strings.Join(out, ",")
+	}
+	cases := map[string]string{
+		"tag:testing":                        "api-tests,ui-tests",
+		"cli:claude":                         "api-tests,pdf",
+		"cli:claude installed:project":       "api-tests",
+		"installed:none":                     "ui-tests",
+		"source:github author:acme":          "api-tests",
+		"source:team author:bob":             "ui-tests",
+		"-cli:claude":                        "ui-tests",
+		"tag:testing source:git source:team": "api-tests,ui-tests",
+		"source:import pdf":                  "pdf",
+		"cli:cursor":                         "",
+	}
+	for q, want := range cases {
+		if got := names(q); got != want {
+			t.Errorf("%s = %q, want %q", q, got, want)
+		}
+	}
+
+	p, _ := e.Query("tag:testing", 1, 0)
+	if p.Total != 2 || len(p.Results) != 1 {
+		t.Errorf("page = %d of %d", len(p.Results), p.Total)
+	}
+	counts := map[string]int{}
+	for _, f := range p.Facets {
+		for _, term := range f.Terms {
+			counts[f.Field+":"+term.Value] = term.Count
+		}
+	}
+	if counts["tag:testing"] != 2 || counts["cli:claude"] != 1 || counts["installed:global"] != 1 || counts["source:github"] != 1 || counts["cli:cursor"] != 0 {
+		t.Errorf("facets = %v", counts)
+	}
+}
@@ -0,0 +1,165 @@
+package search
+
+import (
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/ZiaoLiu-1/pskill/internal/adapter"
+	"github.com/ZiaoLiu-1/pskill/internal/store"
+)
+
+// Facet fields a query can filter on and results are counted by, in the
+// order they are listed.
+const (
+	FacetTag       = "tag"
+	FacetCLI       = "cli"
+	FacetInstalled = "installed"
+	FacetSource    = "source"
+	FacetAuthor    = "author"
+)
+
+var facetFields = []string{FacetTag, FacetCLI, FacetInstalled, FacetSource, FacetAuthor}
+
+// Values of the installed facet.
+const (
+	ScopeGlobal  = "global"  // linked into a CLI's global skill dir
+	ScopeProject = "project" // listed in a project's pskill.yaml
+	ScopeNone    = "none"    // only in the store
+)
+
+// Facet counts the results of a query per value of one field.
+type Facet struct {
+	Field string      `json:"field"`
+	Terms []FacetTerm `json:"terms"`
+}
+
+type FacetTerm struct {
+	Value string `json:"value"`
+	Count int    `json:"count"`
+}
+
+// facetValues are the facet values of one skill, by field.
+type facetValues map[string][]string
+
+// storeFacets returns the facet values of a store skill other than its
+// tags, which come from SKILL.md.
+func storeFacets(storeDir string) func(name string) facetValues {
+	return func(name string) facetValues {
+		st := store.NewManager(storeDir)
+		fv := facetValues{}
+		adapters := adapter.All()
+		for cli, ad := range adapters {
+			if !ad.SupportsSkills() {
+				continue
+			}
+			if _, err := os.Stat(filepath.Join(ad.SkillDir(), name)); err == nil {
+				fv[FacetCLI] = append(fv[FacetCLI], cli)
+			}
+		}
+		if len(fv[FacetCLI]) > 0 {
+			fv[FacetInstalled] = append(fv[FacetInstalled], ScopeGlobal)
+		}
+		if meta, err := st.ReadMeta(name); err == nil {
+			fv[FacetSource] = append(fv[FacetSource], meta.SourceType)
+			if meta.Registry != "" {
+				fv[FacetSource] = append(fv[FacetSource], meta.Registry)
+			}
+			host, owner := repoOwner(meta.SourceURL)
+			if host != "" {
+				fv[FacetSource] = append(fv[FacetSource], host)
+			}
+			switch {
+			case meta.Author != "":
+				fv[FacetAuthor] = append(fv[FacetAuthor], meta.Author)
+			case owner != "":
+				fv[FacetAuthor] = append(fv[FacetAuthor], owner)
+			}
+			if len(meta.Projects) > 0 {
+				fv[FacetInstalled] = append(fv[FacetInstalled], ScopeProject)
+			}
+		}
+		if len(fv[FacetInstalled]) == 0 {
+			fv[FacetInstalled] = []string{ScopeNone}
+		}
+		return fv
+	}
+}
+
+// repoOwner returns the short host name ("github") and the owner of a
+// repository URL such as https://github.com/owner/repo or
+// git@github.com:owner/repo.git. Local paths return nothing.
+func repoOwner(raw string) (host, owner string) {
+	switch {
+	case strings.HasPrefix(raw, "git@"):
+		rest := strings.TrimPrefix(raw, "git@")
+		i := strings.Index(rest, ":")
+		if i < 0 {
+			return "", ""
+		}
+		host, raw = rest[:i], rest[i+1:]
+	case strings.Contains(raw, "://"):
+		u, err := url.Parse(raw)
+		if err != nil {
+			return "", ""
+		}
+		host, raw = u.Hostname(), u.Path
+	default:
+		return "", ""
+	}
+	host = strings.TrimPrefix(host, "www.")
+	if i := strings.Index(host, "."); i > 0 {
+		host = host[:i]
+	}
+	owner, _, _ = strings.Cut(strings.Trim(raw, "/"), "/")
+	return strings.ToLower(host), owner
+}
+
+// normalize lowercases, trims and dedupes facet values, sorted.
+func (fv facetValues) normalize() facetValues {
+	out := facetValues{}
+	for field, values := range fv {
+		seen := map[string]bool{}
+		for _, v := range values {
+			v = strings.ToLower(strings.TrimSpace(v))
+			if v == "" || seen[v] {
+				continue
+			}
+			seen[v] = true
+			out[field] = append(out[field], v)
+		}
+		sort.Strings(out[field])
+	}
+	return out
+}
+
+// countFacets counts, per field, how many of names have each value, most
+// common first.
+func countFacets(names []string, values map[string]facetValues) []Facet {
+	var out []Facet
+	for _, field := range facetFields {
+		counts := map[string]int{}
+		for _, name := range names {
+			for _, v := range values[name][field] {
+				counts[v]++
+			}
+		}
+		if len(counts) == 0 {
+			continue
+		}
+		f := Facet{Field: field}
+		for v, n := range counts {
+			f.Terms = append(f.Terms, FacetTerm{Value: v, Count: n})
+		}
+		sort.Slice(f.Terms, func(i, j int) bool {
+			if f.Terms[i].Count != f.Terms[j].Count {
+				return f.Terms[i].Count > f.Terms[j].Count
+			}
+			return f.Terms[i].Value < f.Terms[j].Value
+		})
+		out = append(out, f)
+	}
+	return out
+}
@@ -0,0 +1,189 @@
+package search
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/blevesearch/bleve/v2"
+	"github.com/blevesearch/bleve/v2/analysis/analyzer/custom"
+	"github.com/blevesearch/bleve/v2/analysis/lang/en"
+	"github.com/blevesearch/bleve/v2/analysis/token/edgengram"
+	"github.com/blevesearch/bleve/v2/analysis/token/lowercase"
+	"github.com/blevesearch/bleve/v2/analysis/tokenizer/single"
+	"github.com/blevesearch/bleve/v2/analysis/tokenizer/unicode"
+	"github.com/blevesearch/bleve/v2/mapping"
+	bsearch "github.com/blevesearch/bleve/v2/search"
+	"github.com/blevesearch/bleve/v2/search/query"
+)
+
+// Analyzers defined by the index mapping.
+const (
+	nameAnalyzer   = "pskill_name"   // whole name, lowercased
+	prefixAnalyzer = "pskill_prefix" // edge n-grams of each word in the name
+	wordsAnalyzer  = "pskill_words"  // lowercased words, to query prefixes
+	prefixFilter   = "pskill_edge_ngram"
+	prefixField    = "name_prefix"
+)
+
+// Query-time field boosts: a name match outranks a tag, a tag a
+// description and a description a passing mention in the body.
+var fieldBoosts = []struct {
+	field string
+	boost float64
+}{
+	{"name", 5},
+	{prefixField, 3},
+	{"tags", 2.5},
+	{"description", 1.5},
+	{"body", 1},
+}
+
+// fuzzyMinLen is the word length from which fuzzy matching allows two
+// edits instead of one.
+const fuzzyMinLen = 6
+
+func newIndexMapping() (mapping.IndexMapping, error) {
+	m := bleve.NewIndexMapping()
+	if err := m.AddCustomTokenFilter(prefixFilter, map[string]interface{}{
+		"type": edgengram.Name,
+		"min":  2.0,
+		"max":  20.0,
+	}); err != nil {
+		return nil, err
+	}
+	for name, cfg := range map[string]map[string]interface{}{
+		nameAnalyzer:   {"type": custom.Name, "tokenizer": single.Name, "token_filters": []string{lowercase.Name}},
+		prefixAnalyzer: {"type": custom.Name, "tokenizer": unicode.Name, "token_filters": []string{lowercase.Name, prefixFilter}},
+		wordsAnalyzer:  {"type": custom.Name, "tokenizer": unicode.Name, "token_filters": []string{lowercase.Name}},
+	} {
+		if err := m.AddCustomAnalyzer(name, cfg); err != nil {
+			return nil, err
+		}
+	}
+
+	text := func(analyzer string) *mapping.FieldMapping {
+		f := bleve.NewTextFieldMapping()
+		f.Analyzer = analyzer
+		return f
+	}
+	doc := bleve.NewDocumentStaticMapping()
+	prefix := text(prefixAnalyzer)
+	prefix.Name = prefixField
+	prefix.Store = false
+	prefix.IncludeInAll = false
+	doc.AddFieldMappingsAt("name", text(nameAnalyzer), prefix)
+	doc.AddFieldMappingsAt("tags", text(en.AnalyzerName))
+	doc.AddFieldMappingsAt("description", text(en.AnalyzerName))
+	doc.AddFieldMappingsAt("body", text(en.AnalyzerName))
+	for _, field := range facetFields {
+		f := bleve.NewKeywordFieldMapping()
+		f.IncludeInAll = false
+		doc.AddFieldMappingsAt(field, f)
+	}
+	m.DefaultMapping = doc
+	m.DefaultAnalyzer = en.AnalyzerName
+	return m, nil
+}
+
+// usesQuerySyntax reports whether text is written in bleve's query string
+// syntax (field:value, +must, -not, "phrases", wildcards, boosts), which is
+// passed through as written.
+func usesQuerySyntax(text string) bool {
+	if strings.ContainsAny(text, `:"+*?~^()`) {
+		return true
+	}
+	for _, w := range strings.Fields(text) {
+		if strings.HasPrefix(w, "-") {
+			return true
+		}
+	}
+	return false
+}
+
+// keywordQuery matches text against every field with its boost. With
+// fuzzy set, words may be an edit or two away from indexed terms; the
+// name prefix field is left out since n-grams match nearly anything
+// fuzzily. It returns nil for a fuzzy query in query string syntax.
+func keywordQuery(text string, fuzzy bool) query.Query {
+	if usesQuerySyntax(text) {
+		if fuzzy {
+			return nil
+		}
+		return bleve.NewQueryStringQuery(text)
+	}
+	var qs []query.Query
+	for _, fb := range fieldBoosts {
+		if !fuzzy {
+			q := bleve.NewMatchQuery(text)
+			q.SetField(fb.field)
+			q.SetBoost(fb.boost)
+			if fb.field == prefixField {
+				q.Analyzer = wordsAnalyzer
+			}
+			qs = append(qs, q)
+			continue
+		}
+		if fb.field == prefixField {
+			continue
+		}
+		for _, w := range strings.Fields(text) {
+			q := bleve.NewMatchQuery(w)
+			q.SetField(fb.field)
+			q.SetBoost(fb.boost)
+			q.SetFuzziness(1)
+			if len(w) >= fuzzyMinLen {
+				q.SetFuzziness(2)
+			}
+			qs = append(qs, q)
+		}
+	}
+	return bleve.NewDisjunctionQuery(qs...)
+}
+
+// filterQuery matches the skills that pass q's facet filters.
+func filterQuery(q Query) query.Query {
+	if !q.Filtered() {
+		return bleve.NewMatchAllQuery()
+	}
+	bq := bleve.NewBooleanQuery()
+	bq.AddMust(bleve.NewMatchAllQuery())
+	for field, values := range q.Filters {
+		var any []query.Query
+		for _, v := range values {
+			tq := bleve.NewTermQuery(v)
+			tq.SetField(field)
+			any = append(any, tq)
+		}
+		bq.AddMust(bleve.NewDisjunctionQuery(any...))
+	}
+	for field, values := range q.Exclude {
+		for _, v := range values {
+			tq := bleve.NewTermQuery(v)
+			tq.SetField(field)
+			bq.AddMustNot(tq)
+		}
+	}
+	return bq
+}
+
+// matchedTerms lists the indexed terms a fuzzy hit matched, for Why.
+func matchedTerms(locations bsearch.FieldTermLocationMap) []string {
+	seen := map[string]bool{}
+	var out []string
+	for field, terms := range locations {
+		if field == prefixField {
+			continue
+		}
+		for t := range terms {
+			if !seen[t] {
+				seen[t] = true
+				out = append(out, t)
+			}
+		}
+	}
+	sort.Strings(out)
+	if len(out) > 3 {
+		out = out[:3]
+	}
+	return out
+}
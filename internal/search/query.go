@@ -0,0 +1,153 @@
+package search
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Query is a parsed search: free text ranked by keyword and meaning, and
+// facet filters. A skill must have one of the values given for every
+// filtered field and none of the excluded ones.
+type Query struct {
+	Text    string
+	Filters map[string][]string
+	Exclude map[string][]string
+}
+
+// ParseQuery splits `tag:testing cli:claude -source:git "react hooks"`
+// into facet filters and free text. Terms on other fields, such as
+// name:pdf, stay in the text.
+func ParseQuery(s string) Query {
+	q := Query{Filters: map[string][]string{}, Exclude: map[string][]string{}}
+	var text []string
+	for _, tok := range splitQuery(s) {
+		neg := strings.HasPrefix(tok, "-")
+		field, value, ok := strings.Cut(strings.TrimPrefix(tok, "-"), ":")
+		field = strings.ToLower(field)
+		if !ok || !isFacet(field) {
+			text = append(text, tok)
+			continue
+		}
+		value = strings.ToLower(strings.Trim(value, `"`))
+		if value == "" {
+			continue
+		}
+		if neg {
+			q.Exclude[field] = appendUnique(q.Exclude[field], value)
+		} else {
+			q.Filters[field] = appendUnique(q.Filters[field], value)
+		}
+	}
+	q.Text = strings.Join(text, " ")
+	return q
+}
+
+// Filtered reports whether q has any facet filter.
+func (q Query) Filtered() bool {
+	return len(q.Filters) > 0 || len(q.Exclude) > 0
+}
+
+// Has reports whether q filters on field=value.
+func (q Query) Has(field, value string) bool {
+	return containsString(q.Filters[field], value)
+}
+
+// Excludes reports whether q excludes field=value.
+func (q Query) Excludes(field, value string) bool {
+	return containsString(q.Exclude[field], value)
+}
+
+// Toggle adds the filter field=value to q, or removes it if present.
+func (q Query) Toggle(field, value string) Query {
+	return Query{Text: q.Text, Filters: toggle(q.Filters, field, value), Exclude: q.Exclude}
+}
+
+// ToggleExclude adds the exclusion -field:value to q, or removes it if
+// present.
+func (q Query) ToggleExclude(field, value string) Query {
+	return Query{Text: q.Text, Filters: q.Filters, Exclude: toggle(q.Exclude, field, value)}
+}
+
+// toggle returns a copy of values with value added to or removed from
+// field.
+func toggle(values map[string][]string, field, value string) map[string][]string {
+	out := map[string][]string{}
+	for f, vs := range values {
+		out[f] = append([]string(nil), vs...)
+	}
+	if !containsString(out[field], value) {
+		out[field] = append(out[field], value)
+		return out
+	}
+	var kept []string
+	for _, v := range out[field] {
+		if v != value {
+			kept = append(kept, v)
+		}
+	}
+	out[field] = kept
+	if len(kept) == 0 {
+		delete(out, field)
+	}
+	return out
+}
+
+// String formats q back into the query syntax, filters first.
+func (q Query) String() string {
+	var parts []string
+	for _, field := range facetFields {
+		for _, v := range q.Filters[field] {
+			parts = append(parts, field+":"+quoteValue(v))
+		}
+		for _, v := range q.Exclude[field] {
+			parts = append(parts, "-"+field+":"+quoteValue(v))
+		}
+	}
+	if q.Text != "" {
+		parts = append(parts, q.Text)
+	}
+	return strings.Join(parts, " ")
+}
+
+// splitQuery splits s on spaces outside double quotes, keeping the quotes.
+func splitQuery(s string) []string {
+	var out []string
+	var cur strings.Builder
+	quoted := false
+	for _, r := range s {
+		switch {
+		case r == '"':
+			quoted = !quoted
+			cur.WriteRune(r)
+		case unicode.IsSpace(r) && !quoted:
+			if cur.Len() > 0 {
+				out = append(out, cur.String())
+				cur.Reset()
+			}
+		default:
+			cur.WriteRune(r)
+		}
+	}
+	if cur.Len() > 0 {
+		out = append(out, cur.String())
+	}
+	return out
+}
+
+func quoteValue(v string) string {
+	if strings.ContainsFunc(v, unicode.IsSpace) {
+		return `"` + v + `"`
+	}
+	return v
+}
+
+func isFacet(field string) bool {
+	return containsString(facetFields, field)
+}
+
+func appendUnique(items []string, s string) []string {
+	if containsString(items, s) {
+		return items
+	}
+	return append(items, s)
+}
@@ -0,0 +1,239 @@
+package search
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/ZiaoLiu-1/pskill/internal/skill"
+)
+
+// vectorsFile holds each skill's weighted term counts next to the bleve
+// index. TF-IDF weights depend on the whole corpus, so vectors are built
+// from these counts at query time.
+const vectorsFile = "semantic.json"
+
+// Field weights for term counts: a word in the name or description says
+// more about a skill than one in its body.
+const (
+	nameWeight = 3
+	metaWeight = 2
+	bodyWeight = 1
+)
+
+// conceptWeight scales a term's weight when it is added to the concepts it
+// signals, so exact words still count for more than related ones.
+const conceptWeight = 0.6
+
+// minSemantic is the similarity below which a skill with no keyword match
+// is left out.
+const minSemantic = 0.08
+
+type termDoc struct {
+	Description string            `json:"description"`
+	Terms       map[string]int    `json:"terms"` // stem → weighted count
+	Words       map[string]string `json:"words"` // stem → shortest word, for explanations
+}
+
+type vectorStore struct {
+	Docs map[string]termDoc `json:"docs"`
+}
+
+func newTermDoc(doc indexedSkill) termDoc {
+	td := termDoc{Description: doc.Description, Terms: map[string]int{}, Words: map[string]string{}}
+	add := func(text string, weight int) {
+		for _, t := range skill.Terms(text) {
+			td.Terms[t.Stem] += weight
+			if w, ok := td.Words[t.Stem]; !ok || len(t.Word) < len(w) {
+				td.Words[t.Stem] = t.Word
+			}
+		}
+	}
+	add(doc.Name, nameWeight)
+	add(doc.Description, metaWeight)
+	add(doc.Tags, metaWeight)
+	add(doc.Body, bodyWeight)
+	return td
+}
+
+func (e *Engine) vectorsPath() string { return filepath.Join(e.indexDir, vectorsFile) }
+
+func (e *Engine) loadVectors() (vectorStore, error) {
+	vs := vectorStore{Docs: map[string]termDoc{}}
+	raw, err := os.ReadFile(e.vectorsPath())
+	if errors.Is(err, os.ErrNotExist) {
+		return vs, nil
+	}
+	if err != nil {
+		return vs, err
+	}
+	if err := json.Unmarshal(raw, &vs); err != nil || vs.Docs == nil {
+		// A corrupt file is rebuilt from the bleve index.
+		return vectorStore{Docs: map[string]termDoc{}}, nil
+	}
+	return vs, nil
+}
+
+func (e *Engine) saveVectors(vs vectorStore) error {
+	raw, err := json.Marshal(vs)
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(e.indexDir, ".semantic-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(raw); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), e.vectorsPath())
+}
+
+// semanticMatch is one skill's similarity to a query and why.
+type semanticMatch struct {
+	score float64
+	why   string
+}
+
+// model holds document frequencies for TF-IDF weights.
+type model struct {
+	n  int
+	df map[string]int
+}
+
+func newModel(vs vectorStore) model {
+	m := model{n: len(vs.Docs), df: map[string]int{}}
+	for _, d := range vs.Docs {
+		for t := range d.Terms {
+			m.df[t]++
+		}
+	}
+	return m
+}
+
+func (m model) idf(term string) float64 {
+	return math.Log(1 + float64(m.n+1)/float64(m.df[term]+1))
+}
+
+// vector weights terms by TF-IDF and adds each term's weight to the
+// concepts it signals.
+func (m model) vector(terms map[string]int) map[string]float64 {
+	v := map[string]float64{}
+	for t, n := range terms {
+		w := (1 + math.Log(float64(n))) * m.idf(t)
+		v["t:"+t] += w
+		for _, c := range conceptsOf[t] {
+			v["c:"+c] += w * conceptWeight
+		}
+	}
+	return v
+}
+
+func cosine(a, b map[string]float64) float64 {
+	var dot, na, nb float64
+	for k, x := range a {
+		na += x * x
+		if y, ok := b[k]; ok {
+			dot += x * y
+		}
+	}
+	for _, y := range b {
+		nb += y * y
+	}
+	if na == 0 || nb == 0 {
+		return 0
+	}
+	return dot / math.Sqrt(na*nb)
+}
+
+// semanticSearch scores every stored skill against text.
+func semanticSearch(vs vectorStore, text string) map[string]semanticMatch {
+	query := map[string]int{}
+	words := map[string]string{}
+	for _, t := range skill.Terms(text) {
+		query[t.Stem]++
+		words[t.Stem] = t.Word
+	}
+	out := map[string]semanticMatch{}
+	if len(query) == 0 {
+		return out
+	}
+	m := newModel(vs)
+	qv := m.vector(query)
+	for name, d := range vs.Docs {
+		score := cosine(qv, m.vector(d.Terms))
+		if score <= 0 {
+			continue
+		}
+		out[name] = semanticMatch{score: score, why: explain(query, words, d)}
+	}
+	return out
+}
+
+// explain lists the query words a skill contains and, per shared concept,
+// its related words: `test, api; related: rest, endpoint (backend)`.
+func explain(query map[string]int, queryWords map[string]string, d termDoc) string {
+	var exact []string
+	queryConcepts := map[string]bool{}
+	for t := range query {
+		if _, ok := d.Terms[t]; ok {
+			exact = append(exact, queryWords[t])
+		}
+		for _, c := range conceptsOf[t] {
+			queryConcepts[c] = true
+		}
+	}
+	sort.Strings(exact)
+
+	related := map[string][]string{}
+	for t := range d.Terms {
+		if _, ok := query[t]; ok {
+			continue
+		}
+		for _, c := range conceptsOf[t] {
+			if queryConcepts[c] {
+				related[c] = append(related[c], d.Words[t])
+			}
+		}
+	}
+	concepts := make([]string, 0, len(related))
+	for c := range related {
+		concepts = append(concepts, c)
+	}
+	sort.Slice(concepts, func(i, j int) bool {
+		if len(related[concepts[i]]) != len(related[concepts[j]]) {
+			return len(related[concepts[i]]) > len(related[concepts[j]])
+		}
+		return concepts[i] < concepts[j]
+	})
+	var rel []string
+	for i, c := range concepts {
+		if i == 3 {
+			break
+		}
+		ws := related[c]
+		sort.Strings(ws)
+		if len(ws) > 3 {
+			ws = ws[:3]
+		}
+		rel = append(rel, strings.Join(ws, ", ")+" ("+c+")")
+	}
+
+	var parts []string
+	if len(exact) > 0 {
+		parts = append(parts, strings.Join(exact, ", "))
+	}
+	if len(rel) > 0 {
+		parts = append(parts, "related: "+strings.Join(rel, "; "))
+	}
+	return strings.Join(parts, "; ")
+}
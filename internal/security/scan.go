@@ -0,0 +1,258 @@
+// Package security scans skills for content an agent should not follow or
+// run unreviewed.
+package security
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/ZiaoLiu-1/pskill/internal/store"
+)
+
+// Severities. High and medium findings make a skill risky; low ones are
+// listed for information.
+const (
+	High   = "high"
+	Medium = "medium"
+	Low    = "low"
+)
+
+// Rule names used in findings.
+const (
+	RulePipeToShell     = "pipe-to-shell"
+	RuleExfiltration    = "credential-exfiltration"
+	RuleHiddenUnicode   = "hidden-unicode"
+	RulePromptInjection = "prompt-injection"
+	RuleExecutable      = "executable"
+	RuleOversizedBinary = "oversized-binary"
+	RuleUnreadable      = "unreadable"
+	RuleSignature       = "signature" // added by the installer from the trust policy
+)
+
+// ReportFile holds the last scan result inside a skill directory. Like other
+// bookkeeping files it is left out of content hashes and archives.
+const ReportFile = ".pskill-scan.json"
+
+// MaxBinarySize is the largest non-text file a skill may ship without a
+// finding.
+const MaxBinarySize = 1 << 20
+
+// maxTextScan bounds how much of a text file is read for patterns.
+const maxTextScan = 4 << 20
+
+// Finding is one suspicious item in a skill.
+type Finding struct {
+	Rule     string `json:"rule"`
+	Severity string `json:"severity"`
+	File     string `json:"file"`
+	Line     int    `json:"line,omitempty"`
+	Detail   string `json:"detail"`
+}
+
+func (f Finding) String() string {
+	loc := f.File
+	if f.Line > 0 {
+		loc = fmt.Sprintf("%s:%d", f.File, f.Line)
+	}
+	return fmt.Sprintf("%s %s %s: %s", f.Severity, f.Rule, loc, f.Detail)
+}
+
+// Report is the result of scanning a skill directory.
+type Report struct {
+	ScannedAt time.Time `json:"scannedAt"`
+	Findings  []Finding `json:"findings"`
+}
+
+// Risky reports whether any finding is high or medium severity.
+func (r Report) Risky() bool {
+	for _, f := range r.Findings {
+		if f.Severity == High || f.Severity == Medium {
+			return true
+		}
+	}
+	return false
+}
+
+// Summary counts findings by severity, such as "2 high, 1 low".
+func (r Report) Summary() string {
+	counts := map[string]int{}
+	for _, f := range r.Findings {
+		counts[f.Severity]++
+	}
+	var parts []string
+	for _, sev := range []string{High, Medium, Low} {
+		if counts[sev] > 0 {
+			parts = append(parts, fmt.Sprintf("%d %s", counts[sev], sev))
+		}
+	}
+	if len(parts) == 0 {
+		return "no findings"
+	}
+	return strings.Join(parts, ", ")
+}
+
+type pattern struct {
+	rule     string
+	severity string
+	re       *regexp.Regexp
+	detail   string
+}
+
+var patterns = []pattern{
+	{RulePipeToShell, High, regexp.MustCompile(`(?i)\b(curl|wget|fetch)\b[^|\n]*\|\s*(sudo\s+)?(ba|z|k|da)?sh\b`), "downloads a script and pipes it to a shell"},
+	{RulePipeToShell, High, regexp.MustCompile(`(?i)\b(ba|z)?sh\s+(-c\s+)?["']?\$\(\s*(curl|wget)\b|(ba|z)?sh\s+<\(\s*(curl|wget)\b`), "runs a downloaded script"},
+	{RulePipeToShell, High, regexp.MustCompile(`(?i)\b(iwr|irm|invoke-webrequest|invoke-restmethod)\b[^|\n]*\|\s*iex\b|\biex\s*\(\s*(iwr|irm|new-object\s+net\.webclient)`), "downloads a PowerShell script and runs it"},
+	{RuleExfiltration, High, regexp.MustCompile(`(?i)\b(curl|wget|nc|ncat|invoke-webrequest)\b[^\n]*(\.ssh/id_|\.aws/credentials|\.netrc|\.git-credentials|\.docker/config\.json|\.kube/config|\.config/gh/hosts)`), "sends a credential file over the network"},
+	{RuleExfiltration, High, regexp.MustCompile(`(?i)\b(cat|base64|tar|zip)\b[^|\n]*(\.ssh/id_|\.aws/credentials|\.netrc|\.git-credentials|\.kube/config)[^|\n]*\|\s*(curl|wget|nc|ncat)\b`), "pipes a credential file to the network"},
+	{RuleExfiltration, High, regexp.MustCompile(`(?i)\b(env|printenv|set)\b\s*\|\s*(curl|wget|nc|ncat)\b`), "sends the environment over the network"},
+	{RuleExfiltration, Medium, regexp.MustCompile(`(?i)\b(curl|wget|invoke-webrequest)\b[^\n]*\$\{?[A-Z0-9_]*(TOKEN|SECRET|PASSWORD|API_KEY|ACCESS_KEY)[A-Z0-9_]*`), "sends a secret variable over the network"},
+	{RulePromptInjection, Medium, regexp.MustCompile(`(?i)\b(ignore|disregard|forget)\s+(all\s+|any\s+)?(the\s+|your\s+)?(previous|prior|above|earlier|system)\s+(instructions|prompts?|rules|messages)`), "asks the agent to drop its instructions"},
+	{RulePromptInjection, Medium, regexp.MustCompile(`(?i)\b(do\s+not|don't|never)\s+(tell|inform|mention\s+(this\s+)?to|reveal\s+(this\s+)?to|show)\s+the\s+user\b|\bwithout\s+(telling|informing|asking|notifying)\s+the\s+user\b`), "asks the agent to hide actions from the user"},
+	{RulePromptInjection, Medium, regexp.MustCompile(`(?i)\b(reveal|print|output|repeat)\s+(your|the)\s+(system|hidden)\s+prompt\b|\byou\s+are\s+now\s+(in\s+)?(dan|developer\s+mode|jailbroken|unrestricted)\b`), "tries to override the agent's system prompt"},
+	{RulePromptInjection, Medium, regexp.MustCompile(`<\|im_start\|>|<\|system\|>|\[/?INST\]`), "contains chat-template control tokens"},
+}
+
+// Scan checks every file under dir except .git and pskill's bookkeeping
+// files at the top level.
+func Scan(dir string) (Report, error) {
+	rep := Report{ScannedAt: time.Now().UTC(), Findings: []Finding{}}
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if d.Name() == ".git" {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		rel, _ := filepath.Rel(dir, path)
+		if store.IsBookkeeping(rel) || d.Type()&fs.ModeSymlink != 0 {
+			return nil
+		}
+		rel = filepath.ToSlash(rel)
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		rep.Findings = append(rep.Findings, scanFile(path, rel, info)...)
+		return nil
+	})
+	return rep, err
+}
+
+func scanFile(path, rel string, info fs.FileInfo) []Finding {
+	f, err := os.Open(path)
+	if err != nil {
+		return []Finding{{Rule: RuleUnreadable, Severity: Medium, File: rel, Detail: err.Error()}}
+	}
+	defer f.Close()
+	head := make([]byte, 8192)
+	n, _ := f.Read(head)
+	head = head[:n]
+
+	var out []Finding
+	if bytes.IndexByte(head, 0) >= 0 {
+		if kind := nativeExecutable(head); kind != "" {
+			out = append(out, Finding{Rule: RuleExecutable, Severity: High, File: rel, Detail: kind + " binary"})
+		}
+		if info.Size() > MaxBinarySize {
+			out = append(out, Finding{Rule: RuleOversizedBinary, Severity: Medium, File: rel,
+				Detail: fmt.Sprintf("%d KB binary file", info.Size()>>10)})
+		}
+		return out
+	}
+	if info.Mode().Perm()&0o111 != 0 {
+		out = append(out, Finding{Rule: RuleExecutable, Severity: Low, File: rel, Detail: "executable script"})
+	}
+
+	if _, err := f.Seek(0, 0); err != nil {
+		return out
+	}
+	sc := bufio.NewScanner(io.LimitReader(f, maxTextScan))
+	sc.Buffer(make([]byte, 64<<10), 1<<20)
+	for line := 1; sc.Scan(); line++ {
+		text := sc.Text()
+		if line == 1 {
+			text = strings.TrimPrefix(text, "\uFEFF")
+		}
+		for _, p := range patterns {
+			if p.re.MatchString(text) {
+				out = append(out, Finding{Rule: p.rule, Severity: p.severity, File: rel, Line: line, Detail: p.detail})
+			}
+		}
+		if r, ok := hiddenRune(text); ok {
+			out = append(out, Finding{Rule: RuleHiddenUnicode, Severity: High, File: rel, Line: line,
+				Detail: fmt.Sprintf("invisible or bidirectional control character U+%04X", r)})
+		}
+	}
+	return out
+}
+
+// hiddenRune returns the first character in s that changes how text
+// displays without being visible: bidi controls, zero-width characters and
+// Unicode tag characters. A zero-width joiner inside an emoji sequence is
+// allowed.
+func hiddenRune(s string) (rune, bool) {
+	var prev rune
+	for _, r := range s {
+		switch {
+		case r >= 0x202A && r <= 0x202E, r >= 0x2066 && r <= 0x2069, r == 0x200E, r == 0x200F, r == 0x061C:
+			return r, true
+		case r == 0x200D && prev < 0x2000:
+			return r, true
+		case r == 0x200B, r == 0x200C, r == 0x2060, r == 0xFEFF, r == 0x180E:
+			return r, true
+		case r >= 0xE0000 && r <= 0xE007F:
+			return r, true
+		}
+		prev = r
+	}
+	return 0, false
+}
+
+// nativeExecutable names the executable format in head, if any.
+func nativeExecutable(head []byte) string {
+	switch {
+	case bytes.HasPrefix(head, []byte("\x7fELF")):
+		return "ELF"
+	case bytes.HasPrefix(head, []byte("MZ")):
+		return "Windows PE"
+	case bytes.HasPrefix(head, []byte{0xfe, 0xed, 0xfa, 0xce}), bytes.HasPrefix(head, []byte{0xfe, 0xed, 0xfa, 0xcf}),
+		bytes.HasPrefix(head, []byte{0xce, 0xfa, 0xed, 0xfe}), bytes.HasPrefix(head, []byte{0xcf, 0xfa, 0xed, 0xfe}),
+		bytes.HasPrefix(head, []byte{0xca, 0xfe, 0xba, 0xbe}):
+		return "Mach-O"
+	}
+	return ""
+}
+
+// WriteReport saves rep in dir.
+func WriteReport(dir string, rep Report) error {
+	raw, err := json.MarshalIndent(rep, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(dir, ReportFile), raw, 0o644)
+}
+
+// ReadReport loads the report saved in dir.
+func ReadReport(dir string) (Report, error) {
+	raw, err := os.ReadFile(filepath.Join(dir, ReportFile))
+	if err != nil {
+		return Report{}, err
+	}
+	var rep Report
+	if err := json.Unmarshal(raw, &rep); err != nil {
+		return Report{}, err
+	}
+	return rep, nil
+}
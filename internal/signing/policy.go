@@ -0,0 +1,116 @@
+package signing
+
+import (
+	"fmt"
+	"path"
+
+	"github.com/ZiaoLiu-1/pskill/internal/config"
+)
+
+// Policy actions.
+const (
+	Allow = "allow"
+	Warn  = "warn"
+	Deny  = "deny"
+)
+
+// Decision is what the trust policy says about one skill.
+type Decision struct {
+	Verification
+	Action string `json:"action"`
+}
+
+// Check verifies the signature in dir and applies the trust policy for a
+// skill called name from registryName (empty for git and local sources).
+func Check(cfg config.Config, dir, registryName, name string) (Decision, error) {
+	keys, err := TrustedKeys(cfg)
+	if err != nil {
+		return Decision{}, err
+	}
+	unsigned, invalid := cfg.Trust.Unsigned, cfg.Trust.Invalid
+	if r, ok := matchRule(cfg, registryName, name); ok {
+		if len(r.Keys) > 0 {
+			keys = onlyKeys(keys, r.Keys)
+		}
+		if r.Unsigned != "" {
+			unsigned = r.Unsigned
+		}
+		if r.Invalid != "" {
+			invalid = r.Invalid
+		}
+	}
+	if unsigned == "" {
+		unsigned = Allow
+	}
+	if invalid == "" {
+		invalid = Warn
+	}
+	for _, a := range []string{unsigned, invalid} {
+		if a != Allow && a != Warn && a != Deny {
+			return Decision{}, fmt.Errorf("trust policy action %q: want allow, warn or deny", a)
+		}
+	}
+
+	d := Decision{Verification: Verify(dir, keys), Action: Allow}
+	switch d.Status {
+	case Unsigned, Untrusted:
+		d.Action = unsigned
+	case Invalid:
+		d.Action = invalid
+	}
+	return d, nil
+}
+
+// matchRule returns the first rule for registryName or name. A scope also
+// matches skills from a registry configured with that scope, since stored
+// skills keep only their bare name.
+func matchRule(cfg config.Config, registryName, name string) (config.TrustRule, bool) {
+	for _, r := range cfg.Trust.Rules {
+		if r.Registry != "" && r.Registry != registryName {
+			continue
+		}
+		if r.Scope != "" && !scopeMatches(cfg, r.Scope, registryName, name) {
+			continue
+		}
+		if r.Registry == "" && r.Scope == "" {
+			continue
+		}
+		return r, true
+	}
+	return config.TrustRule{}, false
+}
+
+func scopeMatches(cfg config.Config, scope, registryName, name string) bool {
+	if ok, _ := path.Match(scope, name); ok {
+		return true
+	}
+	if registryName == "" {
+		return false
+	}
+	for _, rc := range cfg.Registries {
+		if rc.Name == "" {
+			rc.Name = rc.Type
+		}
+		if rc.Name != registryName {
+			continue
+		}
+		for _, s := range rc.Scope {
+			if s == scope {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func onlyKeys(keys []PublicKey, names []string) []PublicKey {
+	var out []PublicKey
+	for _, k := range keys {
+		for _, n := range names {
+			if k.Name == n {
+				out = append(out, k)
+			}
+		}
+	}
+	return out
+}
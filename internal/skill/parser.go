@@ -34,13 +34,20 @@ func Parse(raw []byte, path string, sourceCLI string) (Skill, error) {
 	if name == "" {
 		name = fallbackName(path)
 	}
+	tags := normalizeTags(fm.Tags)
+	if len(tags) == 0 {
+		tags = inferTags(name, fm.Description, body)
+	}
 	return Skill{
 		Name:        name,
 		Description: fm.Description,
+		Version:     fm.Version,
+		Author:      fm.Author,
 		Body:        strings.TrimSpace(body),
 		Path:        path,
 		SourceCLI:   sourceCLI,
-		Tags:        inferTags(name, fm.Description, body),
+		Tags:        tags,
+		Vars:        fm.Vars,
 	}, nil
 }
 
@@ -52,23 +59,3 @@ func fallbackName(path string) string {
 	}
 	return base
 }
-
-func inferTags(values ...string) []string {
-	set := map[string]struct{}{}
-	for _, v := range values {
-		low := strings.ToLower(v)
-		for _, tok := range strings.FieldsFunc(low, func(r rune) bool {
-			return !(r >= 'a' && r <= 'z' || r >= '0' && r <= '9' || r == '-')
-		}) {
-			if len(tok) < 4 {
-				continue
-			}
-			set[tok] = struct{}{}
-		}
-	}
-	tags := make([]string, 0, len(set))
-	for k := range set {
-		tags = append(tags, k)
-	}
-	return tags
-}
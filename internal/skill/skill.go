@@ -1,19 +1,58 @@
 package skill
 
+import (
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
 type Skill struct {
-	Name        string   `json:"name" yaml:"name"`
-	Description string   `json:"description" yaml:"description"`
-	Body        string   `json:"body" yaml:"body"`
-	Path        string   `json:"path" yaml:"path"`
-	SourceCLI   string   `json:"sourceCli" yaml:"sourceCli"`
-	Tags        []string `json:"tags" yaml:"tags"`
-	InstalledIn []string `json:"installedIn" yaml:"installedIn"`
-	UsageCount  int64    `json:"usageCount" yaml:"usageCount"`
-	LastUsedAt  string   `json:"lastUsedAt" yaml:"lastUsedAt"`
+	Name        string            `json:"name" yaml:"name"`
+	Description string            `json:"description" yaml:"description"`
+	Version     string            `json:"version,omitempty" yaml:"version,omitempty"`
+	Author      string            `json:"author,omitempty" yaml:"author,omitempty"`
+	Body        string            `json:"body" yaml:"body"`
+	Path        string            `json:"path" yaml:"path"`
+	SourceCLI   string            `json:"sourceCli" yaml:"sourceCli"`
+	Tags        []string          `json:"tags" yaml:"tags"`
+	InstalledIn []string          `json:"installedIn" yaml:"installedIn"`
+	UsageCount  int64             `json:"usageCount" yaml:"usageCount"`
+	LastUsedAt  string            `json:"lastUsedAt" yaml:"lastUsedAt"`
+	Vars        map[string]string `json:"vars,omitempty" yaml:"vars,omitempty"`
 }
 
 type Frontmatter struct {
-	Name        string `yaml:"name"`
-	Description string `yaml:"description"`
-	License     string `yaml:"license,omitempty"`
+	Name        string            `yaml:"name"`
+	Description string            `yaml:"description"`
+	Version     string            `yaml:"version,omitempty"`
+	Author      string            `yaml:"author,omitempty"`
+	License     string            `yaml:"license,omitempty"`
+	Tags        StringList        `yaml:"tags,omitempty"`
+	Vars        map[string]string `yaml:"vars,omitempty"`
+}
+
+// StringList accepts either a YAML sequence or a comma-separated string.
+type StringList []string
+
+func (l *StringList) UnmarshalYAML(value *yaml.Node) error {
+	if value.Kind == yaml.ScalarNode {
+		var raw string
+		if err := value.Decode(&raw); err != nil {
+			return err
+		}
+		out := StringList{}
+		for _, part := range strings.Split(raw, ",") {
+			if p := strings.TrimSpace(part); p != "" {
+				out = append(out, p)
+			}
+		}
+		*l = out
+		return nil
+	}
+	var items []string
+	if err := value.Decode(&items); err != nil {
+		return err
+	}
+	*l = items
+	return nil
 }
@@ -0,0 +1,234 @@
+package skill
+
+import (
+	"sort"
+	"strings"
+)
+
+// MaxTags caps how many tags are inferred for a single skill.
+const MaxTags = 8
+
+// maxCategories caps how many taxonomy categories one skill can claim.
+const maxCategories = 3
+
+// Taxonomy maps each curated category to the keywords that signal it.
+// Keywords are compared by stem, so plain words are enough here.
+var Taxonomy = map[string][]string{
+	"frontend": {"frontend", "react", "vue", "svelte", "angular", "css", "tailwind", "html", "component", "ui", "ux", "browser", "nextjs"},
+	"backend":  {"backend", "api", "graphql", "server", "endpoint", "grpc", "microservice"},
+	"testing":  {"test", "unit", "jest", "pytest", "vitest", "coverage", "tdd", "e2e", "playwright", "cypress"},
+	"docs":     {"docs", "documentation", "readme", "changelog", "tutorial", "guide", "markdown"},
+	"devops":   {"devops", "docker", "kubernetes", "k8s", "deploy", "ci", "pipeline", "terraform", "helm", "infrastructure"},
+	"database": {"database", "sql", "postgres", "mysql", "sqlite", "mongodb", "redis", "schema", "migration", "query"},
+	"security": {"security", "vulnerability", "auth", "oauth", "secret", "encryption", "owasp", "audit"},
+	"design":   {"design", "figma", "typography", "layout", "brand", "color", "palette"},
+	"git":      {"git", "github", "commit", "branch", "merge", "rebase"},
+	"data":     {"data", "analytics", "pandas", "csv", "etl", "visualization", "chart", "notebook"},
+	"ai":       {"llm", "prompt", "agent", "embedding", "openai", "anthropic", "claude", "rag"},
+	"writing":  {"copywriting", "blog", "essay", "resume", "email", "grammar", "prose"},
+	"mobile":   {"mobile", "ios", "android", "swift", "kotlin", "flutter"},
+	"review":   {"review", "refactor", "lint", "cleanup"},
+}
+
+var stopwords = map[string]struct{}{}
+
+func init() {
+	for _, w := range strings.Fields(`a about above after again against all also an and any are as at be because been
+		before being below between both but by can cannot could did do does doing down during each few for from further
+		had has have having he her here hers him his how i if in into is it its itself just me more most my no nor not
+		now of off on once only or other our ours out over own same she should so some such than that the their theirs
+		them then there these they this those through to too under until up very was we were what when where which while
+		who whom why will with would you your yours use used using uses make makes made create creates creating get gets
+		need needs want wants like via within without across every any many much new help helps helping skill
+		skills when whenever user users ask asks asked task tasks work works working include includes including provide
+		provides provided based file files thing things way ways also etc example examples may might must shall well`) {
+		stopwords[w] = struct{}{}
+	}
+}
+
+// Categories returns the taxonomy categories present in tags, in tag order.
+func Categories(tags []string) []string {
+	out := []string{}
+	for _, t := range tags {
+		if _, ok := Taxonomy[t]; ok {
+			out = append(out, t)
+		}
+	}
+	return out
+}
+
+// PrimaryCategory returns the first taxonomy category among tags, or "other".
+func PrimaryCategory(tags []string) string {
+	if cats := Categories(tags); len(cats) > 0 {
+		return cats[0]
+	}
+	return "other"
+}
+
+func normalizeTags(tags []string) []string {
+	seen := map[string]bool{}
+	out := make([]string, 0, len(tags))
+	for _, t := range tags {
+		t = strings.ToLower(strings.TrimSpace(t))
+		if t == "" || seen[t] {
+			continue
+		}
+		seen[t] = true
+		out = append(out, t)
+	}
+	return out
+}
+
+// inferTags derives tags from free text: taxonomy categories first, then the
+// most frequent remaining keywords. Stopwords and short tokens are dropped and
+// keywords are compared by stem so "tests" and "testing" count as one.
+func inferTags(values ...string) []string {
+	type kw struct {
+		surface string
+		count   int
+		first   int
+	}
+	keywords := map[string]*kw{}
+	catScore := map[string]int{}
+	stemToCat := map[string][]string{}
+	for cat, words := range Taxonomy {
+		for _, w := range words {
+			stemToCat[stem(w)] = append(stemToCat[stem(w)], cat)
+		}
+	}
+
+	pos := 0
+	for _, v := range values {
+		for _, tok := range tokenize(v) {
+			pos++
+			if _, stop := stopwords[tok]; stop {
+				continue
+			}
+			st := stem(tok)
+			for _, cat := range stemToCat[st] {
+				catScore[cat]++
+			}
+			if len(tok) < 4 || isNumeric(tok) {
+				continue
+			}
+			if k, ok := keywords[st]; ok {
+				k.count++
+				if len(tok) < len(k.surface) {
+					k.surface = tok
+				}
+				continue
+			}
+			keywords[st] = &kw{surface: tok, count: 1, first: pos}
+		}
+	}
+
+	cats := make([]string, 0, len(catScore))
+	for c := range catScore {
+		cats = append(cats, c)
+	}
+	sort.Slice(cats, func(i, j int) bool {
+		if catScore[cats[i]] != catScore[cats[j]] {
+			return catScore[cats[i]] > catScore[cats[j]]
+		}
+		return cats[i] < cats[j]
+	})
+
+	ranked := make([]*kw, 0, len(keywords))
+	for _, k := range keywords {
+		ranked = append(ranked, k)
+	}
+	sort.Slice(ranked, func(i, j int) bool {
+		if ranked[i].count != ranked[j].count {
+			return ranked[i].count > ranked[j].count
+		}
+		return ranked[i].first < ranked[j].first
+	})
+
+	tags := make([]string, 0, MaxTags)
+	seen := map[string]bool{}
+	for _, c := range cats {
+		if len(tags) >= maxCategories {
+			break
+		}
+		tags = append(tags, c)
+		seen[c] = true
+	}
+	for _, k := range ranked {
+		if len(tags) >= MaxTags {
+			break
+		}
+		if seen[k.surface] {
+			continue
+		}
+		seen[k.surface] = true
+		tags = append(tags, k.surface)
+	}
+	return tags
+}
+
+// Term is a word stem found in free text, with the shortest word it was
+// taken from for display.
+type Term struct {
+	Stem string
+	Word string
+}
+
+// Terms splits text into stems the way tags are inferred, dropping
+// stopwords and numbers, so "tests" and "testing" compare equal.
+func Terms(text string) []Term {
+	var out []Term
+	for _, tok := range tokenize(text) {
+		if _, stop := stopwords[tok]; stop || isNumeric(tok) {
+			continue
+		}
+		out = append(out, Term{Stem: stem(tok), Word: tok})
+	}
+	return out
+}
+
+func tokenize(s string) []string {
+	return strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !(r >= 'a' && r <= 'z' || r >= '0' && r <= '9')
+	})
+}
+
+func isNumeric(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// stem is a light suffix stripper in the spirit of Porter step 1. It only
+// needs to be consistent, not linguistically exact. A final y becomes i
+// after stripping, so that deploy, deploying and deployed, or query and
+// queries, share a stem.
+func stem(w string) string {
+	if len(w) <= 3 {
+		return w
+	}
+	w = stripSuffix(w)
+	if strings.HasSuffix(w, "y") && len(w) >= 3 {
+		w = w[:len(w)-1] + "i"
+	}
+	return w
+}
+
+func stripSuffix(w string) string {
+	for _, suf := range []struct{ from, to string }{
+		{"ational", "ate"}, {"ization", "ize"}, {"fulness", "ful"}, {"ousness", "ous"},
+		{"iveness", "ive"}, {"ements", ""}, {"ement", ""}, {"ments", ""}, {"ment", ""},
+		{"ities", ""}, {"ity", ""}, {"ings", ""}, {"ing", ""}, {"ies", "i"}, {"ied", "i"},
+		{"ers", ""}, {"er", ""}, {"ed", ""}, {"ly", ""}, {"es", ""}, {"s", ""}, {"e", ""},
+	} {
+		if strings.HasSuffix(w, suf.from) && len(w)-len(suf.from) >= 2 {
+			if suf.from == "s" && strings.HasSuffix(w, "ss") {
+				return w
+			}
+			return w[:len(w)-len(suf.from)] + suf.to
+		}
+	}
+	return w
+}
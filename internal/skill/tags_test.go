@@ -0,0 +1,92 @@
+package skill
+
+import (
+	"strings"
+	"testing"
+)
+
+func contains(items []string, want string) bool {
+	for _, it := range items {
+		if it == want {
+			return true
+		}
+	}
+	return false
+}
+
+func TestInferTags_DropsStopwords(t *testing.T) {
+	tags := inferTags("helper", "Use this skill when you should create these things", "")
+	for _, bad := range []string{"create", "these", "should", "skill"} {
+		if contains(tags, bad) {
+			t.Errorf("stopword %q leaked into tags %v", bad, tags)
+		}
+	}
+}
+
+func TestInferTags_Taxonomy(t *testing.T) {
+	tags := inferTags("jest-runner", "Write unit tests and raise coverage", "Run jest with coverage reports.")
+	if len(tags) == 0 || tags[0] != "testing" {
+		t.Fatalf("expected 'testing' category first, got %v", tags)
+	}
+	if PrimaryCategory(tags) != "testing" {
+		t.Errorf("PrimaryCategory = %q, want testing", PrimaryCategory(tags))
+	}
+}
+
+func TestInferTags_StemsDuplicates(t *testing.T) {
+	tags := inferTags("", "deploying deploys deployed", "")
+	n := 0
+	for _, tag := range tags {
+		if strings.HasPrefix(tag, "deploy") {
+			n++
+		}
+	}
+	if n != 1 {
+		t.Errorf("expected one deploy keyword, got %v", tags)
+	}
+}
+
+func TestStem_SharedForms(t *testing.T) {
+	for _, group := range [][]string{
+		{"deploy", "deploying", "deployed", "deploys"},
+		{"query", "queries"},
+		{"test", "tests", "testing"},
+	} {
+		want := stem(group[0])
+		for _, w := range group[1:] {
+			if got := stem(w); got != want {
+				t.Errorf("stem(%q) = %q, want %q like stem(%q)", w, got, want, group[0])
+			}
+		}
+	}
+}
+
+func TestInferTags_Cap(t *testing.T) {
+	body := "alpha bravo charlie delta echo foxtrot golf hotel india juliet kilo lima mike november oscar"
+	tags := inferTags("", "", body)
+	if len(tags) > MaxTags {
+		t.Errorf("expected at most %d tags, got %d: %v", MaxTags, len(tags), tags)
+	}
+}
+
+func TestParse_ExplicitTagsWin(t *testing.T) {
+	raw := []byte("---\nname: x\ndescription: React components\ntags: [Docs, docs, release]\n---\n\nBody\n")
+	sk, err := Parse(raw, "/tmp/x/SKILL.md", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(sk.Tags) != 2 || sk.Tags[0] != "docs" || sk.Tags[1] != "release" {
+		t.Errorf("expected frontmatter tags [docs release], got %v", sk.Tags)
+	}
+}
+
+func TestParse_CommaSeparatedTags(t *testing.T) {
+	raw := []byte("---\nname: x\ntags: testing, ci\n---\n\nBody\n")
+	sk, err := Parse(raw, "/tmp/x/SKILL.md", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(sk.Tags) != 2 || sk.Tags[1] != "ci" {
+		t.Errorf("expected [testing ci], got %v", sk.Tags)
+	}
+}
@@ -0,0 +1,49 @@
+package skill
+
+import (
+	"fmt"
+	"regexp"
+)
+
+// placeholder matches a {{ .Vars.x }} action. Nothing else between braces
+// is expanded, so ${{ secrets.X }}, Helm and Jinja samples pass through.
+var placeholder = regexp.MustCompile(`\{\{\s*\.Vars\.([A-Za-z_][A-Za-z0-9_]*)\s*\}\}`)
+
+// IsTemplate reports whether a SKILL.md declares vars or references them.
+func IsTemplate(raw []byte) bool {
+	sk, err := Parse(raw, "", "")
+	if err == nil && len(sk.Vars) > 0 {
+		return true
+	}
+	return placeholder.Match(raw)
+}
+
+// Render expands {{ .Vars.x }} placeholders in raw, leaving any other text
+// in braces as is. Frontmatter defaults are applied first, then overrides.
+// A placeholder with no value is an error.
+func Render(raw []byte, overrides map[string]string) ([]byte, error) {
+	sk, err := Parse(raw, "", "")
+	if err != nil {
+		return nil, err
+	}
+	vars := map[string]string{}
+	for k, v := range sk.Vars {
+		vars[k] = v
+	}
+	for k, v := range overrides {
+		vars[k] = v
+	}
+	var missing string
+	out := placeholder.ReplaceAllFunc(raw, func(m []byte) []byte {
+		key := string(placeholder.FindSubmatch(m)[1])
+		v, ok := vars[key]
+		if !ok && missing == "" {
+			missing = key
+		}
+		return []byte(v)
+	})
+	if missing != "" {
+		return nil, fmt.Errorf("render template: missing var %q", missing)
+	}
+	return out, nil
+}
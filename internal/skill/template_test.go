@@ -0,0 +1,52 @@
+package skill
+
+import (
+	"strings"
+	"testing"
+)
+
+const templated = "---\nname: tester\ndescription: Run tests\nvars:\n  testCommand: make test\n  pkg: ui\n---\n\nRun `{{ .Vars.testCommand }}` before importing {{ .Vars.pkg }}.\n"
+
+func TestIsTemplate(t *testing.T) {
+	if !IsTemplate([]byte(templated)) {
+		t.Error("expected templated skill to be detected")
+	}
+	if IsTemplate([]byte("---\nname: plain\n---\n\nNo vars.\n")) {
+		t.Error("expected plain skill not to be a template")
+	}
+}
+
+func TestRender_DefaultsAndOverrides(t *testing.T) {
+	out, err := Render([]byte(templated), map[string]string{"testCommand": "go test ./..."})
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := string(out)
+	if !strings.Contains(s, "Run `go test ./...`") {
+		t.Errorf("override not applied:\n%s", s)
+	}
+	if !strings.Contains(s, "importing ui.") {
+		t.Errorf("default not applied:\n%s", s)
+	}
+}
+
+func TestRender_MissingVar(t *testing.T) {
+	_, err := Render([]byte("Use {{ .Vars.nope }}\n"), nil)
+	if err == nil || !strings.Contains(err.Error(), "nope") {
+		t.Errorf("expected missing var error naming 'nope', got %v", err)
+	}
+}
+
+func TestRender_LeavesOtherBracesAlone(t *testing.T) {
+	raw := "---\nname: ci\nvars:\n  job: test\n---\n\nRun {{ .Vars.job }} with `token: ${{ secrets.TOKEN }}` and {{ if .x }}{{ end }}.\n"
+	out, err := Render([]byte(raw), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "Run test with `token: ${{ secrets.TOKEN }}` and {{ if .x }}{{ end }}.\n"; !strings.HasSuffix(string(out), want) {
+		t.Errorf("rendered:\n%s", out)
+	}
+	if IsTemplate([]byte("---\nname: ci\n---\n\nUse ${{ secrets.TOKEN }}.\n")) {
+		t.Error("a GitHub Actions expression made the skill a template")
+	}
+}
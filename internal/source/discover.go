@@ -0,0 +1,76 @@
+package source
+
+import (
+	"io/fs"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/ZiaoLiu-1/pskill/internal/skill"
+)
+
+// Found is a skill discovered inside a checkout.
+type Found struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Dir         string `json:"-"`
+	Subdir      string `json:"subdir"` // path relative to the repo root
+}
+
+// FindSkills walks root and returns every directory holding a SKILL.md,
+// named after the directory. Hidden directories and node_modules are skipped.
+func FindSkills(root string) ([]Found, error) {
+	var out []Found
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if d.IsDir() {
+			name := d.Name()
+			if path != root && (strings.HasPrefix(name, ".") || name == "node_modules" || name == "vendor") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if d.Name() != "SKILL.md" {
+			return nil
+		}
+		dir := filepath.Dir(path)
+		rel, _ := filepath.Rel(root, dir)
+		f := Found{Name: filepath.Base(dir), Dir: dir, Subdir: filepath.ToSlash(rel)}
+		if rel == "." {
+			f.Name = filepath.Base(root)
+			f.Subdir = ""
+		}
+		if sk, err := skill.ParseFile(path, ""); err == nil {
+			f.Description = sk.Description
+		}
+		out = append(out, f)
+		return nil
+	})
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	return out, err
+}
+
+// Pick returns the found skills whose names are listed, and the names that
+// matched nothing.
+func Pick(found []Found, names []string) ([]Found, []string) {
+	byName := map[string]Found{}
+	for _, f := range found {
+		byName[f.Name] = f
+	}
+	var picked []Found
+	var missing []string
+	for _, n := range names {
+		n = strings.TrimSpace(n)
+		if n == "" {
+			continue
+		}
+		if f, ok := byName[n]; ok {
+			picked = append(picked, f)
+		} else {
+			missing = append(missing, n)
+		}
+	}
+	return picked, missing
+}
@@ -0,0 +1,183 @@
+package source
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Git fetches repositories with the system git binary into a local cache,
+// one checkout per (url, ref) pair.
+type Git struct {
+	cacheDir string
+	bin      string
+	offline  bool
+	maxAge   time.Duration // serve checkouts fetched this recently as is
+	config   []string      // extra -c key=value settings for every command
+}
+
+func NewGit(cacheDir string) *Git {
+	return &Git{cacheDir: cacheDir, bin: "git"}
+}
+
+// SetOffline makes Fetch serve existing checkouts without contacting the
+// remote.
+func (g *Git) SetOffline(offline bool) *Git {
+	g.offline = offline
+	return g
+}
+
+// SetMaxAge makes Fetch serve an existing checkout without contacting the
+// remote when it was fetched less than d ago.
+func (g *Git) SetMaxAge(d time.Duration) *Git {
+	g.maxAge = d
+	return g
+}
+
+// SetConfig passes key=value settings to every git command, as with git -c.
+func (g *Git) SetConfig(kv ...string) *Git {
+	g.config = append(g.config, kv...)
+	return g
+}
+
+// IsRepoURL reports whether arg looks like a git repository rather than a
+// registry skill name.
+func IsRepoURL(arg string) bool {
+	switch {
+	case strings.HasPrefix(arg, "github:"), strings.HasPrefix(arg, "git+"):
+		return true
+	case strings.Contains(arg, "://"):
+		return true
+	case strings.HasPrefix(arg, "git@"):
+		return true
+	case strings.HasSuffix(arg, ".git"):
+		return true
+	}
+	return false
+}
+
+// Fetch shallowly checks out repoURL at ref and returns the checkout
+// directory. ref may be a branch, a tag or a commit id; empty means the
+// remote's default branch. An existing checkout is refreshed unless g is
+// offline or it is younger than the max age. Cancelling ctx kills a git
+// command that is still running.
+func (g *Git) Fetch(ctx context.Context, repoURL, ref string) (string, error) {
+	// The checkout is named after the repo so a single-skill repo (SKILL.md
+	// at the root) gets a sensible skill name.
+	dir := filepath.Join(g.cacheDir, "git", checkoutKey(repoURL, ref), RepoName(repoURL))
+	_, statErr := os.Stat(filepath.Join(dir, ".git"))
+	if g.offline {
+		if statErr != nil {
+			return "", fmt.Errorf("offline: %s has not been fetched before", repoURL)
+		}
+		return dir, nil
+	}
+	if statErr == nil {
+		if fi, err := os.Stat(filepath.Join(dir, ".git", "FETCH_HEAD")); err == nil && time.Since(fi.ModTime()) < g.maxAge {
+			return dir, nil
+		}
+		err := g.checkout(ctx, dir, ref)
+		if err == nil {
+			return dir, nil
+		}
+		if ctx.Err() != nil {
+			return "", err
+		}
+		_ = os.RemoveAll(dir)
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", err
+	}
+	if _, err := g.run(ctx, dir, "init", "--quiet"); err != nil {
+		_ = os.RemoveAll(dir)
+		return "", err
+	}
+	if _, err := g.run(ctx, dir, "remote", "add", "origin", repoURL); err != nil {
+		_ = os.RemoveAll(dir)
+		return "", err
+	}
+	if err := g.checkout(ctx, dir, ref); err != nil {
+		_ = os.RemoveAll(dir)
+		return "", err
+	}
+	return dir, nil
+}
+
+// Head returns the commit id checked out in dir.
+func (g *Git) Head(dir string) (string, error) {
+	return g.run(context.Background(), dir, "rev-parse", "HEAD")
+}
+
+// checkout fetches a single commit for ref and detaches onto it. Fetching
+// by name works for branches, tags and full commit ids alike.
+func (g *Git) checkout(ctx context.Context, dir, ref string) error {
+	target := ref
+	if target == "" {
+		target = "HEAD"
+	}
+	if _, err := g.run(ctx, dir, "fetch", "--quiet", "--depth", "1", "origin", target); err != nil {
+		return err
+	}
+	_, err := g.run(ctx, dir, "checkout", "--quiet", "--force", "--detach", "FETCH_HEAD")
+	return err
+}
+
+func (g *Git) run(ctx context.Context, dir string, args ...string) (string, error) {
+	var full []string
+	for _, kv := range g.config {
+		full = append(full, "-c", kv)
+	}
+	cmd := exec.CommandContext(ctx, g.bin, append(full, args...)...)
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0")
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if ctx.Err() != nil {
+			return "", fmt.Errorf("git %s: %w", args[0], ctx.Err())
+		}
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			return "", fmt.Errorf("git %s: %s", args[0], strings.TrimSpace(stderr.String()))
+		}
+		return "", fmt.Errorf("git %s: %w", args[0], err)
+	}
+	return strings.TrimSpace(stdout.String()), nil
+}
+
+// RepoName returns the last path segment of a repository URL without ".git".
+func RepoName(repoURL string) string {
+	u := strings.TrimRight(repoURL, "/")
+	if i := strings.LastIndexAny(u, "/:"); i >= 0 {
+		u = u[i+1:]
+	}
+	u = strings.TrimSuffix(u, ".git")
+	if u == "" {
+		return "repo"
+	}
+	return sanitize(u)
+}
+
+func checkoutKey(repoURL, ref string) string {
+	sum := sha256.Sum256([]byte(repoURL + "@" + ref))
+	return hex.EncodeToString(sum[:8])
+}
+
+func sanitize(in string) string {
+	out := []rune(in)
+	for i, r := range out {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '_' || r == '-' || r == '.') {
+			out[i] = '_'
+		}
+	}
+	return string(out)
+}
@@ -0,0 +1,177 @@
+package source
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// newBareRepo creates a bare repository holding the given files (path →
+// content) on its default branch and returns its file:// URL.
+func newBareRepo(t *testing.T, files map[string]string) string {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	root := t.TempDir()
+	work := filepath.Join(root, "work")
+	bare := filepath.Join(root, "repo.git")
+	for p, content := range files {
+		full := filepath.Join(work, filepath.FromSlash(p))
+		if err := os.MkdirAll(filepath.Dir(full), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(full, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	gitRun(t, work, "init", "-q", "-b", "main")
+	gitRun(t, work, "add", "-A")
+	gitRun(t, work, "-c", "user.name=t", "-c", "user.email=t@example.com", "commit", "-q", "-m", "init")
+	gitRun(t, work, "tag", "v1")
+	gitRun(t, root, "clone", "-q", "--bare", work, bare)
+	return "file://" + bare
+}
+
+func gitRun(t *testing.T, dir string, args ...string) {
+	t.Helper()
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("git %v: %v\n%s", args, err, out)
+	}
+}
+
+func TestGitFetch_FindSkills(t *testing.T) {
+	url := newBareRepo(t, map[string]string{
+		"skills/alpha/SKILL.md":      "---\nname: alpha\ndescription: First\n---\n\nA\n",
+		"skills/beta/SKILL.md":       "---\nname: beta\ndescription: Second\n---\n\nB\n",
+		"skills/beta/scripts/run.sh": "echo hi\n",
+		"README.md":                  "# repo\n",
+		"node_modules/x/SKILL.md":    "ignored\n",
+		"docs/nested/gamma/SKILL.md": "# Gamma\n",
+	})
+
+	g := NewGit(t.TempDir())
+	dir, err := g.Fetch(context.Background(), url, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	found, err := FindSkills(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(found) != 3 {
+		t.Fatalf("expected 3 skills, got %+v", found)
+	}
+	if found[0].Name != "alpha" || found[0].Subdir != "skills/alpha" || found[0].Description != "First" {
+		t.Errorf("unexpected first skill: %+v", found[0])
+	}
+
+	// Second fetch reuses the checkout.
+	dir2, err := g.Fetch(context.Background(), url, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dir2 != dir {
+		t.Errorf("expected cached checkout %q, got %q", dir, dir2)
+	}
+}
+
+func TestGitFetch_Ref(t *testing.T) {
+	url := newBareRepo(t, map[string]string{"SKILL.md": "# Root skill\n"})
+	dir, err := NewGit(t.TempDir()).Fetch(context.Background(), url, "v1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	found, _ := FindSkills(dir)
+	if len(found) != 1 || found[0].Name != "repo" || found[0].Subdir != "" {
+		t.Errorf("expected root skill named after repo, got %+v", found)
+	}
+}
+
+func TestGitFetch_MaxAgeAndCancel(t *testing.T) {
+	url := newBareRepo(t, map[string]string{"SKILL.md": "# v1\n"})
+	cache := t.TempDir()
+	if _, err := NewGit(cache).Fetch(context.Background(), url, ""); err != nil {
+		t.Fatal(err)
+	}
+	// A fresh checkout is served as is, so the remote is never asked.
+	broken := NewGit(cache).SetMaxAge(time.Hour)
+	broken.bin = "git-does-not-exist"
+	if _, err := broken.Fetch(context.Background(), url, ""); err != nil {
+		t.Errorf("fresh checkout was refetched: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := NewGit(t.TempDir()).Fetch(ctx, url, ""); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected a cancelled fetch, got %v", err)
+	}
+}
+
+func TestPick(t *testing.T) {
+	found := []Found{{Name: "a"}, {Name: "b"}, {Name: "c"}}
+	picked, missing := Pick(found, []string{"a", " c", "zz"})
+	if len(picked) != 2 || picked[1].Name != "c" {
+		t.Errorf("unexpected picked: %+v", picked)
+	}
+	if len(missing) != 1 || missing[0] != "zz" {
+		t.Errorf("unexpected missing: %v", missing)
+	}
+}
+
+func TestIsRepoURL(t *testing.T) {
+	for in, want := range map[string]bool{
+		"https://github.com/a/b": true,
+		"git@github.com:a/b.git": true,
+		"file:///tmp/x.git":      true,
+		"frontend-design":        false,
+		"../local/thing":         false,
+	} {
+		if got := IsRepoURL(in); got != want {
+			t.Errorf("IsRepoURL(%q) = %v, want %v", in, got, want)
+		}
+	}
+}
+
+func TestGitFetch_CommitAndHead(t *testing.T) {
+	url := newBareRepo(t, map[string]string{"pdf/SKILL.md": "# PDF\n"})
+	g := NewGit(t.TempDir())
+	dir, err := g.Fetch(context.Background(), url, "main")
+	if err != nil {
+		t.Fatal(err)
+	}
+	head, err := g.Head(dir)
+	if err != nil || len(head) != 40 {
+		t.Fatalf("Head = %q, %v", head, err)
+	}
+
+	byCommit, err := g.Fetch(context.Background(), url, head)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := g.Head(byCommit); got != head {
+		t.Errorf("checkout by commit at %q, want %q", got, head)
+	}
+}
+
+func TestGitFetch_Offline(t *testing.T) {
+	url := newBareRepo(t, map[string]string{"SKILL.md": "# Root\n"})
+	g := NewGit(t.TempDir())
+	if _, err := g.SetOffline(true).Fetch(context.Background(), url, ""); err == nil {
+		t.Fatal("expected offline fetch of an unknown repo to fail")
+	}
+	dir, err := g.SetOffline(false).Fetch(context.Background(), url, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := g.SetOffline(true).Fetch(context.Background(), url, "")
+	if err != nil || got != dir {
+		t.Errorf("offline Fetch = %q, %v; want existing checkout %q", got, err, dir)
+	}
+}
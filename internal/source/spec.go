@@ -0,0 +1,81 @@
+package source
+
+import (
+	"fmt"
+	"path"
+	"strings"
+)
+
+// Spec identifies a skill subtree in a git repository.
+type Spec struct {
+	URL    string // clone URL
+	Subdir string // path inside the repository, "" for the whole repo
+	Ref    string // branch, tag or commit; "" for the default branch
+}
+
+// ParseSpec parses a source specifier:
+//
+//	github:user/repo[/path][@ref]
+//	git+https://host/repo.git[//subdir][@ref]
+//	git+file:///abs/repo.git[//subdir][@ref]
+//
+// Plain repository URLs are returned unchanged. A ref must not contain
+// "/"; use --ref for branch names like feature/x.
+func ParseSpec(arg string) (Spec, error) {
+	switch {
+	case strings.HasPrefix(arg, "github:"):
+		rest, ref := splitRef(strings.TrimPrefix(arg, "github:"))
+		parts := strings.Split(strings.Trim(rest, "/"), "/")
+		if len(parts) < 2 || parts[0] == "" || parts[1] == "" {
+			return Spec{}, fmt.Errorf("invalid github spec %q: want github:user/repo[/path][@ref]", arg)
+		}
+		return Spec{
+			URL:    "https://github.com/" + parts[0] + "/" + strings.TrimSuffix(parts[1], ".git") + ".git",
+			Subdir: cleanSubdir(strings.Join(parts[2:], "/")),
+			Ref:    ref,
+		}, nil
+	case strings.HasPrefix(arg, "git+"):
+		rest, ref := splitRef(strings.TrimPrefix(arg, "git+"))
+		i := strings.Index(rest, "://")
+		if i < 0 {
+			return Spec{}, fmt.Errorf("invalid git spec %q: missing scheme", arg)
+		}
+		spec := Spec{URL: rest, Ref: ref}
+		if j := strings.Index(rest[i+3:], "//"); j >= 0 {
+			cut := i + 3 + j
+			spec.URL = rest[:cut]
+			spec.Subdir = cleanSubdir(rest[cut+2:])
+		}
+		return spec, nil
+	case IsRepoURL(arg):
+		return Spec{URL: arg}, nil
+	}
+	return Spec{}, fmt.Errorf("%q is not a repository specifier", arg)
+}
+
+// String renders the spec back in git+ form.
+func (s Spec) String() string {
+	out := s.URL
+	if s.Subdir != "" {
+		out += "//" + s.Subdir
+	}
+	if s.Ref != "" {
+		out += "@" + s.Ref
+	}
+	return out
+}
+
+// splitRef splits a trailing @ref off s. An "@" before the last "/" belongs
+// to the URL (as in ssh://git@host/repo) and is left alone.
+func splitRef(s string) (string, string) {
+	at := strings.LastIndex(s, "@")
+	if at < 0 || at < strings.LastIndex(s, "/") {
+		return s, ""
+	}
+	return s[:at], s[at+1:]
+}
+
+func cleanSubdir(p string) string {
+	p = path.Clean("/" + p)
+	return strings.TrimPrefix(p, "/")
+}
@@ -0,0 +1,29 @@
+package source
+
+import "testing"
+
+func TestParseSpec(t *testing.T) {
+	for in, want := range map[string]Spec{
+		"github:acme/skills":                           {URL: "https://github.com/acme/skills.git"},
+		"github:acme/skills/tools/pdf@v1.2":            {URL: "https://github.com/acme/skills.git", Subdir: "tools/pdf", Ref: "v1.2"},
+		"git+https://example.com/a/b.git//skills/x@v2": {URL: "https://example.com/a/b.git", Subdir: "skills/x", Ref: "v2"},
+		"git+file:///tmp/repo.git":                     {URL: "file:///tmp/repo.git"},
+		"git+file:///tmp/repo.git//pdf":                {URL: "file:///tmp/repo.git", Subdir: "pdf"},
+		"git+ssh://git@host/repo.git":                  {URL: "ssh://git@host/repo.git"},
+		"https://github.com/acme/skills":               {URL: "https://github.com/acme/skills"},
+	} {
+		got, err := ParseSpec(in)
+		if err != nil {
+			t.Errorf("ParseSpec(%q): %v", in, err)
+			continue
+		}
+		if got != want {
+			t.Errorf("ParseSpec(%q) = %+v, want %+v", in, got, want)
+		}
+	}
+	for _, bad := range []string{"github:acme", "git+nope", "frontend-design"} {
+		if _, err := ParseSpec(bad); err == nil {
+			t.Errorf("ParseSpec(%q) should fail", bad)
+		}
+	}
+}
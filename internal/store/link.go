@@ -0,0 +1,72 @@
+package store
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// SourceLocal marks skills copied from a directory on disk.
+const SourceLocal = "local"
+
+// LinkDir makes the store entry for name a symlink to dir, so edits in a
+// working checkout are live in every CLI. An existing copied entry is kept
+// in the version history before it is replaced.
+func (m *Manager) LinkDir(name, dir string) error {
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return err
+	}
+	if _, err := os.Stat(filepath.Join(abs, "SKILL.md")); err != nil {
+		return fmt.Errorf("%s: no SKILL.md", abs)
+	}
+	if err := os.MkdirAll(m.storeDir, 0o755); err != nil {
+		return err
+	}
+	dst := m.SkillDir(name)
+	if fi, err := os.Lstat(dst); err == nil {
+		if fi.Mode()&os.ModeSymlink == 0 {
+			if _, err := m.SnapshotVersion(name); err != nil {
+				return err
+			}
+		}
+		if err := os.RemoveAll(dst); err != nil {
+			return err
+		}
+	}
+	return os.Symlink(abs, dst)
+}
+
+// LinkTarget returns the working directory a linked dev skill points at.
+func (m *Manager) LinkTarget(name string) (string, bool) {
+	target, err := os.Readlink(m.SkillDir(name))
+	if err != nil {
+		return "", false
+	}
+	return target, true
+}
+
+// UnlinkDir replaces a linked dev skill with a snapshot copy of its working
+// directory and records the directory as its local source.
+func (m *Manager) UnlinkDir(name string) (string, error) {
+	target, ok := m.LinkTarget(name)
+	if !ok {
+		return "", fmt.Errorf("%s is not a linked skill", name)
+	}
+	prev, _ := m.ReadMeta(name)
+	dst := m.SkillDir(name)
+	if err := os.Remove(dst); err != nil {
+		return "", err
+	}
+	if _, err := m.ImportDir(name, target); err != nil {
+		// Put the link back rather than leave the skill missing.
+		_ = os.Symlink(target, dst)
+		return "", err
+	}
+	m.removeLinkMeta(name)
+	meta := Meta{SourceType: SourceLocal, SourceURL: target, InstalledBy: prev.InstalledBy, InstalledAt: prev.InstalledAt}
+	if err := m.WriteMeta(name, m.Stamp(name, meta)); err != nil {
+		return target, err
+	}
+	return target, nil
+}
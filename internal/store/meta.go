@@ -0,0 +1,258 @@
+package store
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"hash"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/ZiaoLiu-1/pskill/internal/config"
+)
+
+// MetaFile records where a store entry came from.
+const MetaFile = ".pskill-meta.json"
+
+// SignatureFile holds a skill's signature over its ContentHash. Unlike
+// bookkeeping files it ships with the skill; it is left out of the hash
+// only at the top level.
+const SignatureFile = "SKILL.sig"
+
+// bookkeeping lists the files pskill writes next to SKILL.md: provenance,
+// the render marker and the last scan report (security.ReportFile). They are
+// left out of hashes, scans and archives, and stripped from downloads.
+var bookkeeping = map[string]bool{
+	MetaFile:            true,
+	RenderMarker:        true,
+	".pskill-scan.json": true,
+}
+
+// IsBookkeeping reports whether rel, a path relative to a skill directory,
+// is one of pskill's own files. Only the top-level names count; a .pskill-*
+// file anywhere else is part of the skill.
+func IsBookkeeping(rel string) bool {
+	return bookkeeping[filepath.ToSlash(filepath.Clean(rel))]
+}
+
+// linksDir holds the meta of linked dev skills, which cannot live inside
+// the linked working directory.
+const linksDir = ".links"
+
+// Source types recorded in Meta.
+const (
+	SourceRegistry = "registry"
+	SourceGit      = "git"
+	SourceImport   = "import" // copied from a CLI skill dir by scan or init
+	SourceLink     = "link"   // store entry is a symlink to a working dir
+)
+
+// Meta is the provenance record of a store entry.
+type Meta struct {
+	SourceType  string    `json:"sourceType"`
+	SourceURL   string    `json:"sourceUrl,omitempty"`
+	SourceCLI   string    `json:"sourceCli,omitempty"` // CLI an imported skill was found in
+	Registry    string    `json:"registry,omitempty"`  // registry name for registry sources
+	ID          string    `json:"id,omitempty"`        // registry ID
+	Version     string    `json:"version,omitempty"`   // version reported by the registry
+	Author      string    `json:"author,omitempty"`    // author reported by the registry
+	Ref         string    `json:"ref,omitempty"`
+	Subdir      string    `json:"subdir,omitempty"`
+	Commit      string    `json:"commit,omitempty"`       // resolved commit for git sources
+	Hash        string    `json:"hash,omitempty"`         // ContentHash of the entry when written
+	Approved    string    `json:"approvedHash,omitempty"` // ContentHash the user approved despite scan findings
+	Projects    []string  `json:"projects,omitempty"`     // project dirs whose pskill.yaml lists the skill
+	InstalledBy string    `json:"installedBy,omitempty"`
+	Installer   string    `json:"installerVersion,omitempty"`
+	InstalledAt time.Time `json:"installedAt"`
+	UpdatedAt   time.Time `json:"updatedAt"`
+}
+
+func (m *Manager) metaPath(name string) string {
+	if _, ok := m.LinkTarget(name); ok {
+		return filepath.Join(m.storeDir, linksDir, name+".json")
+	}
+	if _, err := os.Stat(m.SkillDir(name)); err != nil && m.IsQuarantined(name) {
+		return filepath.Join(m.QuarantineDir(name), MetaFile)
+	}
+	return filepath.Join(m.storeDir, name, MetaFile)
+}
+
+func (m *Manager) ReadMeta(name string) (Meta, error) {
+	raw, err := os.ReadFile(m.metaPath(name))
+	if err != nil {
+		return Meta{}, err
+	}
+	var meta Meta
+	if err := json.Unmarshal(raw, &meta); err != nil {
+		return Meta{}, err
+	}
+	return meta, nil
+}
+
+// WriteMeta stores meta for a skill, keeping the original InstalledAt,
+// InstalledBy, approval and projects, and stamps the pskill version.
+// UpdatedAt and Hash are kept from the previous meta unless set, so
+// bookkeeping writes neither refresh the entry nor bless hand edits; callers
+// that installed or refreshed the content pass their meta through Stamp. A
+// first write is stamped either way.
+func (m *Manager) WriteMeta(name string, meta Meta) error {
+	now := time.Now().UTC()
+	if prev, err := m.ReadMeta(name); err == nil {
+		if meta.UpdatedAt.IsZero() {
+			meta.UpdatedAt = prev.UpdatedAt
+		}
+		if meta.Hash == "" {
+			meta.Hash = prev.Hash
+		}
+		if !prev.InstalledAt.IsZero() {
+			meta.InstalledAt = prev.InstalledAt
+		}
+		if prev.InstalledBy != "" {
+			meta.InstalledBy = prev.InstalledBy
+		}
+		if meta.Approved == "" {
+			meta.Approved = prev.Approved
+		}
+		if meta.Projects == nil {
+			meta.Projects = prev.Projects
+		}
+	}
+	if meta.InstalledAt.IsZero() {
+		meta.InstalledAt = now
+	}
+	if meta.UpdatedAt.IsZero() {
+		meta = m.Stamp(name, meta)
+	}
+	meta.Installer = config.GetVersion()
+	raw, err := json.MarshalIndent(meta, "", "  ")
+	if err != nil {
+		return err
+	}
+	path := m.metaPath(name)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, raw, 0o644)
+}
+
+// Stamp marks meta as describing content just installed or refreshed in the
+// store entry for name: UpdatedAt becomes now and Hash its ContentHash.
+func (m *Manager) Stamp(name string, meta Meta) Meta {
+	meta.UpdatedAt = time.Now().UTC()
+	meta.Hash = ""
+	if h, err := ContentHash(m.SkillDir(name)); err == nil {
+		meta.Hash = h
+	}
+	return meta
+}
+
+// SetProject records whether the pskill.yaml in dir lists a skill.
+func (m *Manager) SetProject(name, dir string, listed bool) error {
+	meta, err := m.ReadMeta(name)
+	if err != nil {
+		return err
+	}
+	projects := []string{}
+	for _, p := range meta.Projects {
+		if p != dir {
+			projects = append(projects, p)
+		}
+	}
+	if listed {
+		projects = append(projects, dir)
+		sort.Strings(projects)
+	}
+	meta.Projects = projects
+	return m.WriteMeta(name, meta)
+}
+
+// Modified reports whether a store entry's content differs from the hash
+// recorded when its meta was last written. Entries without a hash are
+// reported as unmodified.
+func (m *Manager) Modified(name string) (bool, error) {
+	meta, err := m.ReadMeta(name)
+	if err != nil || meta.Hash == "" {
+		return false, err
+	}
+	h, err := ContentHash(m.SkillDir(name))
+	if err != nil {
+		return false, err
+	}
+	return h != meta.Hash, nil
+}
+
+// ContentHash hashes the files of a skill directory, their paths and
+// contents, ignoring VCS data, pskill's bookkeeping files and the
+// signature.
+func ContentHash(dir string) (string, error) {
+	root, err := filepath.EvalSymlinks(dir)
+	if err != nil {
+		return "", err
+	}
+	var files []string
+	err = filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if d.Name() == ".git" {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		rel, _ := filepath.Rel(root, path)
+		if rel == SignatureFile || IsBookkeeping(rel) {
+			return nil
+		}
+		if d.Type().IsRegular() {
+			files = append(files, path)
+		}
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+	sort.Strings(files)
+	h := sha256.New()
+	for _, path := range files {
+		rel, _ := filepath.Rel(root, path)
+		if err := hashFile(h, filepath.ToSlash(rel), path); err != nil {
+			return "", err
+		}
+	}
+	return "sha256:" + hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// SkillFileHash is the ContentHash dir would have if it held only its
+// SKILL.md, which is all ImportSkill copies into the store.
+func SkillFileHash(dir string) (string, error) {
+	h := sha256.New()
+	if err := hashFile(h, "SKILL.md", filepath.Join(dir, "SKILL.md")); err != nil {
+		return "", err
+	}
+	return "sha256:" + hex.EncodeToString(h.Sum(nil)), nil
+}
+
+func hashFile(h hash.Hash, rel, path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	_, _ = io.WriteString(h, rel+"\x00")
+	if _, err := io.Copy(h, f); err != nil {
+		return err
+	}
+	_, _ = h.Write([]byte{0})
+	return nil
+}
+
+// removeLinkMeta drops the record kept for a linked dev skill.
+func (m *Manager) removeLinkMeta(name string) {
+	_ = os.Remove(filepath.Join(m.storeDir, linksDir, name+".json"))
+}
@@ -0,0 +1,169 @@
+package store
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ZiaoLiu-1/pskill/internal/skill"
+)
+
+func TestWriteMeta_RecordsHashAndKeepsInstall(t *testing.T) {
+	m := NewManager(t.TempDir())
+	src := filepath.Join(t.TempDir(), "demo")
+	os.MkdirAll(src, 0o755)
+	os.WriteFile(filepath.Join(src, "SKILL.md"), []byte("v1\n"), 0o644)
+	if _, err := m.ImportDir("demo", src); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.WriteMeta("demo", Meta{SourceType: SourceLocal, SourceURL: src, InstalledBy: "pskill add"}); err != nil {
+		t.Fatal(err)
+	}
+	first, err := m.ReadMeta("demo")
+	if err != nil || !strings.HasPrefix(first.Hash, "sha256:") || first.Installer == "" {
+		t.Fatalf("meta = %+v, %v", first, err)
+	}
+	if want, _ := ContentHash(src); first.Hash != want {
+		t.Errorf("store hash %s differs from source hash %s", first.Hash, want)
+	}
+	if modified, _ := m.Modified("demo"); modified {
+		t.Error("fresh entry reported as modified")
+	}
+
+	os.WriteFile(filepath.Join(m.SkillDir("demo"), "SKILL.md"), []byte("edited\n"), 0o644)
+	if modified, _ := m.Modified("demo"); !modified {
+		t.Error("edited entry not reported as modified")
+	}
+
+	// Bookkeeping writes keep the recorded hash and update time.
+	if err := m.SetProject("demo", "/work/app", true); err != nil {
+		t.Fatal(err)
+	}
+	kept, _ := m.ReadMeta("demo")
+	if kept.Hash != first.Hash || !kept.UpdatedAt.Equal(first.UpdatedAt) {
+		t.Errorf("SetProject restamped the entry: %+v", kept)
+	}
+	if modified, _ := m.Modified("demo"); !modified {
+		t.Error("SetProject blessed a hand edit")
+	}
+
+	if err := m.WriteMeta("demo", m.Stamp("demo", Meta{SourceType: SourceLocal, SourceURL: src, InstalledBy: "pskill update"})); err != nil {
+		t.Fatal(err)
+	}
+	second, _ := m.ReadMeta("demo")
+	if second.InstalledBy != "pskill add" || !second.InstalledAt.Equal(first.InstalledAt) || second.Hash == first.Hash || !second.UpdatedAt.After(first.UpdatedAt) {
+		t.Errorf("rewrite lost install details or kept a stale hash: %+v", second)
+	}
+}
+
+func TestImportSkill_KeepsExistingProvenance(t *testing.T) {
+	m := NewManager(t.TempDir())
+	cliDir := filepath.Join(t.TempDir(), "demo")
+	os.MkdirAll(cliDir, 0o755)
+	os.WriteFile(filepath.Join(cliDir, "SKILL.md"), []byte("---\nname: demo\n---\nBody\n"), 0o644)
+	sk := skill.Skill{Name: "demo", Path: filepath.Join(cliDir, "SKILL.md"), SourceCLI: "cursor"}
+
+	if err := m.ImportSkill(sk, "pskill scan"); err != nil {
+		t.Fatal(err)
+	}
+	meta, _ := m.ReadMeta("demo")
+	if meta.SourceType != SourceImport || meta.SourceCLI != "cursor" || meta.SourceURL != cliDir {
+		t.Errorf("unexpected import meta: %+v", meta)
+	}
+
+	m.WriteMeta("demo", Meta{SourceType: SourceRegistry, Registry: "team"})
+	if err := m.ImportSkill(sk, "pskill scan"); err != nil {
+		t.Fatal(err)
+	}
+	if meta, _ := m.ReadMeta("demo"); meta.SourceType != SourceRegistry {
+		t.Errorf("rescan replaced provenance: %+v", meta)
+	}
+}
+
+func TestLinkMeta_StaysOutOfWorkingDir(t *testing.T) {
+	m := NewManager(t.TempDir())
+	work := filepath.Join(t.TempDir(), "dev")
+	os.MkdirAll(work, 0o755)
+	os.WriteFile(filepath.Join(work, "SKILL.md"), []byte("v1\n"), 0o644)
+	if err := m.LinkDir("dev", work); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.WriteMeta("dev", Meta{SourceType: SourceLink, SourceURL: work, InstalledBy: "pskill link"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(work, MetaFile)); err == nil {
+		t.Fatal("meta written into the linked working directory")
+	}
+	if meta, err := m.ReadMeta("dev"); err != nil || meta.SourceType != SourceLink || meta.Hash == "" {
+		t.Fatalf("link meta = %+v, %v", meta, err)
+	}
+
+	if _, err := m.UnlinkDir("dev"); err != nil {
+		t.Fatal(err)
+	}
+	meta, err := m.ReadMeta("dev")
+	if err != nil || meta.SourceType != SourceLocal || meta.InstalledBy != "pskill link" {
+		t.Errorf("unlinked meta = %+v, %v", meta, err)
+	}
+	if _, err := os.Stat(filepath.Join(filepath.Dir(m.SkillDir("dev")), linksDir, "dev.json")); err == nil {
+		t.Error("link record left behind after unlink")
+	}
+}
+
+func TestContentHash_IgnoresBookkeeping(t *testing.T) {
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, "SKILL.md"), []byte("x"), 0o644)
+	before, err := ContentHash(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	os.WriteFile(filepath.Join(dir, MetaFile), []byte("{}"), 0o644)
+	os.MkdirAll(filepath.Join(dir, ".git"), 0o755)
+	os.WriteFile(filepath.Join(dir, ".git", "HEAD"), []byte("ref"), 0o644)
+	if after, _ := ContentHash(dir); after != before {
+		t.Error("hash changed for meta or .git files")
+	}
+	os.MkdirAll(filepath.Join(dir, "scripts"), 0o755)
+	os.WriteFile(filepath.Join(dir, "scripts", MetaFile), []byte("{}"), 0o644)
+	if nested, _ := ContentHash(dir); nested == before {
+		t.Error("nested .pskill-* file was not hashed")
+	}
+	os.Remove(filepath.Join(dir, "scripts", MetaFile))
+	os.Rename(filepath.Join(dir, "SKILL.md"), filepath.Join(dir, "README.md"))
+	if renamed, _ := ContentHash(dir); renamed == before {
+		t.Error("hash ignores file names")
+	}
+}
+
+func TestContentHash_IgnoresSignature(t *testing.T) {
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, "SKILL.md"), []byte("v1\n"), 0o644)
+	before, _ := ContentHash(dir)
+	os.WriteFile(filepath.Join(dir, SignatureFile), []byte("sig\n"), 0o644)
+	if after, _ := ContentHash(dir); after != before {
+		t.Error("top-level signature changed the hash")
+	}
+	os.MkdirAll(filepath.Join(dir, "sub"), 0o755)
+	os.WriteFile(filepath.Join(dir, "sub", SignatureFile), []byte("sig\n"), 0o644)
+	if nested, _ := ContentHash(dir); nested == before {
+		t.Error("nested SKILL.sig was not hashed")
+	}
+}
+
+func TestSkillFileHash_MatchesImportedCopy(t *testing.T) {
+	dir := t.TempDir()
+	m := NewManager(filepath.Join(dir, "store"))
+	src := filepath.Join(dir, "cli", "demo")
+	os.MkdirAll(src, 0o755)
+	os.WriteFile(filepath.Join(src, "SKILL.md"), []byte("---\nname: demo\n---\n"), 0o644)
+	os.WriteFile(filepath.Join(src, "notes.txt"), []byte("not imported\n"), 0o644)
+
+	if err := m.ImportSkill(skill.Skill{Name: "demo", Path: filepath.Join(src, "SKILL.md")}, "test"); err != nil {
+		t.Fatal(err)
+	}
+	meta, _ := m.ReadMeta("demo")
+	if h, err := SkillFileHash(src); err != nil || h != meta.Hash {
+		t.Errorf("SkillFileHash = %s, %v; want recorded %s", h, err, meta.Hash)
+	}
+}
@@ -0,0 +1,83 @@
+package store
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// quarantineDir holds skills whose security scan found risks, until they
+// are approved. ListSkills skips it because it is dot-prefixed, and nothing
+// links into it.
+const quarantineDir = ".quarantine"
+
+// QuarantineDir returns where a quarantined skill is kept.
+func (m *Manager) QuarantineDir(name string) string {
+	return filepath.Join(m.storeDir, quarantineDir, name)
+}
+
+// IsQuarantined reports whether name is waiting for approval.
+func (m *Manager) IsQuarantined(name string) bool {
+	fi, err := os.Stat(m.QuarantineDir(name))
+	return err == nil && fi.IsDir()
+}
+
+// Quarantine moves a store entry into quarantine, replacing an older
+// quarantined copy. Links into the store entry stop resolving until it is
+// released.
+func (m *Manager) Quarantine(name string) error {
+	if _, ok := m.LinkTarget(name); ok {
+		return fmt.Errorf("%s is a linked working directory", name)
+	}
+	dst := m.QuarantineDir(name)
+	if err := os.MkdirAll(filepath.Dir(dst), 0o755); err != nil {
+		return err
+	}
+	if err := os.RemoveAll(dst); err != nil {
+		return err
+	}
+	return os.Rename(m.SkillDir(name), dst)
+}
+
+// Release moves a quarantined skill back into the store, replacing any
+// entry there.
+func (m *Manager) Release(name string) error {
+	src := m.QuarantineDir(name)
+	if !m.IsQuarantined(name) {
+		return fmt.Errorf("%s is not quarantined", name)
+	}
+	dst := m.SkillDir(name)
+	if err := os.RemoveAll(dst); err != nil {
+		return err
+	}
+	return os.Rename(src, dst)
+}
+
+// RemoveQuarantined deletes a quarantined skill.
+func (m *Manager) RemoveQuarantined(name string) error {
+	if !m.IsQuarantined(name) {
+		return fmt.Errorf("%s is not quarantined", name)
+	}
+	return os.RemoveAll(m.QuarantineDir(name))
+}
+
+// ListQuarantined returns the names of quarantined skills.
+func (m *Manager) ListQuarantined() ([]string, error) {
+	entries, err := os.ReadDir(filepath.Join(m.storeDir, quarantineDir))
+	if errors.Is(err, os.ErrNotExist) {
+		return []string{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	out := make([]string, 0, len(entries))
+	for _, e := range entries {
+		if e.IsDir() {
+			out = append(out, e.Name())
+		}
+	}
+	sort.Strings(out)
+	return out, nil
+}
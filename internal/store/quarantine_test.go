@@ -0,0 +1,80 @@
+package store
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestQuarantine_RoundTrip(t *testing.T) {
+	m := NewManager(t.TempDir())
+	src := filepath.Join(t.TempDir(), "demo")
+	os.MkdirAll(src, 0o755)
+	os.WriteFile(filepath.Join(src, "SKILL.md"), []byte("v1\n"), 0o644)
+	if _, err := m.ImportDir("demo", src); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.WriteMeta("demo", Meta{SourceType: SourceLocal, SourceURL: src}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := m.Quarantine("demo"); err != nil {
+		t.Fatal(err)
+	}
+	if !m.IsQuarantined("demo") {
+		t.Fatal("demo not quarantined")
+	}
+	if names, _ := m.ListSkills(); len(names) != 0 {
+		t.Errorf("ListSkills = %v, want quarantined skill hidden", names)
+	}
+	if names, _ := m.ListQuarantined(); len(names) != 1 || names[0] != "demo" {
+		t.Errorf("ListQuarantined = %v", names)
+	}
+	if meta, err := m.ReadMeta("demo"); err != nil || meta.SourceURL != src {
+		t.Errorf("meta of quarantined skill = %+v, %v", meta, err)
+	}
+
+	// A newer quarantined copy replaces the older one.
+	os.WriteFile(filepath.Join(src, "SKILL.md"), []byte("v2\n"), 0o644)
+	m.ImportDir("demo", src)
+	if err := m.Quarantine("demo"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := m.Release("demo"); err != nil {
+		t.Fatal(err)
+	}
+	if m.IsQuarantined("demo") {
+		t.Error("still quarantined after release")
+	}
+	if raw, _ := os.ReadFile(filepath.Join(m.SkillDir("demo"), "SKILL.md")); string(raw) != "v2\n" {
+		t.Errorf("released content = %q, want v2", raw)
+	}
+	if err := m.Release("demo"); err == nil {
+		t.Error("releasing a skill that is not quarantined succeeded")
+	}
+}
+
+func TestWriteMeta_KeepsApproval(t *testing.T) {
+	m := NewManager(t.TempDir())
+	src := filepath.Join(t.TempDir(), "demo")
+	os.MkdirAll(src, 0o755)
+	os.WriteFile(filepath.Join(src, "SKILL.md"), []byte("v1\n"), 0o644)
+	m.ImportDir("demo", src)
+	m.WriteMeta("demo", Meta{SourceType: SourceLocal, Approved: "sha256:abc"})
+	m.WriteMeta("demo", Meta{SourceType: SourceLocal})
+	if meta, _ := m.ReadMeta("demo"); meta.Approved != "sha256:abc" {
+		t.Errorf("Approved = %q after rewrite", meta.Approved)
+	}
+}
+
+func TestRemoveSkill_DeletesQuarantinedCopy(t *testing.T) {
+	m := NewManager(t.TempDir())
+	os.MkdirAll(m.QuarantineDir("demo"), 0o755)
+	if err := m.RemoveSkill("demo"); err != nil {
+		t.Fatal(err)
+	}
+	if m.IsQuarantined("demo") {
+		t.Error("quarantined copy survived RemoveSkill")
+	}
+}
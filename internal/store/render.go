@@ -0,0 +1,165 @@
+package store
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/ZiaoLiu-1/pskill/internal/skill"
+)
+
+// RenderMarker sits inside a rendered project-local copy and records what
+// pskill wrote, so later syncs can tell hand edits from stale renders.
+const RenderMarker = ".pskill-render.json"
+
+var (
+	ErrHandEdited = errors.New("rendered copy was edited by hand")
+	ErrUnmanaged  = errors.New("destination exists and is not managed by pskill")
+)
+
+type renderRecord struct {
+	Skill      string            `json:"skill"`
+	Hash       string            `json:"hash"`
+	Vars       map[string]string `json:"vars,omitempty"`
+	RenderedAt time.Time         `json:"renderedAt"`
+}
+
+// IsTemplated reports whether the stored skill uses {{ .Vars.x }} placeholders.
+func (m *Manager) IsTemplated(name string) bool {
+	raw, err := os.ReadFile(filepath.Join(m.storeDir, name, "SKILL.md"))
+	if err != nil {
+		return false
+	}
+	return skill.IsTemplate(raw)
+}
+
+// LinkSkillToProject places a skill into a project-local CLI dir: a symlink
+// for plain skills, a copy rendered against vars for templated ones. It
+// reports whether a rendered copy was written.
+func (m *Manager) LinkSkillToProject(name, cliDir string, vars map[string]string, force bool) (bool, error) {
+	if cliDir == "" {
+		return false, nil
+	}
+	dst := filepath.Join(cliDir, name)
+	if m.IsTemplated(name) {
+		return true, m.RenderSkill(name, dst, vars, force)
+	}
+	if IsRendered(dst) {
+		if err := RemoveProjectCopy(dst, force); err != nil {
+			return false, err
+		}
+	}
+	return false, EnsureSymlink(filepath.Join(m.storeDir, name), dst)
+}
+
+// RenderSkill writes a copy of the stored skill to dst with SKILL.md rendered
+// against vars. An existing rendered copy that was edited by hand is kept
+// and ErrHandEdited returned, unless force is set.
+func (m *Manager) RenderSkill(name, dst string, vars map[string]string, force bool) error {
+	src := filepath.Join(m.storeDir, name)
+	raw, err := os.ReadFile(filepath.Join(src, "SKILL.md"))
+	if err != nil {
+		return err
+	}
+	out, err := skill.Render(raw, vars)
+	if err != nil {
+		return fmt.Errorf("%s: %w", name, err)
+	}
+
+	if fi, err := os.Lstat(dst); err == nil && fi.IsDir() && !force {
+		if !IsRendered(dst) {
+			return fmt.Errorf("%s: %w", dst, ErrUnmanaged)
+		}
+		edited, err := HandEdited(dst)
+		if err != nil {
+			return err
+		}
+		if edited {
+			return fmt.Errorf("%s: %w", dst, ErrHandEdited)
+		}
+	}
+
+	if err := os.MkdirAll(filepath.Dir(dst), 0o755); err != nil {
+		return err
+	}
+	tmp := filepath.Join(filepath.Dir(dst), "."+filepath.Base(dst)+".pskill-tmp")
+	_ = os.RemoveAll(tmp)
+	if err := copyDir(src, tmp); err != nil {
+		_ = os.RemoveAll(tmp)
+		return err
+	}
+	if err := os.WriteFile(filepath.Join(tmp, "SKILL.md"), out, 0o644); err != nil {
+		_ = os.RemoveAll(tmp)
+		return err
+	}
+	rec := renderRecord{Skill: name, Hash: hashBytes(out), Vars: vars, RenderedAt: time.Now().UTC()}
+	payload, _ := json.MarshalIndent(rec, "", "  ")
+	if err := os.WriteFile(filepath.Join(tmp, RenderMarker), payload, 0o644); err != nil {
+		_ = os.RemoveAll(tmp)
+		return err
+	}
+
+	if err := os.RemoveAll(dst); err != nil {
+		_ = os.RemoveAll(tmp)
+		return err
+	}
+	return os.Rename(tmp, dst)
+}
+
+// IsRendered reports whether dir is a rendered copy written by pskill.
+func IsRendered(dir string) bool {
+	_, err := os.Stat(filepath.Join(dir, RenderMarker))
+	return err == nil
+}
+
+// HandEdited reports whether a rendered copy's SKILL.md differs from what
+// pskill last wrote.
+func HandEdited(dir string) (bool, error) {
+	raw, err := os.ReadFile(filepath.Join(dir, RenderMarker))
+	if err != nil {
+		return false, err
+	}
+	var rec renderRecord
+	if err := json.Unmarshal(raw, &rec); err != nil {
+		return false, err
+	}
+	cur, err := os.ReadFile(filepath.Join(dir, "SKILL.md"))
+	if err != nil {
+		return true, nil
+	}
+	return hashBytes(cur) != rec.Hash, nil
+}
+
+// RemoveProjectCopy removes a symlink or rendered copy from a CLI skill
+// dir. Plain directories pskill did not write are left alone.
+func RemoveProjectCopy(dst string, force bool) error {
+	fi, err := os.Lstat(dst)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+		return err
+	}
+	if fi.Mode()&os.ModeSymlink != 0 {
+		return os.Remove(dst)
+	}
+	if !IsRendered(dst) {
+		return fmt.Errorf("%s: %w", dst, ErrUnmanaged)
+	}
+	if !force {
+		if edited, _ := HandEdited(dst); edited {
+			return fmt.Errorf("%s: %w", dst, ErrHandEdited)
+		}
+	}
+	return os.RemoveAll(dst)
+}
+
+func hashBytes(b []byte) string {
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:])
+}
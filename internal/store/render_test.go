@@ -0,0 +1,107 @@
+package store
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func newTemplatedStore(t *testing.T) (*Manager, string) {
+	t.Helper()
+	dir := t.TempDir()
+	m := NewManager(filepath.Join(dir, "store"))
+	p, _, _ := m.EnsureSkillDir("tpl")
+	content := "---\nname: tpl\nvars:\n  cmd: make test\n---\n\nRun {{ .Vars.cmd }}\n"
+	if err := os.WriteFile(filepath.Join(p, "SKILL.md"), []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return m, filepath.Join(dir, "project", ".claude", "skills")
+}
+
+func TestLinkSkillToProject_RendersTemplate(t *testing.T) {
+	m, cliDir := newTemplatedStore(t)
+
+	rendered, err := m.LinkSkillToProject("tpl", cliDir, map[string]string{"cmd": "go test"}, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !rendered {
+		t.Fatal("expected a rendered copy")
+	}
+	dst := filepath.Join(cliDir, "tpl")
+	fi, err := os.Lstat(dst)
+	if err != nil || fi.Mode()&os.ModeSymlink != 0 {
+		t.Fatal("expected a real directory, not a symlink")
+	}
+	raw, _ := os.ReadFile(filepath.Join(dst, "SKILL.md"))
+	if !strings.Contains(string(raw), "Run go test") {
+		t.Errorf("unexpected render: %s", raw)
+	}
+}
+
+func TestRenderSkill_DetectsHandEdits(t *testing.T) {
+	m, cliDir := newTemplatedStore(t)
+	dst := filepath.Join(cliDir, "tpl")
+	if err := m.RenderSkill("tpl", dst, nil, false); err != nil {
+		t.Fatal(err)
+	}
+
+	// Re-rendering an untouched copy is fine.
+	if err := m.RenderSkill("tpl", dst, map[string]string{"cmd": "npm test"}, false); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.WriteFile(filepath.Join(dst, "SKILL.md"), []byte("edited"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	err := m.RenderSkill("tpl", dst, nil, false)
+	if !errors.Is(err, ErrHandEdited) {
+		t.Fatalf("expected ErrHandEdited, got %v", err)
+	}
+	if err := m.RenderSkill("tpl", dst, nil, true); err != nil {
+		t.Fatalf("force render failed: %v", err)
+	}
+	raw, _ := os.ReadFile(filepath.Join(dst, "SKILL.md"))
+	if !strings.Contains(string(raw), "Run make test") {
+		t.Errorf("expected forced re-render with defaults, got %s", raw)
+	}
+}
+
+func TestLinkSkillToProject_PlainSkillSymlinks(t *testing.T) {
+	dir := t.TempDir()
+	m := NewManager(filepath.Join(dir, "store"))
+	p, _, _ := m.EnsureSkillDir("plain")
+	_ = os.WriteFile(filepath.Join(p, "SKILL.md"), []byte("# Plain\n"), 0o644)
+
+	cliDir := filepath.Join(dir, "cli")
+	rendered, err := m.LinkSkillToProject("plain", cliDir, nil, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rendered {
+		t.Error("plain skill should be symlinked, not rendered")
+	}
+	fi, err := os.Lstat(filepath.Join(cliDir, "plain"))
+	if err != nil || fi.Mode()&os.ModeSymlink == 0 {
+		t.Error("expected a symlink")
+	}
+}
+
+func TestLinkSkillToCLI_RendersDefaults(t *testing.T) {
+	m, _ := newTemplatedStore(t)
+	cliDir := filepath.Join(t.TempDir(), "global", "skills")
+
+	if err := m.LinkSkillToCLI("tpl", cliDir); err != nil {
+		t.Fatal(err)
+	}
+	dst := filepath.Join(cliDir, "tpl")
+	if !IsRendered(dst) {
+		t.Fatal("expected a rendered copy in the global dir")
+	}
+	raw, _ := os.ReadFile(filepath.Join(dst, "SKILL.md"))
+	if !strings.Contains(string(raw), "Run make test") {
+		t.Errorf("expected the frontmatter default, got: %s", raw)
+	}
+}
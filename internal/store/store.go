@@ -2,6 +2,7 @@ package store
 
 import (
 	"errors"
+	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
@@ -33,7 +34,11 @@ func (m *Manager) EnsureSkillDir(name string) (string, bool, error) {
 	return path, false, os.MkdirAll(path, 0o755)
 }
 
-func (m *Manager) ImportSkill(sk skill.Skill) error {
+// ImportSkill copies a skill found in a CLI skill dir into the store. A
+// provenance record naming the command in by is written unless the entry
+// already has one, so rescanning a CLI dir that links back into the store
+// keeps the original source.
+func (m *Manager) ImportSkill(sk skill.Skill, by string) error {
 	dest, _, err := m.EnsureSkillDir(sk.Name)
 	if err != nil {
 		return err
@@ -42,10 +47,48 @@ func (m *Manager) ImportSkill(sk skill.Skill) error {
 	if err != nil {
 		return err
 	}
-	return os.WriteFile(filepath.Join(dest, "SKILL.md"), raw, 0o644)
+	if err := os.WriteFile(filepath.Join(dest, "SKILL.md"), raw, 0o644); err != nil {
+		return err
+	}
+	if _, err := m.ReadMeta(sk.Name); err == nil {
+		return nil
+	}
+	return m.WriteMeta(sk.Name, m.Stamp(sk.Name, Meta{SourceType: SourceImport, SourceURL: filepath.Dir(sk.Path), SourceCLI: sk.SourceCLI, InstalledBy: by}))
+}
+
+// ImportDir replaces the store entry for name with a copy of srcDir,
+// including supporting files next to SKILL.md. Existing meta is kept.
+func (m *Manager) ImportDir(name, srcDir string) (string, error) {
+	fi, err := os.Lstat(filepath.Join(srcDir, "SKILL.md"))
+	if err != nil {
+		return "", err
+	}
+	if !fi.Mode().IsRegular() {
+		return "", fmt.Errorf("%s: SKILL.md is not a regular file", srcDir)
+	}
+	dest, _, err := m.EnsureSkillDir(name)
+	if err != nil {
+		return "", err
+	}
+	meta, metaErr := os.ReadFile(filepath.Join(dest, MetaFile))
+	tmp := filepath.Join(m.storeDir, "."+name+".pskill-tmp")
+	_ = os.RemoveAll(tmp)
+	if err := copyDir(srcDir, tmp); err != nil {
+		_ = os.RemoveAll(tmp)
+		return "", err
+	}
+	if metaErr == nil {
+		_ = os.WriteFile(filepath.Join(tmp, MetaFile), meta, 0o644)
+	}
+	if err := os.RemoveAll(dest); err != nil {
+		return "", err
+	}
+	return dest, os.Rename(tmp, dest)
 }
 
 func (m *Manager) RemoveSkill(name string) error {
+	m.removeLinkMeta(name)
+	_ = os.RemoveAll(m.QuarantineDir(name))
 	return os.RemoveAll(filepath.Join(m.storeDir, name))
 }
 
@@ -59,20 +102,28 @@ func (m *Manager) ListSkills() ([]string, error) {
 	}
 	out := make([]string, 0, len(entries))
 	for _, e := range entries {
-		if e.IsDir() {
-			out = append(out, e.Name())
+		if strings.HasPrefix(e.Name(), ".") {
+			continue
 		}
+		if e.Type()&os.ModeSymlink != 0 {
+			// Linked dev skills: keep links that still point at a directory.
+			if fi, err := os.Stat(filepath.Join(m.storeDir, e.Name())); err != nil || !fi.IsDir() {
+				continue
+			}
+		} else if !e.IsDir() {
+			continue
+		}
+		out = append(out, e.Name())
 	}
 	return out, nil
 }
 
+// LinkSkillToCLI places a skill into a global CLI skill dir: a symlink for
+// plain skills, a copy rendered with the frontmatter defaults for templated
+// ones, so agents never read raw placeholders.
 func (m *Manager) LinkSkillToCLI(skillName, cliDir string) error {
-	if cliDir == "" {
-		return nil
-	}
-	src := filepath.Join(m.storeDir, skillName)
-	dst := filepath.Join(cliDir, skillName)
-	return EnsureSymlink(src, dst)
+	_, err := m.LinkSkillToProject(skillName, cliDir, nil, false)
+	return err
 }
 
 func (m *Manager) UnlinkSkillEverywhere(skillName string) error {
@@ -84,7 +135,7 @@ func (m *Manager) UnlinkSkillEverywhere(skillName string) error {
 		if !cli.SupportsSkills || !cli.Installed || strings.TrimSpace(cli.SkillDir) == "" {
 			continue
 		}
-		_ = os.Remove(filepath.Join(cli.SkillDir, skillName))
+		_ = RemoveProjectCopy(filepath.Join(cli.SkillDir, skillName), true)
 	}
 	return nil
 }
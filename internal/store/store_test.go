@@ -64,7 +64,7 @@ func TestImportSkill(t *testing.T) {
 
 	m := NewManager(storeDir)
 	sk := skill.Skill{Name: "imported", Path: srcPath}
-	if err := m.ImportSkill(sk); err != nil {
+	if err := m.ImportSkill(sk, "test"); err != nil {
 		t.Fatal(err)
 	}
 
@@ -172,3 +172,145 @@ func TestLinkSkillToCLI_EmptyDir(t *testing.T) {
 		t.Errorf("expected nil for empty cliDir, got %v", err)
 	}
 }
+
+func TestImportDir_CopiesTreeAndKeepsMeta(t *testing.T) {
+	dir := t.TempDir()
+	m := NewManager(filepath.Join(dir, "store"))
+	src := filepath.Join(dir, "src")
+	_ = os.MkdirAll(filepath.Join(src, "scripts"), 0o755)
+	_ = os.MkdirAll(filepath.Join(src, ".git"), 0o755)
+	_ = os.WriteFile(filepath.Join(src, "SKILL.md"), []byte("v1"), 0o644)
+	_ = os.WriteFile(filepath.Join(src, "scripts", "run.sh"), []byte("echo"), 0o644)
+	_ = os.WriteFile(filepath.Join(src, MetaFile), []byte(`{"sourceType":"registry","approved":true}`), 0o644)
+
+	if _, err := m.ImportDir("multi", src); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "store", "multi", MetaFile)); err == nil {
+		t.Error("meta shipped in the source should not be copied")
+	}
+	if err := m.WriteMeta("multi", Meta{SourceType: SourceGit, SourceURL: "file:///x", Ref: "main"}); err != nil {
+		t.Fatal(err)
+	}
+	first, _ := m.ReadMeta("multi")
+
+	_ = os.WriteFile(filepath.Join(src, "SKILL.md"), []byte("v2"), 0o644)
+	if _, err := m.ImportDir("multi", src); err != nil {
+		t.Fatal(err)
+	}
+	raw, _ := os.ReadFile(filepath.Join(dir, "store", "multi", "SKILL.md"))
+	if string(raw) != "v2" {
+		t.Errorf("expected refreshed SKILL.md, got %q", raw)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "store", "multi", "scripts", "run.sh")); err != nil {
+		t.Error("expected supporting files to be copied")
+	}
+	if _, err := os.Stat(filepath.Join(dir, "store", "multi", ".git")); err == nil {
+		t.Error(".git should not be copied into the store")
+	}
+	meta, err := m.ReadMeta("multi")
+	if err != nil || meta.SourceURL != "file:///x" || !meta.InstalledAt.Equal(first.InstalledAt) {
+		t.Errorf("meta not preserved: %+v, %v", meta, err)
+	}
+}
+
+func TestImportDir_SkipsSymlinksAndKeepsModes(t *testing.T) {
+	dir := t.TempDir()
+	m := NewManager(filepath.Join(dir, "store"))
+	secret := filepath.Join(dir, "id_rsa")
+	_ = os.WriteFile(secret, []byte("PRIVATE KEY"), 0o600)
+	src := filepath.Join(dir, "src")
+	_ = os.MkdirAll(filepath.Join(src, "scripts"), 0o755)
+	_ = os.WriteFile(filepath.Join(src, "SKILL.md"), []byte("v1"), 0o644)
+	_ = os.WriteFile(filepath.Join(src, "scripts", "run.sh"), []byte("echo"), 0o755)
+	if err := os.Symlink(secret, filepath.Join(src, "notes.md")); err != nil {
+		t.Skip("symlinks unsupported:", err)
+	}
+	_ = os.Symlink(dir, filepath.Join(src, "up"))
+
+	dest, err := m.ImportDir("linky", src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"notes.md", "up"} {
+		if _, err := os.Lstat(filepath.Join(dest, name)); err == nil {
+			t.Errorf("symlink %s was copied into the store", name)
+		}
+	}
+	fi, err := os.Stat(filepath.Join(dest, "scripts", "run.sh"))
+	if err != nil || fi.Mode().Perm()&0o111 == 0 {
+		t.Errorf("run.sh lost its executable bit: %v, %v", fi, err)
+	}
+}
+
+func TestSnapshotVersion_KeepsHistory(t *testing.T) {
+	dir := t.TempDir()
+	m := NewManager(dir)
+	src := filepath.Join(t.TempDir(), "skill")
+	os.MkdirAll(src, 0o755)
+	os.WriteFile(filepath.Join(src, "SKILL.md"), []byte("v1\n"), 0o644)
+	if _, err := m.ImportDir("demo", src); err != nil {
+		t.Fatal(err)
+	}
+
+	id, err := m.SnapshotVersion("demo")
+	if err != nil || id == "" {
+		t.Fatalf("SnapshotVersion = %q, %v", id, err)
+	}
+	os.WriteFile(filepath.Join(src, "SKILL.md"), []byte("v2\n"), 0o644)
+	if _, err := m.ImportDir("demo", src); err != nil {
+		t.Fatal(err)
+	}
+
+	vdir, err := m.VersionDir("demo", id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	raw, _ := os.ReadFile(filepath.Join(vdir, "SKILL.md"))
+	if string(raw) != "v1\n" {
+		t.Errorf("snapshot content = %q, want v1", raw)
+	}
+	names, _ := m.ListSkills()
+	if len(names) != 1 {
+		t.Errorf("version history leaked into ListSkills: %v", names)
+	}
+}
+
+func TestLinkDir_LiveEditsAndUnlink(t *testing.T) {
+	m := NewManager(t.TempDir())
+	work := filepath.Join(t.TempDir(), "dev-skill")
+	os.MkdirAll(work, 0o755)
+	os.WriteFile(filepath.Join(work, "SKILL.md"), []byte("v1\n"), 0o644)
+
+	if err := m.LinkDir("dev-skill", work); err != nil {
+		t.Fatal(err)
+	}
+	names, _ := m.ListSkills()
+	if len(names) != 1 || names[0] != "dev-skill" {
+		t.Fatalf("linked skill missing from ListSkills: %v", names)
+	}
+	if target, ok := m.LinkTarget("dev-skill"); !ok || target != work {
+		t.Errorf("LinkTarget = %q, %v", target, ok)
+	}
+
+	os.WriteFile(filepath.Join(work, "SKILL.md"), []byte("v2\n"), 0o644)
+	raw, _ := os.ReadFile(filepath.Join(m.SkillDir("dev-skill"), "SKILL.md"))
+	if string(raw) != "v2\n" {
+		t.Errorf("edit not live through link: %q", raw)
+	}
+
+	if _, err := m.UnlinkDir("dev-skill"); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := m.LinkTarget("dev-skill"); ok {
+		t.Error("store entry still a link after UnlinkDir")
+	}
+	os.WriteFile(filepath.Join(work, "SKILL.md"), []byte("v3\n"), 0o644)
+	raw, _ = os.ReadFile(filepath.Join(m.SkillDir("dev-skill"), "SKILL.md"))
+	if string(raw) != "v2\n" {
+		t.Errorf("expected snapshot v2 after unlink, got %q", raw)
+	}
+	if _, err := os.Stat(filepath.Join(work, MetaFile)); err == nil {
+		t.Error("meta written into the working directory")
+	}
+}
@@ -1,6 +1,7 @@
 package store
 
 import (
+	"io/fs"
 	"os"
 	"path/filepath"
 	"runtime"
@@ -23,7 +24,15 @@ func EnsureSymlink(src, dst string) error {
 	return err
 }
 
+// copyDir copies the regular files under src into dst with their
+// permissions, leaving out VCS data and any bookkeeping files src carries,
+// which pskill writes itself. Symlinks are skipped rather than followed, so
+// a link to a file outside src never lands in the store.
 func copyDir(src, dst string) error {
+	return copyDirAt(src, dst, "")
+}
+
+func copyDirAt(src, dst, rel string) error {
 	entries, err := os.ReadDir(src)
 	if err != nil {
 		return err
@@ -32,19 +41,30 @@ func copyDir(src, dst string) error {
 		return err
 	}
 	for _, e := range entries {
+		entryRel := filepath.Join(rel, e.Name())
+		if e.Name() == ".git" || IsBookkeeping(entryRel) {
+			continue
+		}
 		srcPath := filepath.Join(src, e.Name())
 		dstPath := filepath.Join(dst, e.Name())
 		if e.IsDir() {
-			if err := copyDir(srcPath, dstPath); err != nil {
+			if err := copyDirAt(srcPath, dstPath, entryRel); err != nil {
 				return err
 			}
 			continue
 		}
+		if e.Type()&fs.ModeSymlink != 0 || !e.Type().IsRegular() {
+			continue
+		}
+		info, err := e.Info()
+		if err != nil {
+			return err
+		}
 		raw, err := os.ReadFile(srcPath)
 		if err != nil {
 			return err
 		}
-		if err := os.WriteFile(dstPath, raw, 0o644); err != nil {
+		if err := os.WriteFile(dstPath, raw, info.Mode().Perm()); err != nil {
 			return err
 		}
 	}
@@ -0,0 +1,95 @@
+package store
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// versionsDir holds snapshots of store entries taken before they are
+// replaced. ListSkills skips it because it is dot-prefixed.
+const versionsDir = ".versions"
+
+// MaxVersions is how many snapshots are kept per skill.
+const MaxVersions = 5
+
+// SkillDir returns the store path for a skill.
+func (m *Manager) SkillDir(name string) string {
+	return filepath.Join(m.storeDir, name)
+}
+
+// SnapshotVersion copies the current store entry for name into the version
+// history and returns its id. A missing entry is not an error and yields "".
+func (m *Manager) SnapshotVersion(name string) (string, error) {
+	src := m.SkillDir(name)
+	if _, err := os.Stat(filepath.Join(src, "SKILL.md")); err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return "", nil
+		}
+		return "", err
+	}
+	base := filepath.Join(m.storeDir, versionsDir, name)
+	if err := os.MkdirAll(base, 0o755); err != nil {
+		return "", err
+	}
+	id := time.Now().UTC().Format("20060102T150405Z")
+	for i := 2; ; i++ {
+		if _, err := os.Stat(filepath.Join(base, id)); errors.Is(err, os.ErrNotExist) {
+			break
+		}
+		id = fmt.Sprintf("%s-%d", id[:16], i)
+	}
+	if err := copyDir(src, filepath.Join(base, id)); err != nil {
+		return "", err
+	}
+	return id, m.pruneVersions(name)
+}
+
+// ListVersions returns snapshot ids for name, oldest first.
+func (m *Manager) ListVersions(name string) ([]string, error) {
+	entries, err := os.ReadDir(filepath.Join(m.storeDir, versionsDir, name))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	out := []string{}
+	for _, e := range entries {
+		if e.IsDir() {
+			out = append(out, e.Name())
+		}
+	}
+	sort.Strings(out)
+	return out, nil
+}
+
+// VersionDir returns the directory of a snapshot. The id "current" refers
+// to the live store entry.
+func (m *Manager) VersionDir(name, id string) (string, error) {
+	if id == "" || id == "current" {
+		return m.SkillDir(name), nil
+	}
+	dir := filepath.Join(m.storeDir, versionsDir, name, id)
+	if _, err := os.Stat(dir); err != nil {
+		return "", fmt.Errorf("version %s of %s not found", id, name)
+	}
+	return dir, nil
+}
+
+func (m *Manager) pruneVersions(name string) error {
+	ids, err := m.ListVersions(name)
+	if err != nil {
+		return err
+	}
+	for len(ids) > MaxVersions {
+		if err := os.RemoveAll(filepath.Join(m.storeDir, versionsDir, name, ids[0])); err != nil {
+			return err
+		}
+		ids = ids[1:]
+	}
+	return nil
+}
@@ -0,0 +1,136 @@
+package tokens
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/ZiaoLiu-1/pskill/internal/project"
+	"github.com/ZiaoLiu-1/pskill/internal/skill"
+)
+
+// Count is the estimated context cost of one skill. Description (name plus
+// frontmatter description) is loaded into every session; Body only loads
+// when the agent activates the skill.
+type Count struct {
+	Description int `json:"description"`
+	Body        int `json:"body"`
+}
+
+func (c Count) Total() int { return c.Description + c.Body }
+
+// Usage is the token cost of every skill visible to one CLI.
+type Usage struct {
+	CLI    string           `json:"cli"`
+	Skills map[string]Count `json:"skills"`
+	Count
+}
+
+// Estimate approximates a BPE tokenizer: word pieces of roughly four
+// characters, one token per punctuation mark, one per non-ASCII rune.
+func Estimate(text string) int {
+	n := 0
+	run := 0
+	flush := func() {
+		if run > 0 {
+			n += (run + 3) / 4
+			run = 0
+		}
+	}
+	for _, r := range text {
+		switch {
+		case r < utf8.RuneSelf && (unicode.IsLetter(r) || unicode.IsDigit(r)):
+			run++
+		case unicode.IsSpace(r):
+			flush()
+			if r == '\n' {
+				n++
+			}
+		default:
+			flush()
+			n++
+		}
+	}
+	flush()
+	return n
+}
+
+func ForSkill(sk skill.Skill) Count {
+	return Count{
+		Description: Estimate(sk.Name + ": " + sk.Description),
+		Body:        Estimate(sk.Body),
+	}
+}
+
+// ForDir estimates the skill stored in dir (dir/SKILL.md).
+func ForDir(dir string) (Count, error) {
+	sk, err := skill.ParseFile(filepath.Join(dir, "SKILL.md"), "")
+	if err != nil {
+		return Count{}, err
+	}
+	return ForSkill(sk), nil
+}
+
+// ForCLI sums every skill found in the given skill directories. A skill
+// present in several dirs (global and project-local) is counted once.
+func ForCLI(cli string, dirs ...string) Usage {
+	u := Usage{CLI: cli, Skills: map[string]Count{}}
+	for _, dir := range dirs {
+		if dir == "" {
+			continue
+		}
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+		for _, e := range entries {
+			name := e.Name()
+			if strings.HasPrefix(name, ".") {
+				continue
+			}
+			if _, ok := u.Skills[name]; ok {
+				continue
+			}
+			c, err := ForDir(filepath.Join(dir, name))
+			if err != nil {
+				continue
+			}
+			u.Add(name, c)
+		}
+	}
+	return u
+}
+
+// Add records a skill in the usage, replacing any previous count for it.
+func (u *Usage) Add(name string, c Count) {
+	if u.Skills == nil {
+		u.Skills = map[string]Count{}
+	}
+	if prev, ok := u.Skills[name]; ok {
+		u.Description -= prev.Description
+		u.Body -= prev.Body
+	}
+	u.Skills[name] = c
+	u.Description += c.Description
+	u.Body += c.Body
+}
+
+// CheckBudget returns one message per CLI that exceeds the budget.
+func CheckBudget(b project.Budget, usage []Usage) []string {
+	var out []string
+	sorted := append([]Usage(nil), usage...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].CLI < sorted[j].CLI })
+	for _, u := range sorted {
+		if b.AlwaysLoaded > 0 && u.Description > b.AlwaysLoaded {
+			out = append(out, fmt.Sprintf("%s: always-loaded %d tokens exceeds budget %d", u.CLI, u.Description, b.AlwaysLoaded))
+		}
+		if b.Total > 0 && u.Total() > b.Total {
+			out = append(out, fmt.Sprintf("%s: total %d tokens exceeds budget %d", u.CLI, u.Total(), b.Total))
+		}
+	}
+	return out
+}
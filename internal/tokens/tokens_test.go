@@ -0,0 +1,78 @@
+package tokens
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ZiaoLiu-1/pskill/internal/project"
+)
+
+func writeSkill(t *testing.T, dir, name, content string) {
+	t.Helper()
+	p := filepath.Join(dir, name)
+	if err := os.MkdirAll(p, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(p, "SKILL.md"), []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestEstimate(t *testing.T) {
+	tests := []struct {
+		in   string
+		want int
+	}{
+		{"", 0},
+		{"hello", 2},
+		{"hi there", 3},
+		{"a, b.", 4},
+		{"line\nline", 3},
+	}
+	for _, tt := range tests {
+		if got := Estimate(tt.in); got != tt.want {
+			t.Errorf("Estimate(%q) = %d, want %d", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestForCLI_DedupesAcrossDirs(t *testing.T) {
+	global := t.TempDir()
+	local := t.TempDir()
+	writeSkill(t, global, "alpha", "---\nname: alpha\ndescription: first skill\n---\n\nBody text here.\n")
+	writeSkill(t, local, "alpha", "---\nname: alpha\ndescription: first skill\n---\n\nBody text here.\n")
+	writeSkill(t, local, "beta", "---\nname: beta\ndescription: second\n---\n\nMore body.\n")
+
+	u := ForCLI("claude", global, local, "")
+	if len(u.Skills) != 2 {
+		t.Fatalf("expected 2 skills, got %d", len(u.Skills))
+	}
+	want := u.Skills["alpha"].Description + u.Skills["beta"].Description
+	if u.Description != want {
+		t.Errorf("Description total = %d, want %d", u.Description, want)
+	}
+}
+
+func TestUsageAdd_Replaces(t *testing.T) {
+	var u Usage
+	u.Add("a", Count{Description: 10, Body: 100})
+	u.Add("a", Count{Description: 5, Body: 50})
+	if u.Description != 5 || u.Body != 50 {
+		t.Errorf("expected replacement totals 5/50, got %d/%d", u.Description, u.Body)
+	}
+}
+
+func TestCheckBudget(t *testing.T) {
+	usage := []Usage{
+		{CLI: "cursor", Count: Count{Description: 50, Body: 500}},
+		{CLI: "claude", Count: Count{Description: 150, Body: 100}},
+	}
+	got := CheckBudget(project.Budget{AlwaysLoaded: 100, Total: 400}, usage)
+	if len(got) != 2 {
+		t.Fatalf("expected 2 violations, got %v", got)
+	}
+	if got := CheckBudget(project.Budget{}, usage); len(got) != 0 {
+		t.Errorf("expected no violations for empty budget, got %v", got)
+	}
+}
@@ -11,7 +11,9 @@ import (
 	"github.com/charmbracelet/lipgloss"
 
 	"github.com/ZiaoLiu-1/pskill/internal/config"
+	"github.com/ZiaoLiu-1/pskill/internal/registry"
 	"github.com/ZiaoLiu-1/pskill/internal/scanner"
+	"github.com/ZiaoLiu-1/pskill/internal/search"
 	"github.com/ZiaoLiu-1/pskill/internal/store"
 )
 
@@ -84,6 +86,8 @@ func NewAppWithTab(cfg config.Config, debug bool, startTab AppTabID) *App {
 }
 
 func (a *App) Init() tea.Cmd {
+	// The TUI outlives a background cache refresh; one-shot commands don't.
+	registry.SetBackgroundRefresh(true)
 	cmds := make([]tea.Cmd, 0, len(a.tabs))
 	for _, t := range a.tabs {
 		if c := t.Init(); c != nil {
@@ -213,37 +217,28 @@ func (a *App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			case "q", "ctrl+c":
 				return a, tea.Quit
 			case "1":
-				a.activeTab = TabDashboard
-				return a, nil
+				return a, a.switchTab(TabDashboard)
 			case "2":
-				a.activeTab = TabMySkills
-				return a, nil
+				return a, a.switchTab(TabMySkills)
 			case "3":
-				a.activeTab = TabDiscover
-				return a, nil
+				return a, a.switchTab(TabDiscover)
 			case "4":
-				a.activeTab = TabTrending
-				return a, nil
+				return a, a.switchTab(TabTrending)
 			case "5":
-				a.activeTab = TabMonitor
-				return a, nil
+				return a, a.switchTab(TabMonitor)
 			case "6":
-				a.activeTab = TabProjects
-				return a, nil
+				return a, a.switchTab(TabProjects)
 			case "7":
-				a.activeTab = TabSettings
-				return a, nil
+				return a, a.switchTab(TabSettings)
 			}
 		}
 
 		// Tab/shift+tab always work for navigation
 		switch m.String() {
 		case "tab":
-			a.activeTab = (a.activeTab + 1) % TabCount
-			return a, nil
+			return a, a.switchTab((a.activeTab + 1) % TabCount)
 		case "shift+tab":
-			a.activeTab = (a.activeTab + TabCount - 1) % TabCount
-			return a, nil
+			return a, a.switchTab((a.activeTab + TabCount - 1) % TabCount)
 		}
 	}
 
@@ -254,6 +249,27 @@ func (a *App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return a, cmd
 }
 
+// switchTab makes id the active tab, telling the old tab it lost focus and
+// the new one that it gained it.
+func (a *App) switchTab(id AppTabID) tea.Cmd {
+	if id == a.activeTab {
+		return nil
+	}
+	var cmds []tea.Cmd
+	if t, ok := a.tabs[a.activeTab]; ok {
+		nt, cmd := t.Update(tabBlurMsg{})
+		a.tabs[a.activeTab] = nt
+		cmds = append(cmds, cmd)
+	}
+	a.activeTab = id
+	if t, ok := a.tabs[id]; ok {
+		nt, cmd := t.Update(tabFocusMsg{})
+		a.tabs[id] = nt
+		cmds = append(cmds, cmd)
+	}
+	return tea.Batch(cmds...)
+}
+
 func (a *App) View() string {
 	if a.width == 0 || a.height == 0 {
 		return "  Initializing pskill..."
@@ -384,11 +400,16 @@ func (a *App) scanSystemCmd() tea.Cmd {
 		}
 		// Import into store
 		st := store.NewManager(a.cfg.StoreDir)
+		engine := search.NewStoreEngine(a.cfg.IndexDir, a.cfg.StoreDir)
 		names := make([]string, 0, len(inv.Skills))
+		imported := map[string]string{}
 		for _, sk := range inv.Skills {
-			_ = st.ImportSkill(sk)
+			if err := st.ImportSkill(sk, "pskill (tui)"); err == nil {
+				imported[sk.Name] = st.SkillDir(sk.Name)
+			}
 			names = append(names, sk.Name)
 		}
+		_, _ = engine.IndexSkillsByPath(imported)
 		// Also list anything already in store
 		existing, _ := st.ListSkills()
 		seen := map[string]bool{}
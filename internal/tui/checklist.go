@@ -0,0 +1,100 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// ChecklistItem is one selectable row in RunChecklist.
+type ChecklistItem struct {
+	Label   string
+	Detail  string
+	Checked bool
+}
+
+type checklistModel struct {
+	title     string
+	items     []ChecklistItem
+	cursor    int
+	cancelled bool
+}
+
+// RunChecklist shows an inline multi-select list and returns the indices of
+// the checked items. Cancelling returns nil.
+func RunChecklist(title string, items []ChecklistItem) ([]int, error) {
+	m := &checklistModel{title: title, items: items}
+	if _, err := tea.NewProgram(m).Run(); err != nil {
+		return nil, err
+	}
+	if m.cancelled {
+		return nil, nil
+	}
+	var out []int
+	for i, it := range m.items {
+		if it.Checked {
+			out = append(out, i)
+		}
+	}
+	return out, nil
+}
+
+func (m *checklistModel) Init() tea.Cmd { return nil }
+
+func (m *checklistModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	k, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return m, nil
+	}
+	switch k.String() {
+	case "j", "down":
+		if m.cursor < len(m.items)-1 {
+			m.cursor++
+		}
+	case "k", "up":
+		if m.cursor > 0 {
+			m.cursor--
+		}
+	case " ", "x":
+		if len(m.items) > 0 {
+			m.items[m.cursor].Checked = !m.items[m.cursor].Checked
+		}
+	case "a":
+		for i := range m.items {
+			m.items[i].Checked = true
+		}
+	case "n":
+		for i := range m.items {
+			m.items[i].Checked = false
+		}
+	case "enter":
+		return m, tea.Quit
+	case "esc", "q", "ctrl+c":
+		m.cancelled = true
+		return m, tea.Quit
+	}
+	return m, nil
+}
+
+func (m *checklistModel) View() string {
+	var b strings.Builder
+	b.WriteString(titleStyle.Render(m.title) + "\n\n")
+	for i, it := range m.items {
+		prefix := "  "
+		if i == m.cursor {
+			prefix = selectedStyle.Render("> ")
+		}
+		check := dimStyle.Render("[ ]")
+		if it.Checked {
+			check = successStyle.Render("[x]")
+		}
+		desc := it.Detail
+		if len(desc) > 50 {
+			desc = desc[:47] + "..."
+		}
+		b.WriteString(fmt.Sprintf("%s%s %-28s %s\n", prefix, check, brightStyle.Render(it.Label), dimStyle.Render(desc)))
+	}
+	b.WriteString("\n" + helpEntry("space", "toggle") + "  " + helpEntry("a/n", "all/none") + "  " + helpEntry("enter", "install") + "  " + helpEntry("esc", "cancel") + "\n")
+	return b.String()
+}
@@ -1,19 +1,28 @@
 package components
 
+// Sparkline draws points scaled between their minimum and maximum, so small
+// changes on a large total (such as a star count) stay visible.
 func Sparkline(points []int64) string {
 	if len(points) == 0 {
 		return ""
 	}
 	blocks := []rune("▁▂▃▄▅▆▇█")
-	var max int64 = 1
+	min, max := points[0], points[0]
 	for _, p := range points {
+		if p < min {
+			min = p
+		}
 		if p > max {
 			max = p
 		}
 	}
+	span := max - min
+	if span == 0 {
+		span = 1
+	}
 	out := make([]rune, 0, len(points))
 	for _, p := range points {
-		idx := int((p * int64(len(blocks)-1)) / max)
+		idx := int(((p - min) * int64(len(blocks)-1)) / span)
 		out = append(out, blocks[idx])
 	}
 	return string(out)
@@ -44,6 +44,14 @@ type statusMsg struct {
 	text string
 }
 
+// tabBlurMsg is sent to the active tab when the user switches away from it,
+// tabFocusMsg to the tab that becomes active. Tabs use them to cancel and
+// restart in-flight fetches, whose results would otherwise go to whichever
+// tab is active when they land.
+type tabBlurMsg struct{}
+
+type tabFocusMsg struct{}
+
 type skillsScannedMsg struct {
 	names []string
 	count int
@@ -10,12 +10,22 @@ import (
 
 	"github.com/ZiaoLiu-1/pskill/internal/config"
 	"github.com/ZiaoLiu-1/pskill/internal/detector"
+	"github.com/ZiaoLiu-1/pskill/internal/installer"
+	"github.com/ZiaoLiu-1/pskill/internal/project"
+	"github.com/ZiaoLiu-1/pskill/internal/tokens"
 )
 
+type tokenUsageMsg struct {
+	usage  []tokens.Usage
+	budget project.Budget
+}
+
 type DashboardTab struct {
 	cfg        config.Config
 	clis       []detector.CLIInfo
 	skillCount int
+	usage      []tokens.Usage
+	budget     project.Budget
 	ready      bool
 }
 
@@ -24,9 +34,21 @@ func NewDashboardTab(cfg config.Config) Tab {
 }
 
 func (t *DashboardTab) Init() tea.Cmd {
-	return func() tea.Msg {
+	return tea.Batch(func() tea.Msg {
 		clis, _ := detector.DetectInstalledCLIs()
 		return clis
+	}, t.tokenUsageCmd())
+}
+
+func (t *DashboardTab) tokenUsageCmd() tea.Cmd {
+	targets := t.cfg.TargetCLIs
+	return func() tea.Msg {
+		wd, _ := os.Getwd()
+		msg := tokenUsageMsg{usage: installer.TokenUsage(targets, wd)}
+		if m, err := project.Load(wd); err == nil {
+			msg.budget = m.Budget
+		}
+		return msg
 	}
 }
 
@@ -37,6 +59,10 @@ func (t *DashboardTab) Update(msg tea.Msg) (Tab, tea.Cmd) {
 		t.ready = true
 	case skillsScannedMsg:
 		t.skillCount = m.count
+		return t, t.tokenUsageCmd()
+	case tokenUsageMsg:
+		t.usage = m.usage
+		t.budget = m.budget
 	}
 	return t, nil
 }
@@ -79,6 +105,31 @@ func (t *DashboardTab) View(width, height int) string {
 	b.WriteString(fmt.Sprintf("  Store path:      %s\n", dimStyle.Render(strings.Replace(t.cfg.StoreDir, home, "~", 1))))
 	b.WriteString(fmt.Sprintf("  Registry:        %s\n", dimStyle.Render(t.cfg.RegistryURL)))
 
+	// Context token usage
+	if len(t.usage) > 0 {
+		b.WriteString("\n")
+		b.WriteString(brightStyle.Render("  Context Tokens"))
+		b.WriteString(dimStyle.Render("  (always loaded / on activation)"))
+		b.WriteString("\n")
+		b.WriteString(dimStyle.Render("  " + strings.Repeat("─", 40)))
+		b.WriteString("\n")
+		for _, u := range t.usage {
+			always := successStyle.Render(fmt.Sprintf("%6d", u.Description))
+			if t.budget.AlwaysLoaded > 0 && u.Description > t.budget.AlwaysLoaded {
+				always = dangerStyle.Render(fmt.Sprintf("%6d", u.Description))
+			}
+			onUse := dimStyle.Render(fmt.Sprintf("%7d", u.Body))
+			if t.budget.Total > 0 && u.Total() > t.budget.Total {
+				onUse = dangerStyle.Render(fmt.Sprintf("%7d", u.Body))
+			}
+			b.WriteString(fmt.Sprintf("  %-10s %s / %s  %s\n", u.CLI, always, onUse, dimStyle.Render(fmt.Sprintf("%d skills", len(u.Skills)))))
+		}
+		if !t.budget.IsZero() {
+			b.WriteString(dimStyle.Render(fmt.Sprintf("  Budget: %d always / %d total (%s)", t.budget.AlwaysLoaded, t.budget.Total, budgetMode(t.budget))))
+			b.WriteString("\n")
+		}
+	}
+
 	// Quick actions
 	b.WriteString("\n")
 	b.WriteString(brightStyle.Render("  Quick Actions"))
@@ -129,6 +180,13 @@ func (t *DashboardTab) View(width, height int) string {
 	return content
 }
 
+func budgetMode(b project.Budget) string {
+	if b.Enforced() {
+		return "fail"
+	}
+	return "warn"
+}
+
 func (t *DashboardTab) Title() string { return "Dashboard" }
 func (t *DashboardTab) ShortHelp() []string {
 	return []string{
@@ -1,7 +1,10 @@
 package tui
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"sort"
 	"strings"
 
 	tea "github.com/charmbracelet/bubbletea"
@@ -14,8 +17,12 @@ import (
 )
 
 type searchResultsMsg struct {
+	seq    int
 	Local  []search.Result
+	Facets []search.Facet
 	Remote []registry.SkillResult
+	more   bool // the registry has further pages
+	next   bool // Remote continues the current results
 	err    error
 }
 
@@ -27,12 +34,22 @@ type DiscoverTab struct {
 	searching  bool
 	searchMode int // 0=keyword, 1=AI semantic
 	local      []search.Result
+	facets     []search.Facet
+	pill       int // focused filter pill, -1 for none
 	remote     []registry.SkillResult
 	errMsg     string
+	seq        int // identifies the latest search; older results are dropped
+	cancel     context.CancelFunc
+	stale      bool // a search was cancelled by a tab switch
+
+	// iter pages through keyword results as the cursor nears the end.
+	iter        *registry.Iterator
+	more        bool
+	pendingMore bool // the search in flight is a next page
 }
 
 func NewDiscoverTab(cfg config.Config) Tab {
-	return &DiscoverTab{cfg: cfg, typing: false}
+	return &DiscoverTab{cfg: cfg, typing: false, pill: -1}
 }
 
 func (t *DiscoverTab) Init() tea.Cmd { return nil }
@@ -68,6 +85,9 @@ func (t *DiscoverTab) Update(msg tea.Msg) (Tab, tea.Cmd) {
 				if t.cursor < len(t.allResults())-1 {
 					t.cursor++
 				}
+				if t.more && !t.searching && t.cursor >= len(t.allResults())-3 {
+					return t, t.moreCmd()
+				}
 			case "k", "up":
 				if t.cursor > 0 {
 					t.cursor--
@@ -77,19 +97,74 @@ func (t *DiscoverTab) Update(msg tea.Msg) (Tab, tea.Cmd) {
 				if t.query != "" {
 					return t, t.searchCmd()
 				}
+			case "f", "F":
+				if n := len(t.pills()); n > 0 {
+					switch {
+					case m.String() == "f":
+						t.pill = (t.pill + 1) % n
+					case t.pill < 0:
+						t.pill = n - 1
+					default:
+						t.pill = (t.pill + n - 1) % n
+					}
+				}
+			case " ":
+				pills := t.pills()
+				if t.pill >= 0 && t.pill < len(pills) {
+					p := pills[t.pill]
+					q := search.ParseQuery(t.query)
+					if p.excluded {
+						q = q.ToggleExclude(p.field, p.value)
+					} else {
+						q = q.Toggle(p.field, p.value)
+					}
+					t.query = q.String()
+					t.pill = -1
+					return t, t.searchCmd()
+				}
+			}
+		}
+
+	case tabBlurMsg:
+		if t.searching {
+			t.stale = true
+		}
+		t.stopSearch()
+
+	case tabFocusMsg:
+		if t.stale && t.query != "" {
+			t.stale = false
+			if t.pendingMore {
+				return t, t.moreCmd()
 			}
+			return t, t.searchCmd()
 		}
 
 	case searchResultsMsg:
+		if m.seq != t.seq {
+			return t, nil
+		}
 		t.searching = false
-		if m.err != nil {
+		t.cancel = nil
+		t.pendingMore = false
+		switch {
+		case m.err != nil:
 			t.errMsg = m.err.Error()
-		} else {
+		case m.next:
+			t.remote = append(t.remote, m.Remote...)
+			t.more = m.more
+			t.errMsg = ""
+		default:
 			t.local = m.Local
+			t.facets = m.Facets
+			t.pill = -1
 			t.remote = m.Remote
+			t.more = m.more
 			t.errMsg = ""
 		}
-		t.cursor = 0
+		if !m.next {
+			t.cursor = 0
+		}
 	}
 	return t, nil
 }
@@ -123,10 +198,16 @@ func (t *DiscoverTab) View(width, height int) string {
 	list.WriteString(dimStyle.Render("Mode: ") + modeStyle.Render(modeLabels[t.searchMode]))
 	list.WriteString(dimStyle.Render("  (m to toggle)"))
 	list.WriteString("\n")
+	if pills := t.renderPills(l.LeftW - 4); pills != "" {
+		list.WriteString(pills + "\n")
+	}
 
 	if t.searching {
 		list.WriteString(warningStyle.Render("Searching...") + "\n")
 	}
+	if note := registry.StaleNote(t.remote); note != "" {
+		list.WriteString(warningStyle.Render("Offline, remote results "+note) + "\n")
+	}
 	if t.errMsg != "" {
 		list.WriteString(dangerStyle.Render("Error: "+t.errMsg) + "\n")
 	}
@@ -142,7 +223,21 @@ func (t *DiscoverTab) View(width, height int) string {
 		list.WriteString(dimStyle.Render("  e.g. \"help me write better code reviews\"") + "\n")
 	}
 
-	for i, r := range results {
+	// Keep the cursor in view below the header lines.
+	visibleH := l.ContentH - strings.Count(list.String(), "\n") - 1
+	if visibleH < 1 {
+		visibleH = 1
+	}
+	start := 0
+	if t.cursor >= visibleH {
+		start = t.cursor - visibleH + 1
+	}
+	end := start + visibleH
+	if end > len(results) {
+		end = len(results)
+	}
+	for i := start; i < end; i++ {
+		r := results[i]
 		prefix := "  "
 		if i == t.cursor {
 			prefix = selectedStyle.Render("> ")
@@ -169,7 +264,11 @@ func (t *DiscoverTab) View(width, height int) string {
 			stars = dimStyle.Render(fmt.Sprintf("★%d", r.stars))
 		}
 
-		list.WriteString(fmt.Sprintf("%s%s %-26s %6s %6s  %s\n", prefix, badge, name, score, stars, dimStyle.Render(r.author)))
+		source := r.author
+		if r.registry != "" {
+			source = strings.TrimSpace(source + " @" + r.registry)
+		}
+		list.WriteString(fmt.Sprintf("%s%s %-26s %6s %6s  %s\n", prefix, badge, name, score, stars, dimStyle.Render(source)))
 	}
 
 	leftPane := activePaneStyle.Width(l.LeftW).Height(l.ContentH).Render(list.String())
@@ -187,8 +286,15 @@ func (t *DiscoverTab) View(width, height int) string {
 		preview.WriteString(sel.desc + "\n\n")
 		if sel.isLocal {
 			preview.WriteString(successStyle.Render("Installed locally") + "\n")
+			if sel.why != "" {
+				preview.WriteString(dimStyle.Render("Matched: ") + wordWrap(sel.why, l.RightW-6) + "\n")
+			}
 		} else {
-			preview.WriteString(dimStyle.Render("Available on registry") + "\n")
+			where := "registry"
+			if sel.registry != "" {
+				where = sel.registry
+			}
+			preview.WriteString(dimStyle.Render("Available on ") + brightStyle.Render(where) + "\n")
 		}
 		if sel.githubURL != "" {
 			preview.WriteString("\n" + dimStyle.Render("GitHub: "+sel.githubURL))
@@ -217,7 +323,123 @@ func (t *DiscoverTab) ShortHelp() []string {
 		helpEntry("/", "search"),
 		helpEntry("m", "mode"),
 		helpEntry("j/k", "nav"),
+		helpEntry("f/F", "filter"),
+		helpEntry("space", "apply"),
+	}
+}
+
+// filterPill is a facet value shown under the search bar. Active pills
+// are filters or exclusions in the query and toggle off; the others narrow
+// the results when applied.
+type filterPill struct {
+	field, value string
+	count        int
+	active       bool
+	excluded     bool // an active -field:value
+}
+
+// pillsPerField caps how many values of one facet get a pill.
+const pillsPerField = 4
+
+func (t *DiscoverTab) pills() []filterPill {
+	q := search.ParseQuery(t.query)
+	var out []filterPill
+	for _, f := range t.facets {
+		for _, term := range f.Terms {
+			if q.Has(f.Field, term.Value) {
+				out = append(out, filterPill{field: f.Field, value: term.Value, count: term.Count, active: true})
+			}
+		}
+	}
+	for _, field := range sortedKeys(q.Filters) {
+		for _, v := range q.Filters[field] {
+			if !pillsContain(out, field, v) {
+				out = append(out, filterPill{field: field, value: v, active: true})
+			}
+		}
+	}
+	for _, field := range sortedKeys(q.Exclude) {
+		for _, v := range q.Exclude[field] {
+			out = append(out, filterPill{field: field, value: v, active: true, excluded: true})
+		}
 	}
+	for _, f := range t.facets {
+		n := 0
+		for _, term := range f.Terms {
+			if n == pillsPerField {
+				break
+			}
+			if q.Has(f.Field, term.Value) || q.Excludes(f.Field, term.Value) {
+				continue
+			}
+			out = append(out, filterPill{field: f.Field, value: term.Value, count: term.Count})
+			n++
+		}
+	}
+	return out
+}
+
+// sortedKeys returns the fields of m in order, so pills keep their places
+// between renders.
+func sortedKeys(m map[string][]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func pillsContain(pills []filterPill, field, value string) bool {
+	for _, p := range pills {
+		if p.field == field && p.value == value && !p.excluded {
+			return true
+		}
+	}
+	return false
+}
+
+// renderPills lays the pills out in at most two lines of width.
+func (t *DiscoverTab) renderPills(width int) string {
+	pills := t.pills()
+	if len(pills) == 0 {
+		return ""
+	}
+	var lines []string
+	line, lineW := "", 0
+	for i, p := range pills {
+		label := p.field + ":" + p.value
+		if p.excluded {
+			label = "-" + label
+		}
+		if p.count > 0 {
+			label += fmt.Sprintf(" %d", p.count)
+		}
+		label = "[" + label + "]"
+		style := dimStyle
+		switch {
+		case i == t.pill:
+			style = selectedStyle
+		case p.excluded:
+			style = dangerStyle
+		case p.active:
+			style = successStyle
+		}
+		w := lipgloss.Width(label) + 1
+		if lineW+w > width && line != "" {
+			lines = append(lines, line)
+			line, lineW = "", 0
+			if len(lines) == 2 {
+				break
+			}
+		}
+		line += style.Render(label) + " "
+		lineW += w
+	}
+	if line != "" && len(lines) < 2 {
+		lines = append(lines, line)
+	}
+	return strings.Join(lines, "\n")
 }
 
 func (t *DiscoverTab) AcceptsTextInput() bool { return t.typing }
@@ -229,13 +451,15 @@ type discoveryResult struct {
 	score     float64
 	stars     int64
 	githubURL string
+	registry  string
+	why       string // why a local result matched
 	isLocal   bool
 }
 
 func (t *DiscoverTab) allResults() []discoveryResult {
 	out := make([]discoveryResult, 0, len(t.local)+len(t.remote))
 	for _, l := range t.local {
-		out = append(out, discoveryResult{name: l.Name, desc: l.Description, score: l.Score, isLocal: true})
+		out = append(out, discoveryResult{name: l.Name, desc: l.Description, score: l.Score, why: l.Why, isLocal: true})
 	}
 	for _, r := range t.remote {
 		out = append(out, discoveryResult{
@@ -245,6 +469,7 @@ func (t *DiscoverTab) allResults() []discoveryResult {
 			score:     r.Score,
 			stars:     r.Stars,
 			githubURL: r.GithubURL,
+			registry:  r.Registry,
 			isLocal:   false,
 		})
 	}
@@ -252,26 +477,76 @@ func (t *DiscoverTab) allResults() []discoveryResult {
 }
 
 func (t *DiscoverTab) searchCmd() tea.Cmd {
+	t.stopSearch()
 	t.searching = true
+	ctx, cancel := context.WithCancel(context.Background())
+	t.cancel = cancel
+	seq := t.seq
+	cfg := t.cfg
 	query := t.query
+	parsed := search.ParseQuery(query)
 	mode := t.searchMode
+	reg := registry.FromConfig(cfg)
+	t.iter = registry.SearchPages(reg, parsed.Text, 15)
+	t.more = false
+	it := t.iter
 	return func() tea.Msg {
-		// Always search local index
-		engine := search.NewEngine(t.cfg.IndexDir)
-		local, _ := engine.Search(query, 5)
+		// Always search local index; filters narrow it to installed
+		// skills, so the registries are left out.
+		engine := search.NewStoreEngine(cfg.IndexDir, cfg.StoreDir)
+		limit := 5
+		if parsed.Filtered() {
+			limit = 50
+		}
+		page, _ := engine.Query(query, limit, 0)
+		if parsed.Filtered() || strings.TrimSpace(parsed.Text) == "" {
+			return searchResultsMsg{seq: seq, Local: page.Results, Facets: page.Facets}
+		}
 
-		client := registry.NewClient(t.cfg.RegistryURL, t.cfg.CacheDir, t.cfg.RegistryAPIKey)
 		var remote []registry.SkillResult
+		var more bool
 		var err error
 
 		if mode == 1 {
 			// AI semantic search
-			remote, err = client.AISearch(query)
+			remote, err = reg.AISearch(ctx, parsed.Text)
 		} else {
 			// Keyword search
-			remote, _, err = client.Search(query, 15, 1, "stars")
+			remote, err = it.NextPage(ctx)
+			if errors.Is(err, registry.ErrDone) {
+				err = nil
+			}
+			more = it.HasNext()
 		}
 
-		return searchResultsMsg{Local: local, Remote: remote, err: err}
+		return searchResultsMsg{seq: seq, Local: page.Results, Facets: page.Facets, Remote: remote, more: more, err: err}
+	}
+}
+
+// moreCmd fetches the next page of keyword results.
+func (t *DiscoverTab) moreCmd() tea.Cmd {
+	t.stopSearch()
+	t.searching = true
+	t.pendingMore = true
+	ctx, cancel := context.WithCancel(context.Background())
+	t.cancel = cancel
+	seq := t.seq
+	it := t.iter
+	return func() tea.Msg {
+		remote, err := it.NextPage(ctx)
+		if errors.Is(err, registry.ErrDone) {
+			err = nil
+		}
+		return searchResultsMsg{seq: seq, Remote: remote, more: it.HasNext(), next: true, err: err}
+	}
+}
+
+// stopSearch cancels any search in flight and invalidates its result.
+func (t *DiscoverTab) stopSearch() {
+	if t.cancel != nil {
+		t.cancel()
+		t.cancel = nil
 	}
+	t.seq++
+	t.searching = false
 }
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"sort"
 	"strings"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
@@ -14,12 +15,14 @@ import (
 )
 
 type monitorMsg struct {
-	stats monitor.Aggregates
+	stats  monitor.Aggregates
+	trends map[string]monitor.Trend
 }
 
 type MonitorTab struct {
-	cfg   config.Config
-	stats monitor.Aggregates
+	cfg    config.Config
+	stats  monitor.Aggregates
+	trends map[string]monitor.Trend // star history by skill name
 }
 
 func NewMonitorTab(cfg config.Config) Tab {
@@ -50,6 +53,7 @@ func (t *MonitorTab) Update(msg tea.Msg) (Tab, tea.Cmd) {
 		}
 	case monitorMsg:
 		t.stats = m.stats
+		t.trends = m.trends
 	}
 	return t, nil
 }
@@ -98,7 +102,16 @@ func (t *MonitorTab) renderTopSkills(w, h int) string {
 
 	var b strings.Builder
 	for _, p := range pairs {
-		spark := components.Sparkline([]int64{1, 3, p.n / 2, p.n})
+		// Star history from trending snapshots; skills never seen in
+		// Trending have none.
+		spark := ""
+		if tr, ok := t.trends[p.name]; ok && len(tr.Series) > 1 {
+			series := tr.Series
+			if len(series) > 14 {
+				series = series[len(series)-14:]
+			}
+			spark = components.Sparkline(series) + dimStyle.Render(fmt.Sprintf(" %+d/wk", tr.Weekly))
+		}
 		b.WriteString(fmt.Sprintf("%-18s [%3d] %s\n", p.name, p.n, spark))
 	}
 	if len(pairs) == 0 {
@@ -171,6 +184,7 @@ func (t *MonitorTab) loadCmd() tea.Cmd {
 		}
 		defer tr.Close()
 		st, _ := tr.Stats()
-		return monitorMsg{stats: st}
+		trends, _ := tr.TrendsByName(time.Now())
+		return monitorMsg{stats: st, trends: trends}
 	}
 }
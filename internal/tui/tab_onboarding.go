@@ -11,6 +11,7 @@ import (
 	"github.com/ZiaoLiu-1/pskill/internal/config"
 	"github.com/ZiaoLiu-1/pskill/internal/detector"
 	"github.com/ZiaoLiu-1/pskill/internal/scanner"
+	"github.com/ZiaoLiu-1/pskill/internal/search"
 	"github.com/ZiaoLiu-1/pskill/internal/skill"
 	"github.com/ZiaoLiu-1/pskill/internal/store"
 )
@@ -415,11 +416,16 @@ func (t *OnboardingTab) scanCmd() tea.Cmd {
 	return func() tea.Msg {
 		inv, _ := scanner.ScanSystemSkills()
 		st := store.NewManager(cfg.StoreDir)
+		engine := search.NewStoreEngine(cfg.IndexDir, cfg.StoreDir)
 		names := make([]string, 0, len(inv.Skills))
+		imported := map[string]string{}
 		for _, sk := range inv.Skills {
-			_ = st.ImportSkill(sk)
+			if err := st.ImportSkill(sk, "pskill (tui)"); err == nil {
+				imported[sk.Name] = st.SkillDir(sk.Name)
+			}
 			names = append(names, sk.Name)
 		}
+		_, _ = engine.IndexSkillsByPath(imported)
 		return skillsImportedMsg{skills: inv.Skills, names: names}
 	}
 }
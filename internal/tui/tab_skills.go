@@ -1,18 +1,24 @@
 package tui
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"path/filepath"
 	"sort"
 	"strings"
+	"time"
 
 	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 
 	"github.com/ZiaoLiu-1/pskill/internal/config"
+	"github.com/ZiaoLiu-1/pskill/internal/diff"
+	"github.com/ZiaoLiu-1/pskill/internal/installer"
+	"github.com/ZiaoLiu-1/pskill/internal/project"
 	"github.com/ZiaoLiu-1/pskill/internal/skill"
+	"github.com/ZiaoLiu-1/pskill/internal/store"
 	"github.com/ZiaoLiu-1/pskill/internal/tui/components"
 )
 
@@ -26,13 +32,34 @@ type SkillsTab struct {
 	filtered  []skillEntry
 	viewport  viewport.Model
 	ready     bool
+	showDiff  bool
+}
+
+// skillDiffMsg carries the result of comparing a stored skill with its
+// project copy, previous version or upstream.
+type skillDiffMsg struct {
+	name  string
+	label string
+	files []diff.File
+	err   error
+}
+
+// skillsModifiedMsg reports which store entries differ from their recorded
+// hash.
+type skillsModifiedMsg struct {
+	modified map[string]bool
 }
 
 type skillEntry struct {
-	Name string
-	Desc string
-	CLI  string
-	Path string
+	Name     string
+	Desc     string
+	CLI      string
+	Path     string
+	Tags     []string
+	Category string
+	Linked   string // working dir of a linked dev skill
+	Meta     *store.Meta
+	Modified bool // store copy differs from the recorded hash
 }
 
 func NewSkillsTab(cfg config.Config) Tab {
@@ -91,15 +118,31 @@ func (t *SkillsTab) Update(msg tea.Msg) (Tab, tea.Cmd) {
 		case "enter":
 			if t.state == StateDetail {
 				t.state = StateList
+				t.showDiff = false
 			} else {
 				t.state = StateDetail
 				t.updateViewport()
 			}
+		case "d":
+			if len(t.filtered) == 0 {
+				break
+			}
+			if t.state == StateDetail && t.showDiff {
+				t.showDiff = false
+				t.updateViewport()
+				break
+			}
+			t.state = StateDetail
+			t.showDiff = true
+			t.viewport.SetContent(dimStyle.Render("Comparing " + t.filtered[t.cursor].Name + "..."))
+			t.viewport.GotoTop()
+			return t, t.diffCmd(t.filtered[t.cursor].Name)
 		case "g":
-			t.groupMode = (t.groupMode + 1) % 4
+			t.groupMode = (t.groupMode + 1) % 5
 			t.updateFiltered() // re-sort
 		case "esc":
 			t.state = StateList
+			t.showDiff = false
 			t.filter = ""
 			t.updateFiltered()
 		}
@@ -113,10 +156,108 @@ func (t *SkillsTab) Update(msg tea.Msg) (Tab, tea.Cmd) {
 	case skillsScannedMsg:
 		t.items = t.loadSkillEntries(m.names)
 		t.updateFiltered()
+		cmd = t.modifiedCmd(m.names)
+	case skillsModifiedMsg:
+		for _, list := range [][]skillEntry{t.items, t.filtered} {
+			for i := range list {
+				list[i].Modified = m.modified[list[i].Name]
+			}
+		}
+	case skillDiffMsg:
+		if t.showDiff && t.cursor < len(t.filtered) && t.filtered[t.cursor].Name == m.name {
+			t.viewport.SetContent(renderSkillDiff(m))
+			t.viewport.GotoTop()
+		}
 	}
 	return t, cmd
 }
 
+// modifiedCmd hashes the store entries of names off the UI path to find
+// those changed since install.
+func (t *SkillsTab) modifiedCmd(names []string) tea.Cmd {
+	storeDir := t.cfg.StoreDir
+	return func() tea.Msg {
+		st := store.NewManager(storeDir)
+		modified := map[string]bool{}
+		for _, name := range names {
+			if ok, _ := st.Modified(name); ok {
+				modified[name] = true
+			}
+		}
+		return skillsModifiedMsg{modified: modified}
+	}
+}
+
+// diffCmd compares a stored skill with, in order of preference, its copy in
+// the current project, its latest stored version, or its upstream source.
+func (t *SkillsTab) diffCmd(name string) tea.Cmd {
+	cfg := t.cfg
+	return func() tea.Msg {
+		st := store.NewManager(cfg.StoreDir)
+		wd, _ := os.Getwd()
+		targets := cfg.TargetCLIs
+		manifest, err := project.Load(wd)
+		if err == nil && len(manifest.TargetCLIs) > 0 {
+			targets = manifest.TargetCLIs
+		}
+		if dir, cli := installer.ProjectCopy(wd, targets, name); dir != "" {
+			expected, cleanup, err := installer.ExpectedProjectCopy(cfg, name, manifest.Vars)
+			if err != nil {
+				return skillDiffMsg{name: name, err: err}
+			}
+			defer cleanup()
+			files, err := diff.Dirs(expected, dir, "store", cli)
+			return skillDiffMsg{name: name, label: "store vs project (" + cli + ")", files: files, err: err}
+		}
+		if ids, _ := st.ListVersions(name); len(ids) > 0 {
+			prev, _ := st.VersionDir(name, ids[len(ids)-1])
+			files, err := diff.Dirs(prev, st.SkillDir(name), ids[len(ids)-1], "current")
+			return skillDiffMsg{name: name, label: ids[len(ids)-1] + " vs current", files: files, err: err}
+		}
+		up, cleanup, err := installer.FetchUpstream(context.Background(), cfg, name)
+		if err != nil {
+			return skillDiffMsg{name: name, err: err}
+		}
+		defer cleanup()
+		files, err := diff.Dirs(st.SkillDir(name), up, "store", "upstream")
+		return skillDiffMsg{name: name, label: "store vs upstream", files: files, err: err}
+	}
+}
+
+func renderSkillDiff(m skillDiffMsg) string {
+	var b strings.Builder
+	b.WriteString(titleStyle.Render("# "+m.name+" diff") + "\n")
+	if m.err != nil {
+		b.WriteString("\n" + dangerStyle.Render(m.err.Error()))
+		return b.String()
+	}
+	b.WriteString(dimStyle.Render(m.label) + "\n\n")
+	if len(m.files) == 0 {
+		b.WriteString(successStyle.Render("No differences."))
+		return b.String()
+	}
+	b.WriteString(dimStyle.Render(diff.Stat(m.files)) + "\n")
+	for _, f := range m.files {
+		for _, line := range strings.Split(strings.TrimSuffix(f.Unified, "\n"), "\n") {
+			switch {
+			case strings.HasPrefix(line, "+++"), strings.HasPrefix(line, "---"):
+				b.WriteString(brightStyle.Render(line))
+			case strings.HasPrefix(line, "@@"):
+				b.WriteString(selectedStyle.Render(line))
+			case strings.HasPrefix(line, "+"):
+				b.WriteString(successStyle.Render(line))
+			case strings.HasPrefix(line, "-"):
+				b.WriteString(dangerStyle.Render(line))
+			default:
+				b.WriteString(line)
+			}
+			b.WriteString("\n")
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
 func (t *SkillsTab) View(width, height int) string {
 	l := ComputeLayout(width, height, t.state == StateDetail)
 	if l.IsTooSmall {
@@ -141,7 +282,21 @@ func (t *SkillsTab) View(width, height int) string {
 		list.WriteString(dimStyle.Render("/ to filter"))
 	}
 	list.WriteString(dimStyle.Render(fmt.Sprintf("  %s  %d/%d", t.groupLabel(), len(t.filtered), len(t.items))))
-	list.WriteString("\n\n")
+	list.WriteString("\n")
+	if t.groupMode == 2 {
+		cats := t.categories()
+		selected := -1
+		if t.cursor < len(t.filtered) {
+			for i, c := range cats {
+				if c == t.filtered[t.cursor].Category {
+					selected = i
+				}
+			}
+		}
+		list.WriteString(components.CategoryPills(cats, selected))
+		list.WriteString("\n")
+	}
+	list.WriteString("\n")
 
 	if len(t.filtered) == 0 {
 		list.WriteString(dimStyle.Render("  No skills found.\n"))
@@ -149,6 +304,9 @@ func (t *SkillsTab) View(width, height int) string {
 	}
 
 	visibleHeight := l.ContentH - 2
+	if t.groupMode == 2 {
+		visibleHeight--
+	}
 	if visibleHeight < 1 {
 		visibleHeight = 1
 	}
@@ -186,7 +344,15 @@ func (t *SkillsTab) View(width, height int) string {
 			name = brightStyle.Render(name)
 		}
 
-		list.WriteString(fmt.Sprintf("%s%s %-30s %s %s\n", prefix, badge, name, dimStyle.Render(desc), ""))
+		group := ""
+		if t.groupMode == 2 {
+			group = dimStyle.Render("[" + entry.Category + "]")
+		}
+		if entry.Linked != "" {
+			group += " " + warningStyle.Render("dev")
+		}
+
+		list.WriteString(fmt.Sprintf("%s%s %-30s %s %s\n", prefix, badge, name, dimStyle.Render(desc), group))
 	}
 
 	leftPane := activePaneStyle.Width(l.LeftW).Height(l.ContentH).Render(list.String())
@@ -205,8 +371,15 @@ func (t *SkillsTab) View(width, height int) string {
 		selected := t.filtered[t.cursor]
 		detail.WriteString(titleStyle.Render("# "+selected.Name) + "\n\n")
 		detail.WriteString(dimStyle.Render("CLI: ") + brightStyle.Render(selected.CLI) + "\n")
-		detail.WriteString(dimStyle.Render("Path: ") + dimStyle.Render(selected.Path) + "\n\n")
-		detail.WriteString(dimStyle.Render("Press Enter to view full detail"))
+		detail.WriteString(dimStyle.Render("Path: ") + dimStyle.Render(selected.Path) + "\n")
+		if selected.Linked != "" {
+			detail.WriteString(dimStyle.Render("Linked: ") + warningStyle.Render(selected.Linked) + "\n")
+		}
+		if len(selected.Tags) > 0 {
+			detail.WriteString(dimStyle.Render("Tags: ") + brightStyle.Render(strings.Join(selected.Tags, ", ")) + "\n")
+		}
+		detail.WriteString(provenance(selected))
+		detail.WriteString("\n" + dimStyle.Render("Press Enter to view full detail, d to diff"))
 	} else {
 		detail.WriteString(dimStyle.Render("No skill selected"))
 	}
@@ -215,12 +388,46 @@ func (t *SkillsTab) View(width, height int) string {
 	return lipgloss.JoinHorizontal(lipgloss.Top, leftPane, rightPane)
 }
 
+// provenance renders where a stored skill came from, one field per line.
+func provenance(e skillEntry) string {
+	m := e.Meta
+	if m == nil {
+		return dimStyle.Render("Source: ") + warningStyle.Render("unknown") + "\n"
+	}
+	var b strings.Builder
+	line := func(label, value string) {
+		if value != "" {
+			b.WriteString(dimStyle.Render(label+": ") + brightStyle.Render(value) + "\n")
+		}
+	}
+	line("Source", strings.TrimSpace(m.SourceType+" "+m.Registry))
+	if m.SourceType != store.SourceLink {
+		line("From", m.SourceURL)
+	}
+	line("Version", m.Version)
+	if m.Commit != "" {
+		line("Commit", installer.ShortHash(m.Commit))
+	}
+	installed := m.InstalledAt.Local().Format("2006-01-02")
+	if m.InstalledBy != "" {
+		installed += " by " + m.InstalledBy
+	}
+	line("Installed", installed)
+	if m.UpdatedAt.Sub(m.InstalledAt) > time.Minute {
+		line("Updated", m.UpdatedAt.Local().Format("2006-01-02"))
+	}
+	if e.Modified && e.Linked == "" {
+		b.WriteString(warningStyle.Render("Modified in the store since install") + "\n")
+	}
+	return b.String()
+}
+
 func (t *SkillsTab) updateFiltered() {
 	q := strings.ToLower(t.filter)
 	var filtered []skillEntry
 
 	for _, s := range t.items {
-		if strings.TrimSpace(t.filter) == "" || strings.Contains(strings.ToLower(s.Name), q) || strings.Contains(strings.ToLower(s.Desc), q) {
+		if strings.TrimSpace(t.filter) == "" || strings.Contains(strings.ToLower(s.Name), q) || strings.Contains(strings.ToLower(s.Desc), q) || hasTag(s.Tags, q) {
 			filtered = append(filtered, s)
 		}
 	}
@@ -233,6 +440,13 @@ func (t *SkillsTab) updateFiltered() {
 			}
 			return filtered[i].Name < filtered[j].Name
 		})
+	case 2:
+		sort.Slice(filtered, func(i, j int) bool {
+			if filtered[i].Category != filtered[j].Category {
+				return categoryLess(filtered[i].Category, filtered[j].Category)
+			}
+			return filtered[i].Name < filtered[j].Name
+		})
 	default:
 		sort.Slice(filtered, func(i, j int) bool {
 			return filtered[i].Name < filtered[j].Name
@@ -253,7 +467,12 @@ func (t *SkillsTab) updateViewport() {
 	var content strings.Builder
 	content.WriteString(titleStyle.Render("# "+selected.Name) + "\n\n")
 	content.WriteString(dimStyle.Render("CLI: ") + brightStyle.Render(selected.CLI) + "\n")
-	content.WriteString(dimStyle.Render("Path: ") + dimStyle.Render(selected.Path) + "\n\n")
+	content.WriteString(dimStyle.Render("Path: ") + dimStyle.Render(selected.Path) + "\n")
+	if selected.Linked != "" {
+		content.WriteString(dimStyle.Render("Linked: ") + warningStyle.Render(selected.Linked) + "\n")
+	}
+	content.WriteString(provenance(selected))
+	content.WriteString("\n")
 
 	mdPath := filepath.Join(t.cfg.StoreDir, selected.Name, "SKILL.md")
 	if raw, err := os.ReadFile(mdPath); err == nil {
@@ -281,6 +500,7 @@ func (t *SkillsTab) ShortHelp() []string {
 		helpEntry("j/k", "nav"),
 		helpEntry("g", "group"),
 		helpEntry("enter", "detail"),
+		helpEntry("d", "diff"),
 	}
 }
 
@@ -293,8 +513,10 @@ func (t *SkillsTab) groupLabel() string {
 	case 1:
 		return "by-cli"
 	case 2:
-		return "by-activity"
+		return "by-category"
 	case 3:
+		return "by-activity"
+	case 4:
 		return "by-recency"
 	default:
 		return "flat"
@@ -303,13 +525,20 @@ func (t *SkillsTab) groupLabel() string {
 
 func (t *SkillsTab) loadSkillEntries(names []string) []skillEntry {
 	entries := make([]skillEntry, 0, len(names))
+	st := store.NewManager(t.cfg.StoreDir)
 	for _, name := range names {
-		entry := skillEntry{Name: name, CLI: "store"}
+		entry := skillEntry{Name: name, CLI: "store", Category: "other"}
+		entry.Linked, _ = st.LinkTarget(name)
+		if meta, err := st.ReadMeta(name); err == nil {
+			entry.Meta = &meta
+		}
 		mdPath := filepath.Join(t.cfg.StoreDir, name, "SKILL.md")
 		if sk, err := skill.ParseFile(mdPath, ""); err == nil {
 			entry.Desc = sk.Description
 			entry.CLI = sk.SourceCLI
 			entry.Path = sk.Path
+			entry.Tags = sk.Tags
+			entry.Category = skill.PrimaryCategory(sk.Tags)
 			if entry.CLI == "" {
 				entry.CLI = "store"
 			}
@@ -318,3 +547,36 @@ func (t *SkillsTab) loadSkillEntries(names []string) []skillEntry {
 	}
 	return entries
 }
+
+// categories returns the distinct categories of the loaded skills, "other" last.
+func (t *SkillsTab) categories() []string {
+	seen := map[string]bool{}
+	out := []string{}
+	for _, it := range t.items {
+		if !seen[it.Category] {
+			seen[it.Category] = true
+			out = append(out, it.Category)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return categoryLess(out[i], out[j]) })
+	return out
+}
+
+func categoryLess(a, b string) bool {
+	if a == "other" || b == "other" {
+		return b == "other" && a != "other"
+	}
+	return a < b
+}
+
+func hasTag(tags []string, q string) bool {
+	if q == "" {
+		return false
+	}
+	for _, tag := range tags {
+		if strings.Contains(tag, q) {
+			return true
+		}
+	}
+	return false
+}
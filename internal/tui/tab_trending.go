@@ -1,6 +1,8 @@
 package tui
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"strings"
 	"time"
@@ -10,25 +12,36 @@ import (
 
 	"github.com/ZiaoLiu-1/pskill/internal/config"
 	"github.com/ZiaoLiu-1/pskill/internal/installer"
+	"github.com/ZiaoLiu-1/pskill/internal/monitor"
 	"github.com/ZiaoLiu-1/pskill/internal/registry"
+	"github.com/ZiaoLiu-1/pskill/internal/security"
+	"github.com/ZiaoLiu-1/pskill/internal/store"
+	"github.com/ZiaoLiu-1/pskill/internal/tui/components"
 )
 
 // --- messages ---
 
 type trendingMsg struct {
-	items []registry.SkillResult
-	total int
-	err   error
+	seq    int
+	items  []registry.SkillResult
+	total  int
+	more   bool
+	trends map[string]monitor.Trend // star history from stats.db, by monitor.SnapshotKey
+	err    error
 }
 
+// trendingRetryMsg ticks the rate-limit countdown once a second.
+type trendingRetryMsg struct{ seq int }
+
 type trendingInstallDoneMsg struct {
 	result *installer.Result
 	err    error
 }
 
 type trendingUninstallDoneMsg struct {
-	name string
-	err  error
+	name     string
+	rejected bool // a quarantined install was deleted
+	err      error
 }
 
 // --- tab state ---
@@ -39,6 +52,7 @@ const (
 	trendingBrowse   trendingState = iota // normal list browsing
 	trendingConfirm                       // confirmation dialog
 	trendingWorking                       // install/uninstall in progress
+	trendingReview                        // scan findings of a quarantined install
 )
 
 type TrendingTab struct {
@@ -46,15 +60,34 @@ type TrendingTab struct {
 	items    []registry.SkillResult
 	total    int
 	cursor   int
-	page     int
 	pageSize int
 	loading  bool
 	state    trendingState
 	errMsg   string
+
+	// iter pages through the listing as the cursor nears the end. reset is
+	// set until the first page of a new iterator replaces the old items.
+	iter  *registry.Iterator
+	reset bool
+	more  bool
+
+	trends map[string]monitor.Trend
+	rising bool // order by stars gained this week instead of total stars
+
+	// review is the quarantined install shown in trendingReview.
+	review     *installer.Result
+	reviewItem registry.SkillResult
+
+	// seq identifies the latest load; results and ticks from older ones are
+	// dropped. cancel aborts the load in flight.
+	seq     int
+	cancel  context.CancelFunc
+	stale   bool      // a load was cancelled by a tab switch
+	retryAt time.Time // set while waiting out a 429
 }
 
 func NewTrendingTab(cfg config.Config) Tab {
-	return &TrendingTab{cfg: cfg, page: 1, pageSize: 20}
+	return &TrendingTab{cfg: cfg, pageSize: 20}
 }
 
 func (t *TrendingTab) Init() tea.Cmd { return t.loadCmd() }
@@ -68,12 +101,26 @@ func (t *TrendingTab) Update(msg tea.Msg) (Tab, tea.Cmd) {
 			switch m.String() {
 			case "y", "Y", "enter":
 				t.state = trendingWorking
-				return t, t.installCmd(t.items[t.cursor])
+				t.reviewItem = t.listed()[t.cursor]
+				return t, t.installCmd(t.reviewItem, false)
 			case "n", "N", "esc", "q":
 				t.state = trendingBrowse
 			}
 			return t, nil
 
+		case trendingReview:
+			switch m.String() {
+			case "a", "A":
+				t.state = trendingWorking
+				return t, t.installCmd(t.reviewItem, true)
+			case "d", "D":
+				t.state = trendingWorking
+				return t, t.rejectCmd(t.reviewItem.Name)
+			case "esc", "q":
+				t.state, t.review = trendingBrowse, nil
+			}
+			return t, nil
+
 		case trendingWorking:
 			// Block all keys except quit while working
 			return t, nil
@@ -84,24 +131,19 @@ func (t *TrendingTab) Update(msg tea.Msg) (Tab, tea.Cmd) {
 				if t.cursor < len(t.items)-1 {
 					t.cursor++
 				}
+				if t.nearEnd() {
+					return t, t.loadCmd()
+				}
 			case "k", "up":
 				if t.cursor > 0 {
 					t.cursor--
 				}
 			case "r":
+				t.iter = nil
 				return t, t.loadCmd()
-			case ">", ".":
-				if t.page*t.pageSize < t.total {
-					t.page++
-					t.cursor = 0
-					return t, t.loadCmd()
-				}
-			case "<", ",":
-				if t.page > 1 {
-					t.page--
-					t.cursor = 0
-					return t, t.loadCmd()
-				}
+			case "s":
+				t.rising = !t.rising
+				t.cursor = 0
 			case "enter":
 				if len(t.items) > 0 && t.cursor < len(t.items) {
 					t.state = trendingConfirm
@@ -109,18 +151,56 @@ func (t *TrendingTab) Update(msg tea.Msg) (Tab, tea.Cmd) {
 			case "x":
 				if len(t.items) > 0 && t.cursor < len(t.items) {
 					t.state = trendingWorking
-					return t, t.uninstallCmd(t.items[t.cursor].Name)
+					return t, t.uninstallCmd(t.listed()[t.cursor].Name)
 				}
 			}
 		}
 
+	case tabBlurMsg:
+		if t.loading || !t.retryAt.IsZero() {
+			t.stale = true
+		}
+		t.stopLoad()
+
+	case tabFocusMsg:
+		if t.stale || (len(t.items) == 0 && t.errMsg == "") {
+			t.stale = false
+			return t, t.loadCmd()
+		}
+
+	case trendingRetryMsg:
+		if m.seq != t.seq || t.retryAt.IsZero() {
+			return t, nil
+		}
+		if !time.Now().Before(t.retryAt) {
+			return t, t.loadCmd()
+		}
+		return t, t.retryTick()
+
 	case trendingMsg:
+		if m.seq != t.seq {
+			return t, nil
+		}
 		t.loading = false
+		t.cancel = nil
+		var rl *registry.RateLimitError
+		if errors.As(m.err, &rl) {
+			t.errMsg = ""
+			t.retryAt = time.Now().Add(rl.RetryAfter)
+			return t, t.retryTick()
+		}
 		if m.err != nil {
 			t.errMsg = m.err.Error()
 		} else {
-			t.items = m.items
+			if t.reset {
+				t.items, t.cursor, t.reset = nil, 0, false
+			}
+			t.items = append(t.items, m.items...)
+			if m.trends != nil {
+				t.trends = m.trends
+			}
 			t.total = m.total
+			t.more = m.more
 			t.errMsg = ""
 		}
 
@@ -133,16 +213,25 @@ func (t *TrendingTab) Update(msg tea.Msg) (Tab, tea.Cmd) {
 			}
 		}
 		t.errMsg = ""
+		if m.result.Quarantined != nil {
+			t.state, t.review = trendingReview, m.result
+			return t, func() tea.Msg { return statusMsg{text: "Quarantined " + m.result.SkillName + ": " + m.result.Quarantined.Summary()} }
+		}
+		t.review = nil
 		linked := strings.Join(m.result.LinkedCLIs, ", ")
+		status := "Installed " + m.result.SkillName
+		if len(m.result.Warnings) > 0 {
+			status += " (warn: " + strings.Join(m.result.Warnings, "; ") + ")"
+		}
 		return t, tea.Batch(
-			func() tea.Msg { return statusMsg{text: "Installed " + m.result.SkillName} },
+			func() tea.Msg { return statusMsg{text: status} },
 			func() tea.Msg {
 				return toastMsg{text: "Installed " + m.result.SkillName + " → " + linked, duration: 3 * time.Second}
 			},
 		)
 
 	case trendingUninstallDoneMsg:
-		t.state = trendingBrowse
+		t.state, t.review = trendingBrowse, nil
 		if m.err != nil {
 			t.errMsg = m.err.Error()
 			return t, func() tea.Msg {
@@ -150,6 +239,9 @@ func (t *TrendingTab) Update(msg tea.Msg) (Tab, tea.Cmd) {
 			}
 		}
 		t.errMsg = ""
+		if m.rejected {
+			return t, func() tea.Msg { return statusMsg{text: "Deleted quarantined " + m.name} }
+		}
 		return t, tea.Batch(
 			func() tea.Msg { return statusMsg{text: "Uninstalled " + m.name + " from project"} },
 			func() tea.Msg {
@@ -179,12 +271,15 @@ func (t *TrendingTab) View(width, height int) string {
 
 func (t *TrendingTab) renderList(l Layout) string {
 	var b strings.Builder
-	totalPages := (t.total + t.pageSize - 1) / t.pageSize
-	if totalPages == 0 {
-		totalPages = 1
+	order := "★ stars"
+	if t.rising {
+		order = "▲ rising this week"
 	}
 	b.WriteString(brightStyle.Render("Trending Skills"))
-	b.WriteString(dimStyle.Render(fmt.Sprintf("  ★ stars  %d total  page %d/%d", t.total, t.page, totalPages)))
+	b.WriteString(dimStyle.Render(fmt.Sprintf("  %s  %d of %d", order, len(t.items), t.total)))
+	if note := registry.StaleNote(t.items); note != "" {
+		b.WriteString(warningStyle.Render("  offline, " + note))
+	}
 	b.WriteString("\n\n")
 
 	if t.loading {
@@ -193,6 +288,13 @@ func (t *TrendingTab) renderList(l Layout) string {
 	if t.state == trendingWorking {
 		b.WriteString(warningStyle.Render("  Working...") + "\n")
 	}
+	if !t.retryAt.IsZero() {
+		wait := time.Until(t.retryAt).Round(time.Second)
+		if wait < 0 {
+			wait = 0
+		}
+		b.WriteString(warningStyle.Render(fmt.Sprintf("  Rate limited, retry in %ds", int(wait.Seconds()))) + "\n")
+	}
 	if t.errMsg != "" {
 		b.WriteString(dangerStyle.Render("  "+t.errMsg) + "\n")
 	}
@@ -214,15 +316,15 @@ func (t *TrendingTab) renderList(l Layout) string {
 		end = len(t.items)
 	}
 
+	items := t.listed()
 	for i := start; i < end; i++ {
-		it := t.items[i]
+		it := items[i]
 		prefix := "  "
 		if i == t.cursor {
 			prefix = selectedStyle.Render("> ")
 		}
 
-		globalRank := (t.page-1)*t.pageSize + i + 1
-		rank := warningStyle.Render(fmt.Sprintf("#%-3d", globalRank))
+		rank := warningStyle.Render(fmt.Sprintf("#%-3d", i+1))
 		name := it.Name
 		if i == t.cursor {
 			name = selectedStyle.Render(name)
@@ -231,12 +333,16 @@ func (t *TrendingTab) renderList(l Layout) string {
 		}
 
 		stars := dimStyle.Render(fmt.Sprintf("★%d", it.Stars))
+		delta := ""
+		if tr, ok := t.trends[monitor.SnapshotKey(it)]; ok && tr.Weekly != 0 {
+			delta = successStyle.Render(fmt.Sprintf("%+d", tr.Weekly))
+		}
 		author := dimStyle.Render(it.Author)
 
-		b.WriteString(fmt.Sprintf("%s%s %-28s %8s  %s\n", prefix, rank, name, stars, author))
+		b.WriteString(fmt.Sprintf("%s%s %-28s %8s %6s  %s\n", prefix, rank, name, stars, delta, author))
 	}
 
-	if len(t.items) == 0 && !t.loading && t.errMsg == "" {
+	if len(t.items) == 0 && !t.loading && t.errMsg == "" && t.retryAt.IsZero() {
 		b.WriteString(dimStyle.Render("  No trending data.") + "\n")
 	}
 
@@ -252,11 +358,14 @@ func (t *TrendingTab) renderDetail(l Layout) string {
 		)
 	}
 
-	it := t.items[t.cursor]
+	it := t.listed()[t.cursor]
 
 	if t.state == trendingConfirm {
 		return t.renderConfirm(l, it)
 	}
+	if t.state == trendingReview && t.review != nil {
+		return t.renderReview(l)
+	}
 
 	return t.renderSkillDetail(l, it)
 }
@@ -276,7 +385,15 @@ func (t *TrendingTab) renderSkillDetail(l Layout, it registry.SkillResult) strin
 	}
 	b.WriteString("\n\n")
 
-	b.WriteString(dimStyle.Render("Stars: ") + warningStyle.Render(fmt.Sprintf("★ %d", it.Stars)) + "\n\n")
+	b.WriteString(dimStyle.Render("Stars: ") + warningStyle.Render(fmt.Sprintf("★ %d", it.Stars)) + "\n")
+	if tr, ok := t.trends[monitor.SnapshotKey(it)]; ok && len(tr.Series) > 1 {
+		b.WriteString(dimStyle.Render("Trend: ") + successStyle.Render(components.Sparkline(tr.Series)) +
+			dimStyle.Render(fmt.Sprintf("  %+d today  %+d this week", tr.Daily, tr.Weekly)) + "\n")
+	}
+	if it.Registry != "" {
+		b.WriteString(dimStyle.Render("Registry: ") + brightStyle.Render(it.Registry) + "\n")
+	}
+	b.WriteString("\n")
 
 	b.WriteString(successStyle.Render("enter") + dimStyle.Render(" install to project") + "\n")
 	b.WriteString(dangerStyle.Render("x") + dimStyle.Render("     uninstall from project") + "\n")
@@ -339,6 +456,43 @@ func (t *TrendingTab) renderConfirm(l Layout, it registry.SkillResult) string {
 	return paneStyle.Width(l.RightW).Height(l.ContentH).Render(b.String())
 }
 
+// renderReview lists the scan findings that kept an install in quarantine.
+func (t *TrendingTab) renderReview(l Layout) string {
+	w := l.RightW - 6
+	if w < 20 {
+		w = 20
+	}
+	sep := dimStyle.Render(strings.Repeat("─", w))
+	rep := t.review.Quarantined
+
+	var b strings.Builder
+	b.WriteString("\n")
+	b.WriteString(dangerStyle.Render("  QUARANTINED") + "\n")
+	b.WriteString("  " + sep + "\n\n")
+	b.WriteString("  " + brightStyle.Render(t.review.SkillName) + dimStyle.Render("  "+rep.Summary()) + "\n\n")
+	for _, f := range rep.Findings {
+		style := dimStyle
+		switch f.Severity {
+		case security.High:
+			style = dangerStyle
+		case security.Medium:
+			style = warningStyle
+		}
+		line := f.String()
+		if len(line) > w {
+			line = line[:w-1] + "…"
+		}
+		b.WriteString("  " + style.Render(line) + "\n")
+	}
+	b.WriteString("\n  " + dimStyle.Render("Held in "+t.review.StorePath) + "\n")
+	b.WriteString("  " + sep + "\n\n")
+	b.WriteString("  " + warningStyle.Render("a") + dimStyle.Render(" approve and install  ") +
+		dangerStyle.Render("d") + dimStyle.Render(" delete  ") +
+		dimStyle.Render("esc keep for later") + "\n")
+
+	return paneStyle.Width(l.RightW).Height(l.ContentH).Render(b.String())
+}
+
 func (t *TrendingTab) Title() string { return "Trending" }
 
 func (t *TrendingTab) ShortHelp() []string {
@@ -348,36 +502,107 @@ func (t *TrendingTab) ShortHelp() []string {
 			helpEntry("esc", "cancel"),
 		}
 	}
+	if t.state == trendingReview {
+		return []string{
+			helpEntry("a", "approve"),
+			helpEntry("d", "delete"),
+			helpEntry("esc", "keep quarantined"),
+		}
+	}
 	return []string{
 		helpEntry("j/k", "nav"),
 		helpEntry("enter", "install"),
 		helpEntry("x", "uninstall"),
-		helpEntry("</>", "page"),
+		helpEntry("s", "stars/rising"),
 		helpEntry("r", "refresh"),
 	}
 }
 
 func (t *TrendingTab) AcceptsTextInput() bool { return false }
 
+// loadCmd fetches the next page, starting a new listing when there is no
+// iterator yet.
 func (t *TrendingTab) loadCmd() tea.Cmd {
+	t.stopLoad()
+	if t.iter == nil {
+		t.iter = registry.TrendingPages(registry.FromConfig(t.cfg), t.pageSize)
+		t.reset = true
+	}
 	t.loading = true
-	page := t.page
-	limit := t.pageSize
+	ctx, cancel := context.WithCancel(context.Background())
+	t.cancel = cancel
+	seq := t.seq
+	it := t.iter
+	dbPath := t.cfg.StatsDB
 	return func() tea.Msg {
-		client := registry.NewClient(t.cfg.RegistryURL, t.cfg.CacheDir, t.cfg.RegistryAPIKey)
-		items, total, err := client.Trending(limit, page)
-		return trendingMsg{items: items, total: total, err: err}
+		items, err := it.NextPage(ctx)
+		if errors.Is(err, registry.ErrDone) {
+			err = nil
+		}
+		msg := trendingMsg{seq: seq, items: items, total: it.Total(), more: it.HasNext(), err: err}
+		if err == nil {
+			msg.trends, _ = monitor.SnapshotTrending(dbPath, items)
+		}
+		return msg
+	}
+}
+
+// listed returns the items in display order.
+func (t *TrendingTab) listed() []registry.SkillResult {
+	if !t.rising {
+		return t.items
+	}
+	out := append([]registry.SkillResult(nil), t.items...)
+	monitor.SortRising(out, t.trends)
+	return out
+}
+
+// nearEnd reports whether the cursor is close enough to the last loaded
+// item to fetch the next page.
+func (t *TrendingTab) nearEnd() bool {
+	return t.more && !t.loading && t.retryAt.IsZero() && t.cursor >= len(t.items)-5
+}
+
+// stopLoad cancels any load in flight and invalidates pending results and
+// retry ticks.
+func (t *TrendingTab) stopLoad() {
+	if t.cancel != nil {
+		t.cancel()
+		t.cancel = nil
 	}
+	t.seq++
+	t.loading = false
+	t.retryAt = time.Time{}
+}
+
+func (t *TrendingTab) retryTick() tea.Cmd {
+	seq := t.seq
+	return tea.Tick(time.Second, func(time.Time) tea.Msg { return trendingRetryMsg{seq: seq} })
 }
 
-func (t *TrendingTab) installCmd(item registry.SkillResult) tea.Cmd {
+// installCmd installs item, first approving its quarantined copy when
+// approve is set.
+func (t *TrendingTab) installCmd(item registry.SkillResult, approve bool) tea.Cmd {
 	cfg := t.cfg
 	return func() tea.Msg {
-		result, err := installer.InstallFromRegistryResult(cfg, item, true)
+		if approve {
+			if err := installer.Approve(cfg, item.Name); err != nil {
+				return trendingInstallDoneMsg{err: err}
+			}
+		}
+		result, err := installer.InstallFromRegistryResult(context.Background(), cfg, item, true)
 		return trendingInstallDoneMsg{result: result, err: err}
 	}
 }
 
+func (t *TrendingTab) rejectCmd(name string) tea.Cmd {
+	cfg := t.cfg
+	return func() tea.Msg {
+		err := store.NewManager(cfg.StoreDir).RemoveQuarantined(name)
+		return trendingUninstallDoneMsg{name: name, rejected: true, err: err}
+	}
+}
+
 func (t *TrendingTab) uninstallCmd(name string) tea.Cmd {
 	cfg := t.cfg
 	return func() tea.Msg {